@@ -22,6 +22,8 @@ var (
 		NewVersionCommand(),
 		NewRunCommand(),
 		NewMigrateCommand(),
+		NewRunesCommand(),
+		NewRollbackCommand(),
 	}
 )
 
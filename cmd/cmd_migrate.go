@@ -3,7 +3,6 @@ package cmd
 import (
 	"github.com/gaze-network/indexer-network/cmd/migrate"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/spf13/cobra"
 )
 
@@ -15,6 +14,7 @@ func NewMigrateCommand() *cobra.Command {
 	cmd.AddCommand(
 		migrate.NewMigrateUpCommand(),
 		migrate.NewMigrateDownCommand(),
+		migrate.NewMigrateStatusCommand(),
 	)
 	return cmd
 }
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/cmd/runes"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/internal/config"
+	"github.com/spf13/cobra"
+)
+
+type rollbackCmdOptions struct {
+	Module   string
+	ToHeight int64
+	DryRun   bool
+	Yes      bool
+}
+
+func NewRollbackCommand() *cobra.Command {
+	opts := &rollbackCmdOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "rollback",
+		Short:   "Revert the indexed data of a module to a block height",
+		Long:    "Revert the indexed data of a module to a block height, so that the indexer re-indexes from the next block. The indexer of the module must be stopped.",
+		Example: `gaze rollback --module runes --to-height 840000 --dry-run`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return rollbackHandler(opts, cmd)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Module, "module", "", "Module to rollback, E.g. `runes` (required)")
+	flags.Int64Var(&opts.ToHeight, "to-height", -1, "Block height to rollback to. Data after this height is reverted (required)")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "Show the number of rows that would be reverted per table without modifying any data")
+	flags.BoolVar(&opts.Yes, "yes", false, "Confirm rollback without prompt")
+	_ = cmd.MarkFlagRequired("module")
+	_ = cmd.MarkFlagRequired("to-height")
+
+	return cmd
+}
+
+func rollbackHandler(opts *rollbackCmdOptions, cmd *cobra.Command) error {
+	conf := config.Load()
+	if !conf.Network.IsSupported() {
+		return errors.Wrapf(errs.Unsupported, "%q network is not supported", conf.Network.String())
+	}
+	if opts.ToHeight < 0 {
+		return errors.Wrap(errs.InvalidArgument, "--to-height must be non-negative")
+	}
+
+	switch strings.ToLower(opts.Module) {
+	case "runes":
+		return errors.WithStack(runes.Rollback(cmd, conf, runes.RollbackOptions{
+			ToHeight: opts.ToHeight,
+			DryRun:   opts.DryRun,
+			Yes:      opts.Yes,
+		}))
+	default:
+		return errors.Wrapf(errs.Unsupported, "rollback of %q module is not supported", opts.Module)
+	}
+}
@@ -151,7 +151,10 @@ func runHandler(cmd *cobra.Command, _ []string) error {
 		})
 		app.
 			Use(favicon.New()).
-			Use(cors.New()).
+			Use(cors.New(cors.Config{
+				// expose the block that runes API responses are served at to browser clients
+				ExposeHeaders: "X-Block-Height,X-Block-Hash",
+			})).
 			Use(requestid.New()).
 			Use(requestcontext.New(
 				requestcontext.WithRequestId(),
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/gaze-network/indexer-network/cmd/runes"
+	"github.com/spf13/cobra"
+)
+
+func NewRunesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "runes",
+		Short: "Runes module maintenance tools",
+	}
+	cmd.AddCommand(
+		runes.NewCheckCommand(),
+		runes.NewVerifyHashesCommand(),
+		runes.NewSnapshotCommand(),
+		runes.NewHoldersCommand(),
+		runes.NewBackfillValuesCommand(),
+	)
+	return cmd
+}
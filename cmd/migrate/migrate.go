@@ -1,11 +1,105 @@
 package migrate
 
-import "net/url"
+import (
+	"context"
+	"io/fs"
+	"net/url"
+	"strings"
 
-const (
-	runesMigrationSource = "modules/runes/database/postgresql/migrations"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/internal/config"
+	"github.com/gaze-network/indexer-network/internal/postgres"
+	nodesalemigrations "github.com/gaze-network/indexer-network/modules/nodesale/database/postgresql/migrations"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	runesmigrations "github.com/gaze-network/indexer-network/modules/runes/database/postgresql/migrations"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/samber/lo"
+	"github.com/spf13/pflag"
 )
 
+type migrationModule struct {
+	Name            string // Display name of the module
+	Flag            string // Name of the command flag selecting the module
+	Source          fs.FS
+	MigrationTable  string
+	RequiredVersion uint                                // Schema version required by this build. 0 if the module doesn't track its schema version.
+	Schema          func(modules config.Modules) string // Returns the Postgres schema configured for the module
+}
+
+// migrationModules lists every module with database migrations in dependency order.
+// Up migrations are applied in this order, down migrations in reverse order.
+var migrationModules = []migrationModule{
+	{
+		Name:            "Runes",
+		Flag:            "runes",
+		Source:          runesmigrations.FS,
+		MigrationTable:  runesmigrations.MigrationsTable,
+		RequiredVersion: constants.DBVersion,
+		Schema:          func(modules config.Modules) string { return modules.Runes.Postgres.Schema },
+	},
+	{
+		Name:           "NodeSale",
+		Flag:           "nodesale",
+		Source:         nodesalemigrations.FS,
+		MigrationTable: nodesalemigrations.MigrationsTable,
+		Schema:         func(modules config.Modules) string { return modules.NodeSale.Postgres.Schema },
+	},
+}
+
+// selectMigrationModules returns the modules selected by their flags in dependency order.
+// If all is true, it returns every module enabled in the configuration instead.
+func selectMigrationModules(all bool, selected map[string]bool, enabledModules []string) ([]migrationModule, error) {
+	if all {
+		if lo.Contains(lo.Values(selected), true) {
+			return nil, errors.New("--all cannot be used with module flags")
+		}
+		enabled := lo.Map(enabledModules, func(item string, _ int) string { return strings.TrimSpace(item) })
+		modules := lo.Filter(migrationModules, func(module migrationModule, _ int) bool {
+			return lo.Contains(enabled, module.Flag)
+		})
+		if len(modules) == 0 {
+			return nil, errors.New("no module with migrations is enabled in the enable_modules configuration")
+		}
+		return modules, nil
+	}
+
+	modules := lo.Filter(migrationModules, func(module migrationModule, _ int) bool {
+		return selected[module.Flag]
+	})
+	if len(modules) == 0 {
+		flags := lo.Map(migrationModules, func(module migrationModule, _ int) string {
+			return "--" + module.Flag
+		})
+		return nil, errors.Errorf("no module selected, use %s or --all", strings.Join(flags, ", "))
+	}
+	return modules, nil
+}
+
+// migrationSchemas returns the Postgres schema of each module by its flag, taken from the postgres.schema configuration of the module.
+// The schema flag overrides the configuration, so it can only be used with a single module.
+func migrationSchemas(modules []migrationModule, schemaFlag string, conf config.Config) (map[string]string, error) {
+	if schemaFlag != "" && len(modules) > 1 {
+		return nil, errors.New("--schema can only be used with a single module, set postgres.schema of each module in the configuration instead")
+	}
+	schemas := make(map[string]string, len(modules))
+	for _, module := range modules {
+		schemas[module.Flag] = module.Schema(conf.Modules)
+		if schemaFlag != "" {
+			schemas[module.Flag] = schemaFlag
+		}
+	}
+	return schemas, nil
+}
+
+// addAllModulesFlags adds the --all flag, and --all-modules as its deprecated alias.
+func addAllModulesFlags(flags *pflag.FlagSet, p *bool, usage string) {
+	flags.BoolVar(p, "all", false, usage)
+	flags.BoolVar(p, "all-modules", false, usage)
+	_ = flags.MarkDeprecated("all-modules", "use --all instead")
+}
+
 func cloneURLWithQuery(u *url.URL, newQuery url.Values) *url.URL {
 	clone := *u
 	query := clone.Query()
@@ -18,6 +112,35 @@ func cloneURLWithQuery(u *url.URL, newQuery url.Values) *url.URL {
 	return &clone
 }
 
+// newMigrate creates a migrate instance reading migrations from the embedded source, so the binary doesn't depend on the working directory.
+// If schema is not empty, the migrations and the migrations table are applied in the schema.
+func newMigrate(source fs.FS, databaseURL *url.URL, migrationTable string, schema string) (*migrate.Migrate, error) {
+	sourceDriver, err := iofs.New(source, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open migrations source")
+	}
+	query := url.Values{"x-migrations-table": {migrationTable}}
+	if schema != "" {
+		query.Set("search_path", postgres.SearchPath(schema))
+	}
+	newDatabaseURL := cloneURLWithQuery(databaseURL, query)
+	m, err := migrate.NewWithSourceInstance("iofs", sourceDriver, newDatabaseURL.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open database")
+	}
+	return m, nil
+}
+
+// createSchema creates the schema of the migrations if it doesn't exist.
+func createSchema(ctx context.Context, databaseURL *url.URL, schema string) error {
+	conn, err := postgres.New(ctx, postgres.Config{URL: databaseURL.String()})
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to database")
+	}
+	defer conn.Close(ctx)
+	return errors.WithStack(postgres.CreateSchema(ctx, conn, schema))
+}
+
 var supportedDrivers = map[string]struct{}{
 	"postgres":   {},
 	"postgresql": {},
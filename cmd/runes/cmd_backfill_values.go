@@ -0,0 +1,62 @@
+package runes
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/core/datasources"
+	"github.com/gaze-network/indexer-network/internal/config"
+	runesmodule "github.com/gaze-network/indexer-network/modules/runes"
+	"github.com/spf13/cobra"
+)
+
+func NewBackfillValuesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "backfill-values",
+		Short:   "Record the BTC value of outpoints indexed before values were recorded",
+		Long:    "Fetch the transactions of outpoint balances indexed before the outpoint value column was added from the Bitcoin node, and record their values. Rune UTXO endpoints fetch the value of outpoints without a recorded value from the Bitcoin node. The command can be interrupted and resumed, and can run while the indexer is running.",
+		Example: `gaze runes backfill-values`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return backfillValuesHandler(cmd)
+		},
+	}
+	return cmd
+}
+
+func backfillValuesHandler(cmd *cobra.Command) error {
+	conf := config.Load()
+	if !conf.Network.IsSupported() {
+		return errors.Wrapf(errs.Unsupported, "%q network is not supported", conf.Network.String())
+	}
+
+	ctx := cmd.Context()
+	repo, cleanup, err := newRepository(ctx, conf)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer cleanup()
+
+	client, err := rpcclient.New(&rpcclient.ConnConfig{
+		Host:         conf.BitcoinNode.Host,
+		User:         conf.BitcoinNode.User,
+		Pass:         conf.BitcoinNode.Pass,
+		DisableTLS:   conf.BitcoinNode.DisableTLS,
+		HTTPPostMode: true,
+	}, nil)
+	if err != nil {
+		return errors.Wrap(err, "invalid Bitcoin node configuration")
+	}
+	defer client.Shutdown()
+	if err := client.Ping(); err != nil {
+		return errors.Wrapf(err, "can't connect to Bitcoin Core RPC Server %q", conf.BitcoinNode.Host)
+	}
+
+	count, err := runesmodule.BackfillOutPointValues(ctx, repo, datasources.NewBitcoinNode(client))
+	if err != nil {
+		return errors.Wrapf(err, "failed to backfill outpoint values, backfilled %d transactions", count)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Backfilled outpoint values of %d transactions\n", count)
+	return nil
+}
@@ -0,0 +1,65 @@
+package runes
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/internal/config"
+	runesmodule "github.com/gaze-network/indexer-network/modules/runes"
+	"github.com/spf13/cobra"
+)
+
+type checkCmdOptions struct {
+	Height int64
+}
+
+func NewCheckCommand() *cobra.Command {
+	opts := &checkCmdOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "check",
+		Short:   "Verify the integrity of the runes database",
+		Long:    "Verify that unspent outpoint balances match balances, circulating supplies match premine + mints - burns, and indexed blocks and their cumulative event hashes chain correctly.",
+		Example: `gaze runes check --height 840000`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return checkHandler(opts, cmd)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.Int64Var(&opts.Height, "height", -1, "Block height to check at. Defaults to the latest indexed block height")
+
+	return cmd
+}
+
+func checkHandler(opts *checkCmdOptions, cmd *cobra.Command) error {
+	conf := config.Load()
+	if !conf.Network.IsSupported() {
+		return errors.Wrapf(errs.Unsupported, "%q network is not supported", conf.Network.String())
+	}
+	if opts.Height < -1 {
+		return errors.Wrap(errs.InvalidArgument, "--height must be -1 or non-negative")
+	}
+
+	ctx := cmd.Context()
+	repo, cleanup, err := newRepository(ctx, conf)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer cleanup()
+
+	checker := runesmodule.NewIntegrityChecker(repo, repo, conf.Network)
+	violations, err := checker.Check(ctx, opts.Height)
+	if err != nil {
+		return errors.Wrap(err, "failed to check integrity")
+	}
+	for _, violation := range violations {
+		fmt.Fprintln(cmd.OutOrStdout(), violation.String())
+	}
+	if len(violations) > 0 {
+		return errors.Errorf("found %d integrity violations", len(violations))
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "No integrity violations found")
+	return nil
+}
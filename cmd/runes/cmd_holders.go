@@ -0,0 +1,134 @@
+package runes
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/internal/config"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/indexer-network/modules/runes/usecase"
+	"github.com/spf13/cobra"
+)
+
+func NewHoldersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "holders",
+		Short: "Rune holder tools",
+	}
+	cmd.AddCommand(
+		newHoldersExportCommand(),
+	)
+	return cmd
+}
+
+type holdersExportCmdOptions struct {
+	Id     string
+	Height int64
+	Format string
+	Output string
+}
+
+func newHoldersExportCommand() *cobra.Command {
+	opts := &holdersExportCmdOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export every holder of a rune at a block height",
+		Long:    "Export the address, pkscript, amount and decimals-adjusted amount of every holder of a rune at a block height as CSV or NDJSON. The last row is a trailer with the holder count, a sha256 checksum of all previous rows and the reconciliation of the total amount against the circulating supply.",
+		Example: `gaze runes holders export --id 840000:1 --height 850000 --format csv --output holders.csv`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return holdersExportHandler(opts, cmd)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Id, "id", "", "Rune id or rune name to export holders of (required)")
+	flags.Int64Var(&opts.Height, "height", -1, "Block height to export at. Defaults to the latest indexed block height")
+	flags.StringVar(&opts.Format, "format", string(usecase.HoldersExportFormatCSV), "Export format, csv or ndjson")
+	flags.StringVarP(&opts.Output, "output", "o", "-", "Path of the file to write, or - for stdout")
+	_ = cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func holdersExportHandler(opts *holdersExportCmdOptions, cmd *cobra.Command) error {
+	conf := config.Load()
+	if !conf.Network.IsSupported() {
+		return errors.Wrapf(errs.Unsupported, "%q network is not supported", conf.Network.String())
+	}
+	if opts.Height < -1 {
+		return errors.Wrap(errs.InvalidArgument, "--height must be -1 or non-negative")
+	}
+	format := usecase.HoldersExportFormat(opts.Format)
+	if !format.IsValid() {
+		return errors.Wrapf(errs.InvalidArgument, "--format must be %s or %s", usecase.HoldersExportFormatCSV, usecase.HoldersExportFormatNDJSON)
+	}
+
+	ctx := cmd.Context()
+	repo, cleanup, err := newRepository(ctx, conf)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer cleanup()
+
+	runeId, err := runes.NewRuneIdFromString(opts.Id)
+	if err != nil {
+		rune, err := runes.NewRuneFromString(opts.Id)
+		if err != nil {
+			return errors.Wrapf(errs.InvalidArgument, "--id %q is not a valid rune id or rune name", opts.Id)
+		}
+		runeId, err = repo.GetRuneIdFromRune(ctx, rune)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get rune id of %s", rune)
+		}
+	}
+
+	height := uint64(opts.Height)
+	if opts.Height == -1 {
+		latestBlock, err := repo.GetLatestBlock(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to get latest block")
+		}
+		height = uint64(latestBlock.Height)
+	}
+
+	out := cmd.OutOrStdout()
+	var file *os.File
+	if opts.Output != "-" {
+		// write to a temporary file first, so that a failed export doesn't leave a partial export behind
+		tmpPath := opts.Output + ".tmp"
+		file, err = os.Create(tmpPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to create export file")
+		}
+		defer os.Remove(tmpPath)
+		defer file.Close()
+		out = file
+	}
+
+	bw := bufio.NewWriter(out)
+	trailer, err := usecase.New(repo, nil).ExportHolders(ctx, bw, runeId, height, format, conf.Network)
+	if err != nil {
+		return errors.Wrap(err, "failed to export holders")
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "failed to write export")
+	}
+	if file != nil {
+		if err := file.Close(); err != nil {
+			return errors.Wrap(err, "failed to close export file")
+		}
+		if err := os.Rename(file.Name(), opts.Output); err != nil {
+			return errors.Wrap(err, "failed to rename export file")
+		}
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d holders of %s at height %d, total amount %s, circulating supply %s, sha256 %s\n", trailer.Holders, runeId, height, trailer.TotalAmount, trailer.CirculatingSupply, trailer.SHA256)
+	if !trailer.Reconciled {
+		return errors.Wrap(errs.InvalidState, "total amount of holders does not match the circulating supply")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package runes
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/internal/config"
+	runesmodule "github.com/gaze-network/indexer-network/modules/runes"
+	"github.com/spf13/cobra"
+)
+
+type verifyHashesCmdOptions struct {
+	From int64
+	To   int64
+}
+
+func NewVerifyHashesCommand() *cobra.Command {
+	opts := &verifyHashesCmdOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "verify-hashes",
+		Short:   "Recompute and verify event hashes from the runes database",
+		Long:    "Recompute the event hash of every indexed block in a height range from the stored data, and verify it and the cumulative event hash chain against the stored hashes.",
+		Example: `gaze runes verify-hashes --from 840000 --to 850000`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return verifyHashesHandler(opts, cmd)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.Int64Var(&opts.From, "from", 0, "First block height to verify (required)")
+	flags.Int64Var(&opts.To, "to", -1, "Last block height to verify. Defaults to the latest indexed block height")
+	_ = cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+func verifyHashesHandler(opts *verifyHashesCmdOptions, cmd *cobra.Command) error {
+	conf := config.Load()
+	if !conf.Network.IsSupported() {
+		return errors.Wrapf(errs.Unsupported, "%q network is not supported", conf.Network.String())
+	}
+	if opts.From < 0 {
+		return errors.Wrap(errs.InvalidArgument, "--from must be non-negative")
+	}
+	if opts.To < -1 {
+		return errors.Wrap(errs.InvalidArgument, "--to must be -1 or non-negative")
+	}
+
+	ctx := cmd.Context()
+	repo, cleanup, err := newRepository(ctx, conf)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer cleanup()
+
+	checker := runesmodule.NewIntegrityChecker(repo, repo, conf.Network)
+	violations, err := checker.VerifyEventHashes(ctx, opts.From, opts.To)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify event hashes")
+	}
+	for _, violation := range violations {
+		fmt.Fprintln(cmd.OutOrStdout(), violation.String())
+	}
+	if len(violations) > 0 {
+		return errors.Errorf("found %d event hash violations", len(violations))
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "All event hashes verified")
+	return nil
+}
@@ -0,0 +1,103 @@
+package runes
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/internal/config"
+	runesmodule "github.com/gaze-network/indexer-network/modules/runes"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	runespostgres "github.com/gaze-network/indexer-network/modules/runes/repository/postgres"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+type RollbackOptions struct {
+	ToHeight int64
+	DryRun   bool
+	Yes      bool
+}
+
+// Rollback reverts the runes data to the given height, so that the indexer re-indexes from the next block.
+// It refuses to run while a runes indexer is running on the same database.
+func Rollback(cmd *cobra.Command, conf config.Config, opts RollbackOptions) error {
+	ctx := cmd.Context()
+	pg, err := newPool(ctx, conf)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer pg.Close()
+
+	unlock, err := lockIndexer(ctx, conf, pg)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer unlock()
+
+	repo := runespostgres.NewRepository(pg)
+	latestBlock, err := repo.GetLatestBlock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get latest block")
+	}
+	if opts.ToHeight >= latestBlock.Height {
+		return errors.Wrapf(errs.InvalidArgument, "--to-height %d must be lower than the latest indexed block height %d", opts.ToHeight, latestBlock.Height)
+	}
+	if startingHeight := constants.StartingBlockHeader[conf.Network].Height; opts.ToHeight < startingHeight {
+		return errors.Wrapf(errs.InvalidArgument, "--to-height %d must not be lower than the starting block height %d", opts.ToHeight, startingHeight)
+	}
+
+	prunedHeight, err := repo.GetPrunedHeight(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pruned height")
+	}
+	if opts.ToHeight < int64(prunedHeight) {
+		return errors.Wrapf(errs.InvalidArgument, "--to-height %d must not be lower than the pruned height %d", opts.ToHeight, prunedHeight)
+	}
+
+	processor := runesmodule.NewProcessor(repo, repo, nil, conf.Network, nil, conf.Modules.Runes.Pruning, nil)
+	from := opts.ToHeight + 1
+	counts, err := processor.CountRevertData(ctx, from)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Rolling back runes data from height %d to %d:\n", latestBlock.Height, opts.ToHeight)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  indexed blocks\t%d deleted\n", counts.IndexedBlocks)
+	fmt.Fprintf(w, "  rune entries\t%d deleted\n", counts.RuneEntries)
+	fmt.Fprintf(w, "  rune entry states\t%d deleted\n", counts.RuneEntryStates)
+	fmt.Fprintf(w, "  rune transactions\t%d deleted\n", counts.RuneTransactions)
+	fmt.Fprintf(w, "  rune transaction inputs/outputs\t%d deleted\n", counts.RuneTransactionIO)
+	fmt.Fprintf(w, "  runestones\t%d deleted\n", counts.Runestones)
+	fmt.Fprintf(w, "  outpoint balances\t%d deleted\n", counts.OutPointBalances)
+	fmt.Fprintf(w, "  spent outpoint balances\t%d unspent\n", counts.SpentOutPointBalances)
+	fmt.Fprintf(w, "  balances\t%d deleted\n", counts.Balances)
+	fmt.Fprintf(w, "  current balances\t%d restored\n", counts.CurrentBalances)
+	if err := w.Flush(); err != nil {
+		return errors.Wrap(err, "failed to write row counts")
+	}
+	if opts.DryRun {
+		fmt.Fprintln(out, "Dry run, no data was modified")
+		return nil
+	}
+
+	if !opts.Yes {
+		input := ""
+		fmt.Fprintf(out, "Are you sure you want to rollback runes data to height %d? (y/N):", opts.ToHeight)
+		fmt.Fscanln(cmd.InOrStdin(), &input)
+		if !lo.Contains([]string{"y", "yes"}, strings.ToLower(input)) {
+			fmt.Fprintln(out, "Rollback cancelled")
+			return nil
+		}
+	}
+
+	if err := processor.RevertData(ctx, from); err != nil {
+		return errors.Wrap(err, "failed to revert data")
+	}
+	fmt.Fprintf(out, "Rolled back runes data to height %d\n", opts.ToHeight)
+	return nil
+}
@@ -0,0 +1,55 @@
+package runes
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/internal/config"
+	"github.com/gaze-network/indexer-network/internal/postgres"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	runespostgres "github.com/gaze-network/indexer-network/modules/runes/repository/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newPool connects to the runes database from the configuration. The returned pool must be closed.
+func newPool(ctx context.Context, conf config.Config) (*pgxpool.Pool, error) {
+	switch strings.ToLower(conf.Modules.Runes.Database) {
+	case "postgresql", "postgres", "pg":
+		pg, err := postgres.NewPool(ctx, conf.Modules.Runes.Postgres)
+		if err != nil {
+			if errors.Is(err, errs.InvalidArgument) {
+				return nil, errors.Wrap(err, "Invalid Postgres configuration for indexer")
+			}
+			return nil, errors.Wrap(err, "can't create Postgres connection pool")
+		}
+		return pg, nil
+	default:
+		return nil, errors.Wrapf(errs.Unsupported, "%q database for indexer is not supported", conf.Modules.Runes.Database)
+	}
+}
+
+// newRepository connects to the runes database from the configuration. The returned cleanup function must be called to close the connection.
+func newRepository(ctx context.Context, conf config.Config) (*runespostgres.Repository, func(), error) {
+	pg, err := newPool(ctx, conf)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return runespostgres.NewRepository(pg), pg.Close, nil
+}
+
+// lockIndexer acquires the runes indexer lock, so that no runes indexer can run while the data is being modified.
+// The returned unlock function must be called before closing the pool.
+func lockIndexer(ctx context.Context, conf config.Config, pg *pgxpool.Pool) (func(), error) {
+	releaseLock, ok, err := postgres.TryAdvisoryLock(ctx, pg, postgres.SchemaLockKey(constants.IndexerLockKey, conf.Modules.Runes.Postgres.Schema))
+	if err != nil {
+		return nil, errors.Wrap(err, "can't acquire runes indexer lock")
+	}
+	if !ok {
+		return nil, errors.Wrap(errs.InvalidState, "a runes indexer or maintenance command is running on the database, stop it first")
+	}
+	return func() {
+		_ = releaseLock(ctx)
+	}, nil
+}
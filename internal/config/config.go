@@ -38,6 +38,9 @@ var (
 			Runes: runesconfig.Config{
 				Datasource: "bitcoin-node",
 				Database:   "postgres",
+				Pruning: runesconfig.PruningConfig{
+					KeepBlocks: 1000,
+				},
 			},
 		},
 	}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"hash/fnv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TryAdvisoryLock tries to acquire the session-level advisory lock of the given key without waiting.
+// The lock is held by a dedicated connection of the pool until the returned release function is called, which must be done before closing the pool.
+// Returns ok = false if the lock is held by another session.
+func TryAdvisoryLock(ctx context.Context, pool *pgxpool.Pool, key int64) (release func(context.Context) error, ok bool, err error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to acquire connection")
+	}
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&ok); err != nil {
+		conn.Release()
+		return nil, false, errors.Wrap(err, "failed to try advisory lock")
+	}
+	if !ok {
+		conn.Release()
+		return nil, false, nil
+	}
+	return func(ctx context.Context) error {
+		defer conn.Release()
+		if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", key); err != nil {
+			return errors.Wrap(err, "failed to release advisory lock")
+		}
+		return nil
+	}, true, nil
+}
+
+// SchemaLockKey derives the advisory lock key of the given schema from the base key, so that instances using different schemas of the same database don't block each other.
+// Returns the base key if schema is empty.
+func SchemaLockKey(key int64, schema string) int64 {
+	if schema == "" {
+		return key
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(schema))
+	return key ^ int64(h.Sum64())
+}
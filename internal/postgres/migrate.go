@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/pkg/logger"
+	"github.com/gaze-network/indexer-network/pkg/logger/slogx"
+	"github.com/golang-migrate/migrate/v4"
+	migratepostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// MigrateUpTo applies the up migrations of source to the database until the schema reaches the given version.
+// golang-migrate holds an advisory lock on the migrations table while migrating, so concurrent migrations of the same schema are serialized.
+// It never applies down migrations: a dirty schema or a schema newer than the given version returns errs.ConflictSetting.
+func MigrateUpTo(ctx context.Context, pool *pgxpool.Pool, source fs.FS, migrationsTable string, version uint) (err error) {
+	sourceDriver, err := iofs.New(source, ".")
+	if err != nil {
+		return errors.Wrap(err, "failed to open migrations source")
+	}
+	// closing the database/sql wrapper only releases its connections back to the pool
+	databaseDriver, err := migratepostgres.WithInstance(stdlib.OpenDBFromPool(pool), &migratepostgres.Config{
+		MigrationsTable: migrationsTable,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to open database")
+	}
+	m, err := migrate.NewWithInstance("iofs", sourceDriver, "postgres", databaseDriver)
+	if err != nil {
+		return errors.Wrap(err, "failed to create migrate instance")
+	}
+	defer func() {
+		sourceErr, databaseErr := m.Close()
+		if err == nil {
+			err = errors.CombineErrors(sourceErr, databaseErr)
+		}
+	}()
+
+	currentVersion, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return errors.Wrap(err, "failed to get current schema version")
+	}
+	if dirty {
+		return errors.Wrapf(errs.ConflictSetting, "schema is dirty at version %d, fix the failed migration manually", currentVersion)
+	}
+	if currentVersion > version {
+		return errors.Wrapf(errs.ConflictSetting, "schema version %d is newer than the supported version %d", currentVersion, version)
+	}
+	if currentVersion == version {
+		return nil
+	}
+
+	logger.InfoContext(ctx, "Applying up migrations", slogx.Uint("from_version", currentVersion), slogx.Uint("to_version", version))
+	if err := m.Migrate(version); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return errors.Wrapf(err, "failed to migrate schema to version %d", version)
+	}
+	return nil
+}
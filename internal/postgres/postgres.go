@@ -28,11 +28,16 @@ type Config struct {
 	DBName   string `mapstructure:"db_name"`  // Default is postgres
 	SSLMode  string `mapstructure:"ssl_mode"` // Default is prefer
 	URL      string `mapstructure:"url"`      // If URL is provided, other fields are ignored
+	Schema   string `mapstructure:"schema"`   // Schema to store the data in. Default is empty (use the database's default search_path, usually public)
 
 	MaxConns int32 `mapstructure:"max_conns"` // Default is 16
 	MinConns int32 `mapstructure:"min_conns"` // Default is 0
 
 	Debug bool `mapstructure:"debug"`
+
+	// ReadReplicas are read-only replicas of the database to serve API queries from. Only supported by the Runes module.
+	// The schema of a replica defaults to the schema of the primary database.
+	ReadReplicas []Config `mapstructure:"read_replicas"`
 }
 
 // New creates a new connection to the database
@@ -43,6 +48,9 @@ func New(ctx context.Context, conf Config) (*pgx.Conn, error) {
 		return nil, errors.Join(errs.InvalidArgument, errors.Wrap(err, "failed while parse config"))
 	}
 	connConfig.Tracer = conf.QueryTracer()
+	if conf.Schema != "" {
+		connConfig.RuntimeParams["search_path"] = SearchPath(conf.Schema)
+	}
 
 	// Create a new connection
 	conn, err := pgx.ConnectConfig(ctx, connConfig)
@@ -68,6 +76,9 @@ func NewPool(ctx context.Context, conf Config) (*pgxpool.Pool, error) {
 	connConfig.MaxConns = utils.Default(conf.MaxConns, DefaultMaxConns)
 	connConfig.MinConns = utils.Default(conf.MinConns, DefaultMinConns)
 	connConfig.ConnConfig.Tracer = conf.QueryTracer()
+	if conf.Schema != "" {
+		connConfig.ConnConfig.RuntimeParams["search_path"] = SearchPath(conf.Schema)
+	}
 
 	// Create a new connection pool
 	connPool, err := pgxpool.NewWithConfig(ctx, connConfig)
@@ -83,6 +94,20 @@ func NewPool(ctx context.Context, conf Config) (*pgxpool.Pool, error) {
 	return connPool, nil
 }
 
+// SearchPath returns the search_path that stores new tables in the given schema.
+// The public schema stays in the path, so extensions installed there (e.g. pg_trgm) remain usable.
+func SearchPath(schema string) string {
+	return pgx.Identifier{schema}.Sanitize() + ", public"
+}
+
+// CreateSchema creates the given schema if it doesn't exist.
+func CreateSchema(ctx context.Context, db Queryable, schema string) error {
+	if _, err := db.Exec(ctx, "CREATE SCHEMA IF NOT EXISTS "+pgx.Identifier{schema}.Sanitize()); err != nil {
+		return errors.Wrapf(err, "failed to create schema %q", schema)
+	}
+	return nil
+}
+
 // String returns the connection string (DSN format or URL format)
 func (conf Config) String() string {
 	if conf.Host == "" {
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations of the NodeSale module, so the binary can apply them from any working directory.
+package migrations
+
+import "embed"
+
+// MigrationsTable is the table used by golang-migrate to track the applied NodeSale migrations.
+const MigrationsTable = "nodesale_schema_migrations"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,69 @@
+package httphandler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+)
+
+// pageCursor is an opaque pagination cursor, encoded as base64 JSON. Clients must not rely on its content.
+// It pins every page to the block height of the first page, and holds the sort key of the last item of the previous page.
+type pageCursor[K any] struct {
+	Height uint64 `json:"h"`
+	Key    K      `json:"k"`
+}
+
+// validateCursor validates the cursor of the request. Endpoints that don't support cursors reject them instead of ignoring them.
+func (req paginationRequest) validateCursor(supported bool) error {
+	if req.Cursor == "" {
+		return nil
+	}
+	if !supported {
+		return errors.New("'cursor' is not supported, use 'offset'")
+	}
+	if req.Offset != 0 {
+		return errors.New("'cursor' cannot be used with 'offset'")
+	}
+	return nil
+}
+
+func encodeCursor[K any](height uint64, key K) (string, error) {
+	data, err := json.Marshal(pageCursor[K]{
+		Height: height,
+		Key:    key,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal cursor")
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decodes the cursor of a paginated request. Returns nil if raw is empty.
+func decodeCursor[K any](raw string) (*pageCursor[K], error) {
+	if raw == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errs.NewPublicError("invalid cursor")
+	}
+	var cursor pageCursor[K]
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, errs.NewPublicError("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+// encodeNextCursor returns the cursor of the page after the given page, or an empty string if the page is the last page.
+func encodeNextCursor[T, K any](height uint64, page []T, limit int32, key func(T) K) (string, error) {
+	if len(page) == 0 || len(page) < int(limit) {
+		return "", nil
+	}
+	cursor, err := encodeCursor(height, key(page[len(page)-1]))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return cursor, nil
+}
@@ -0,0 +1,149 @@
+package httphandler
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/uint128"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	t.Run("balance", func(t *testing.T) {
+		key := entity.BalanceCursor{
+			Amount:   uint128.Max, // amounts exceed the precision of JSON numbers
+			PkScript: []byte{0x51, 0x20, 0xff},
+		}
+		raw, err := encodeCursor(840000, key)
+		assert.NoError(t, err)
+		cursor, err := decodeCursor[entity.BalanceCursor](raw)
+		assert.NoError(t, err)
+		assert.Equal(t, &pageCursor[entity.BalanceCursor]{Height: 840000, Key: key}, cursor)
+	})
+	t.Run("rune transaction", func(t *testing.T) {
+		key := entity.RuneTransactionCursor{BlockHeight: 840001, Index: 12}
+		raw, err := encodeCursor(840002, key)
+		assert.NoError(t, err)
+		cursor, err := decodeCursor[entity.RuneTransactionCursor](raw)
+		assert.NoError(t, err)
+		assert.Equal(t, &pageCursor[entity.RuneTransactionCursor]{Height: 840002, Key: key}, cursor)
+	})
+	t.Run("outpoint", func(t *testing.T) {
+		key := wire.OutPoint{Hash: chainhash.Hash{1, 2, 3}, Index: 7}
+		raw, err := encodeCursor(840000, key)
+		assert.NoError(t, err)
+		cursor, err := decodeCursor[wire.OutPoint](raw)
+		assert.NoError(t, err)
+		assert.Equal(t, &pageCursor[wire.OutPoint]{Height: 840000, Key: key}, cursor)
+	})
+}
+
+func TestDecodeCursor(t *testing.T) {
+	type testcase struct {
+		name string
+		raw  string
+	}
+
+	testcases := []testcase{
+		{
+			name: "not base64",
+			raw:  "not a cursor!",
+		},
+		{
+			name: "not json",
+			raw:  "bm90IGpzb24",
+		},
+		{
+			name: "wrong key type",
+			raw:  "eyJoIjoxLCJrIjoiYWJjIn0", // {"h":1,"k":"abc"}
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cursor, err := decodeCursor[entity.RuneTransactionCursor](tc.raw)
+			assert.Nil(t, cursor)
+			var publicErr *errs.PublicError
+			assert.ErrorAs(t, err, &publicErr)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		cursor, err := decodeCursor[entity.RuneTransactionCursor]("")
+		assert.NoError(t, err)
+		assert.Nil(t, cursor)
+	})
+}
+
+func TestEncodeNextCursor(t *testing.T) {
+	key := func(n int) entity.RuneTransactionCursor {
+		return entity.RuneTransactionCursor{BlockHeight: uint64(n)}
+	}
+
+	t.Run("full page", func(t *testing.T) {
+		raw, err := encodeNextCursor(840000, []int{3, 2, 1}, 3, key)
+		assert.NoError(t, err)
+		cursor, err := decodeCursor[entity.RuneTransactionCursor](raw)
+		assert.NoError(t, err)
+		// the cursor points at the last item of the page
+		assert.Equal(t, &pageCursor[entity.RuneTransactionCursor]{Height: 840000, Key: key(1)}, cursor)
+	})
+	t.Run("last page", func(t *testing.T) {
+		raw, err := encodeNextCursor(840000, []int{3, 2}, 3, key)
+		assert.NoError(t, err)
+		assert.Empty(t, raw)
+	})
+	t.Run("empty page", func(t *testing.T) {
+		raw, err := encodeNextCursor(840000, []int{}, 3, key)
+		assert.NoError(t, err)
+		assert.Empty(t, raw)
+	})
+}
+
+func TestValidateCursor(t *testing.T) {
+	type testcase struct {
+		name        string
+		req         paginationRequest
+		supported   bool
+		expectedErr bool
+	}
+
+	testcases := []testcase{
+		{
+			name:      "no cursor",
+			req:       paginationRequest{Offset: 10},
+			supported: false,
+		},
+		{
+			name:      "cursor",
+			req:       paginationRequest{Cursor: "abc"},
+			supported: true,
+		},
+		{
+			name:        "cursor with offset",
+			req:         paginationRequest{Cursor: "abc", Offset: 10},
+			supported:   true,
+			expectedErr: true,
+		},
+		{
+			name:        "cursor not supported",
+			req:         paginationRequest{Cursor: "abc"},
+			supported:   false,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.req.validateCursor(tc.supported)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package httphandler
+
+import (
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+type decodeRunestoneRequest struct {
+	RawTx  string `json:"rawTx"`  // raw transaction hex
+	TxHash string `json:"txHash"` // fetched from the bitcoin node if rawTx is not given
+}
+
+func (r decodeRunestoneRequest) Validate() error {
+	var errList []error
+	if r.RawTx == "" && r.TxHash == "" {
+		errList = append(errList, errors.New("'rawTx' or 'txHash' is required"))
+	}
+	if r.RawTx != "" && r.TxHash != "" {
+		errList = append(errList, errors.New("'rawTx' cannot be used with 'txHash'"))
+	}
+	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
+}
+
+type decodeRunestoneResult struct {
+	TxHash      chainhash.Hash `json:"txHash"`
+	OutputCount int            `json:"outputCount"` // number of outputs that edicts and the pointer refer to
+	Runestone   *runestone     `json:"runestone"`   // null if the transaction has no runestone
+}
+
+type decodeRunestoneResponse = HttpResponse[decodeRunestoneResult]
+
+// DecodeRunestone deciphers the runestone of a transaction, including the flaws that make it a cenotaph. The transaction does not need to be indexed.
+func (h *HttpHandler) DecodeRunestone(ctx *fiber.Ctx) (err error) {
+	var req decodeRunestoneRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	var msgTx *wire.MsgTx
+	if req.RawTx != "" {
+		rawTx, err := hex.DecodeString(req.RawTx)
+		if err != nil {
+			return errs.NewPublicError("'rawTx' is not valid hex")
+		}
+		msgTx, err = h.usecase.DecodeRawTransaction(rawTx)
+		if err != nil {
+			if errors.Is(err, errs.InvalidArgument) {
+				return errs.NewPublicError("'rawTx' is not a valid transaction")
+			}
+			return errors.Wrap(err, "error during DecodeRawTransaction")
+		}
+	} else {
+		txHash, err := chainhash.NewHashFromStr(req.TxHash)
+		if err != nil {
+			return errs.NewPublicError("invalid 'txHash'")
+		}
+		msgTx, err = h.usecase.GetRawTransaction(ctx.UserContext(), *txHash)
+		if err != nil {
+			if errors.Is(err, errs.NotFound) {
+				return errs.NewPublicError("transaction not found")
+			}
+			return errors.Wrap(err, "error during GetRawTransaction")
+		}
+	}
+
+	runestone, err := h.usecase.DecipherRunestone(msgTx)
+	if err != nil {
+		return errors.Wrap(err, "error during DecipherRunestone")
+	}
+
+	resp := decodeRunestoneResponse{
+		Result: &decodeRunestoneResult{
+			TxHash:      msgTx.TxHash(),
+			OutputCount: len(msgTx.TxOut),
+			Runestone:   mapRunestone(runestone),
+		},
+	}
+
+	return errors.WithStack(ctx.JSON(resp))
+}
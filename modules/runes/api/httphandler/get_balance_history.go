@@ -0,0 +1,164 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+)
+
+type getBalanceHistoryRequest struct {
+	paginationRequest
+	snapshotRequest
+	Wallet    string `params:"wallet"`
+	Id        string `query:"id"`
+	FromBlock uint64 `query:"fromBlock"`
+	ToBlock   uint64 `query:"toBlock"`
+	Interval  uint64 `query:"interval"` // number of blocks per point, only the last change in each interval is returned
+}
+
+const (
+	getBalanceHistoryMaxLimit = 5000
+)
+
+func (r *getBalanceHistoryRequest) Validate() error {
+	var errList []error
+	if r.Wallet == "" {
+		errList = append(errList, errors.New("'wallet' is required"))
+	}
+	if r.Id != "" {
+		id, err := url.QueryUnescape(r.Id)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		r.Id = id
+		if !isRuneIdOrRuneName(r.Id) {
+			errList = append(errList, errors.Errorf("id '%s' is not valid rune id or rune name", r.Id))
+		}
+	}
+	if r.ToBlock != 0 && r.FromBlock > r.ToBlock {
+		errList = append(errList, errors.New("'fromBlock' must be less than or equal to 'toBlock'"))
+	}
+	if r.Limit < 0 {
+		errList = append(errList, errors.New("'limit' must be non-negative"))
+	}
+	if r.Limit > getBalanceHistoryMaxLimit {
+		errList = append(errList, errors.Errorf("'limit' cannot exceed %d", getBalanceHistoryMaxLimit))
+	}
+	if err := r.validateCursor(false); err != nil {
+		errList = append(errList, err)
+	}
+	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
+}
+
+func (r *getBalanceHistoryRequest) ParseDefault() error {
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+	return errors.WithStack(r.paginationRequest.ParseDefault())
+}
+
+type balanceChange struct {
+	Id             runes.RuneId     `json:"id"`
+	Name           runes.SpacedRune `json:"name"`
+	Symbol         string           `json:"symbol"`
+	Decimals       uint8            `json:"decimals"`
+	BlockHeight    uint64           `json:"blockHeight"`
+	Timestamp      *int64           `json:"timestamp"` // unix timestamp, null if the block time is unknown
+	Amount         uint128.Uint128  `json:"amount"`
+	PreviousAmount uint128.Uint128  `json:"previousAmount"`
+}
+
+type getBalanceHistoryResult struct {
+	List []balanceChange `json:"list"`
+}
+
+type getBalanceHistoryResponse = HttpResponse[getBalanceHistoryResult]
+
+func (h *HttpHandler) GetBalanceHistory(ctx *fiber.Ctx) (err error) {
+	var req getBalanceHistoryRequest
+	if err := ctx.ParamsParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.ParseDefault(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	pkScript, ok := resolvePkScript(h.network, req.Wallet)
+	if !ok {
+		return errs.NewPublicError("unable to resolve pkscript from \"wallet\"")
+	}
+
+	var runeId runes.RuneId
+	if req.Id != "" {
+		var ok bool
+		runeId, ok = h.resolveRuneId(ctx.UserContext(), req.Id)
+		if !ok {
+			return errs.NewPublicError(fmt.Sprintf("unable to resolve rune id \"%s\" from \"id\"", req.Id))
+		}
+	}
+
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// default to snapshot block
+	if req.ToBlock == 0 {
+		req.ToBlock = snapshot.Height
+	}
+	if req.ToBlock > snapshot.Height {
+		return errs.NewPublicError(fmt.Sprintf("toBlock must be less than or equal to the snapshot block height, got toBlock=%d, snapshot=%d", req.ToBlock, snapshot.Height))
+	}
+	if req.FromBlock > req.ToBlock {
+		return errs.NewPublicError(fmt.Sprintf("fromBlock must be less than or equal to toBlock, got fromBlock=%d, toBlock=%d", req.FromBlock, req.ToBlock))
+	}
+
+	changes, err := h.usecase.GetBalanceHistoryByPkScript(ctx.UserContext(), pkScript, runeId, req.FromBlock, req.ToBlock, req.Interval, req.Limit, req.Offset)
+	if err != nil {
+		return errors.Wrap(err, "error during GetBalanceHistoryByPkScript")
+	}
+
+	runeIds := lo.Uniq(lo.Map(changes, func(c *entity.BalanceChange, _ int) runes.RuneId {
+		return c.RuneId
+	}))
+	runeEntries, err := h.usecase.GetRuneEntryByRuneIdAndHeightBatch(ctx.UserContext(), runeIds, snapshot.Height)
+	if err != nil {
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeightBatch")
+	}
+
+	list := make([]balanceChange, 0, len(changes))
+	for _, c := range changes {
+		runeEntry := runeEntries[c.RuneId]
+		list = append(list, balanceChange{
+			Id:             c.RuneId,
+			Name:           runeEntry.SpacedRune,
+			Symbol:         string(runeEntry.Symbol),
+			Decimals:       runeEntry.Divisibility,
+			BlockHeight:    c.BlockHeight,
+			Timestamp:      lo.Ternary(c.Timestamp.IsZero(), nil, lo.ToPtr(c.Timestamp.Unix())),
+			Amount:         c.Amount,
+			PreviousAmount: c.PreviousAmount,
+		})
+	}
+
+	resp := getBalanceHistoryResponse{
+		Result: &getBalanceHistoryResult{
+			List: list,
+		},
+	}
+
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
+}
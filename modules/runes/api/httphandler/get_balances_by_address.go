@@ -14,6 +14,7 @@ import (
 
 type getBalancesRequest struct {
 	paginationRequest
+	snapshotRequest
 	Wallet      string `params:"wallet"`
 	Id          string `query:"id"`
 	BlockHeight uint64 `query:"blockHeight"`
@@ -45,6 +46,9 @@ func (r *getBalancesRequest) Validate() error {
 	if r.Limit > getBalancesMaxLimit {
 		errList = append(errList, errors.Errorf("'limit' cannot exceed %d", getBalancesMaxLimit))
 	}
+	if err := r.validateCursor(false); err != nil {
+		errList = append(errList, err)
+	}
 	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
 }
 
@@ -83,19 +87,16 @@ func (h *HttpHandler) GetBalances(ctx *fiber.Ctx) (err error) {
 		return errs.NewPublicError("unable to resolve pkscript from \"wallet\"")
 	}
 
-	blockHeight := req.BlockHeight
-	if blockHeight == 0 {
-		blockHeader, err := h.usecase.GetLatestBlock(ctx.UserContext())
-		if err != nil {
-			if errors.Is(err, errs.NotFound) {
-				return errs.NewPublicError("latest block not found")
-			}
-			return errors.Wrap(err, "error during GetLatestBlock")
-		}
-		blockHeight = uint64(blockHeader.Height)
+	if err := req.pin(req.BlockHeight, "blockHeight"); err != nil {
+		return errors.WithStack(err)
+	}
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
 	}
+	blockHeight := snapshot.Height
 
-	balances, err := h.usecase.GetBalancesByPkScript(ctx.UserContext(), pkScript, blockHeight, req.Limit, req.Offset)
+	balances, err := h.usecase.GetBalancesByPkScript(ctx.UserContext(), pkScript, blockHeight, snapshot.Latest, req.Limit, req.Offset)
 	if err != nil {
 		if errors.Is(err, errs.NotFound) {
 			return errs.NewPublicError("balances not found")
@@ -114,9 +115,9 @@ func (h *HttpHandler) GetBalances(ctx *fiber.Ctx) (err error) {
 	balanceRuneIds := lo.Map(balances, func(b *entity.Balance, _ int) runes.RuneId {
 		return b.RuneId
 	})
-	runeEntries, err := h.usecase.GetRuneEntryByRuneIdBatch(ctx.UserContext(), balanceRuneIds)
+	runeEntries, err := h.usecase.GetRuneEntryByRuneIdAndHeightBatch(ctx.UserContext(), balanceRuneIds, blockHeight)
 	if err != nil {
-		return errors.Wrap(err, "error during GetRuneEntryByRuneIdBatch")
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeightBatch")
 	}
 
 	balanceList := make([]balance, 0, len(balances))
@@ -138,5 +139,5 @@ func (h *HttpHandler) GetBalances(ctx *fiber.Ctx) (err error) {
 		},
 	}
 
-	return errors.WithStack(ctx.JSON(resp))
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
 }
@@ -22,6 +22,7 @@ type getBalanceQuery struct {
 }
 
 type getBalancesBatchRequest struct {
+	snapshotRequest
 	Queries []getBalanceQuery `json:"queries"`
 }
 
@@ -63,19 +64,18 @@ func (h *HttpHandler) GetBalancesBatch(ctx *fiber.Ctx) (err error) {
 	if err := ctx.BodyParser(&req); err != nil {
 		return errors.WithStack(err)
 	}
+	if err := ctx.QueryParser(&req.snapshotRequest); err != nil {
+		return errors.WithStack(err)
+	}
 	if err := req.Validate(); err != nil {
 		return errors.WithStack(err)
 	}
 
-	var latestBlockHeight uint64
-	blockHeader, err := h.usecase.GetLatestBlock(ctx.UserContext())
+	// queries without their own block height are served at the snapshot
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
 	if err != nil {
-		if errors.Is(err, errs.NotFound) {
-			return errs.NewPublicError("latest block not found")
-		}
-		return errors.Wrap(err, "error during GetLatestBlock")
+		return errors.WithStack(err)
 	}
-	latestBlockHeight = uint64(blockHeader.Height)
 
 	processQuery := func(ctx context.Context, query getBalanceQuery, queryIndex int) (*getBalancesResult, error) {
 		pkScript, ok := resolvePkScript(h.network, query.Wallet)
@@ -83,16 +83,16 @@ func (h *HttpHandler) GetBalancesBatch(ctx *fiber.Ctx) (err error) {
 			return nil, errs.NewPublicError(fmt.Sprintf("unable to resolve pkscript from \"queries[%d].wallet\"", queryIndex))
 		}
 
-		blockHeight := query.BlockHeight
+		blockHeight, latest := query.BlockHeight, false
 		if blockHeight == 0 {
-			blockHeight = latestBlockHeight
+			blockHeight, latest = snapshot.Height, snapshot.Latest
 		}
 
 		if query.Limit == 0 {
 			query.Limit = getBalancesDefaultLimit
 		}
 
-		balances, err := h.usecase.GetBalancesByPkScript(ctx, pkScript, blockHeight, query.Limit, query.Offset)
+		balances, err := h.usecase.GetBalancesByPkScript(ctx, pkScript, blockHeight, latest, query.Limit, query.Offset)
 		if err != nil {
 			if errors.Is(err, errs.NotFound) {
 				return nil, errs.NewPublicError("balances not found")
@@ -111,12 +111,12 @@ func (h *HttpHandler) GetBalancesBatch(ctx *fiber.Ctx) (err error) {
 		balanceRuneIds := lo.Map(balances, func(b *entity.Balance, _ int) runes.RuneId {
 			return b.RuneId
 		})
-		runeEntries, err := h.usecase.GetRuneEntryByRuneIdBatch(ctx, balanceRuneIds)
+		runeEntries, err := h.usecase.GetRuneEntryByRuneIdAndHeightBatch(ctx, balanceRuneIds, blockHeight)
 		if err != nil {
 			if errors.Is(err, errs.NotFound) {
 				return nil, errs.NewPublicError("rune not found")
 			}
-			return nil, errors.Wrap(err, "error during GetRuneEntryByRuneIdBatch")
+			return nil, errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeightBatch")
 		}
 
 		balanceList := make([]balance, 0, len(balances))
@@ -162,5 +162,5 @@ func (h *HttpHandler) GetBalancesBatch(ctx *fiber.Ctx) (err error) {
 		},
 	}
 
-	return errors.WithStack(ctx.JSON(resp))
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
 }
@@ -0,0 +1,162 @@
+package httphandler
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+)
+
+type getBlockActivityRequest struct {
+	snapshotRequest
+	Height uint64 `params:"height"`
+}
+
+type blockMint struct {
+	Id       runes.RuneId     `json:"id"`
+	Name     runes.SpacedRune `json:"name"`
+	Symbol   string           `json:"symbol"`
+	Decimals uint8            `json:"decimals"`
+	Count    uint64           `json:"count"` // number of mint transactions
+	Amount   uint128.Uint128  `json:"amount"`
+}
+
+type blockBurn struct {
+	Id       runes.RuneId     `json:"id"`
+	Name     runes.SpacedRune `json:"name"`
+	Symbol   string           `json:"symbol"`
+	Decimals uint8            `json:"decimals"`
+	Amount   uint128.Uint128  `json:"amount"`
+}
+
+type blockCenotaph struct {
+	TxHash     chainhash.Hash `json:"txHash"`
+	Index      uint32         `json:"index"`
+	Flaws      []string       `json:"flaws"`
+	RuneEtched bool           `json:"runeEtched"`
+}
+
+type getBlockActivityResult struct {
+	BlockHeight         uint64               `json:"blockHeight"`
+	BlockHash           chainhash.Hash       `json:"blockHash"`
+	PrevBlockHash       chainhash.Hash       `json:"prevBlockHash"`
+	EventHash           chainhash.Hash       `json:"eventHash"`
+	CumulativeEventHash chainhash.Hash       `json:"cumulativeEventHash"`
+	Timestamp           *int64               `json:"timestamp"` // unix timestamp, null if the block has no rune transactions
+	TransactionCount    uint64               `json:"transactionCount"`
+	TransferCount       uint64               `json:"transferCount"` // number of rune transactions that spend rune balances
+	Etchings            []getTokenInfoResult `json:"etchings"`
+	Mints               []blockMint          `json:"mints"`
+	Burns               []blockBurn          `json:"burns"`
+	Cenotaphs           []blockCenotaph      `json:"cenotaphs"`
+}
+
+type getBlockActivityResponse = HttpResponse[getBlockActivityResult]
+
+// GetBlockActivity returns the etchings, mints, burns and cenotaphs in the block, along with its event hashes.
+func (h *HttpHandler) GetBlockActivity(ctx *fiber.Ctx) (err error) {
+	var req getBlockActivityRequest
+	if err := ctx.ParamsParser(&req); err != nil {
+		return errs.NewPublicError("invalid 'height'")
+	}
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if req.Height > snapshot.Height {
+		return errs.NewPublicError(fmt.Sprintf("height must be less than or equal to the snapshot block height, got height=%d, snapshot=%d", req.Height, snapshot.Height))
+	}
+
+	activity, err := h.usecase.GetBlockActivity(ctx.UserContext(), req.Height)
+	if err != nil {
+		if errors.Is(err, errs.NotFound) {
+			return errs.NewPublicError(fmt.Sprintf("block height %d is not indexed", req.Height))
+		}
+		return errors.Wrap(err, "error during GetBlockActivity")
+	}
+
+	runeIds := slices.Concat(activity.EtchedRuneIds, lo.Keys(activity.Mints), lo.Keys(activity.Burns))
+	runeEntries, err := h.usecase.GetRuneEntryByRuneIdAndHeightBatch(ctx.UserContext(), lo.Uniq(runeIds), snapshot.Height)
+	if err != nil {
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeightBatch")
+	}
+
+	etchings := make([]getTokenInfoResult, 0, len(activity.EtchedRuneIds))
+	for _, runeId := range activity.EtchedRuneIds {
+		etching, err := createTokenInfoResult(runeEntries[runeId], nil)
+		if err != nil {
+			return errors.Wrap(err, "error during createTokenInfoResult")
+		}
+		etchings = append(etchings, *etching)
+	}
+
+	mints := make([]blockMint, 0, len(activity.Mints))
+	for runeId, mint := range activity.Mints {
+		runeEntry := runeEntries[runeId]
+		mints = append(mints, blockMint{
+			Id:       runeId,
+			Name:     runeEntry.SpacedRune,
+			Symbol:   string(runeEntry.Symbol),
+			Decimals: runeEntry.Divisibility,
+			Count:    mint.Count,
+			Amount:   mint.Amount,
+		})
+	}
+	slices.SortFunc(mints, func(m1, m2 blockMint) int {
+		return m1.Id.Cmp(m2.Id)
+	})
+
+	burns := make([]blockBurn, 0, len(activity.Burns))
+	for runeId, amount := range activity.Burns {
+		runeEntry := runeEntries[runeId]
+		burns = append(burns, blockBurn{
+			Id:       runeId,
+			Name:     runeEntry.SpacedRune,
+			Symbol:   string(runeEntry.Symbol),
+			Decimals: runeEntry.Divisibility,
+			Amount:   amount,
+		})
+	}
+	slices.SortFunc(burns, func(b1, b2 blockBurn) int {
+		return b1.Id.Cmp(b2.Id)
+	})
+
+	cenotaphs := make([]blockCenotaph, 0, len(activity.Cenotaphs))
+	for _, tx := range activity.Cenotaphs {
+		cenotaphs = append(cenotaphs, blockCenotaph{
+			TxHash:     tx.Hash,
+			Index:      tx.Index,
+			Flaws:      tx.Runestone.Flaws.CollectAsString(),
+			RuneEtched: tx.RuneEtched,
+		})
+	}
+
+	resp := getBlockActivityResponse{
+		Result: &getBlockActivityResult{
+			BlockHeight:         uint64(activity.Block.Height),
+			BlockHash:           activity.Block.Hash,
+			PrevBlockHash:       activity.Block.PrevHash,
+			EventHash:           activity.Block.EventHash,
+			CumulativeEventHash: activity.Block.CumulativeEventHash,
+			Timestamp:           lo.Ternary(activity.Timestamp.IsZero(), nil, lo.ToPtr(activity.Timestamp.Unix())),
+			TransactionCount:    activity.TransactionCount,
+			TransferCount:       activity.TransferCount,
+			Etchings:            etchings,
+			Mints:               mints,
+			Burns:               burns,
+			Cenotaphs:           cenotaphs,
+		},
+	}
+
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
+}
@@ -7,6 +7,10 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type getCurrentBlockRequest struct {
+	snapshotRequest
+}
+
 type getCurrentBlockResult struct {
 	Hash   string `json:"hash"`
 	Height int64  `json:"height"`
@@ -15,20 +19,42 @@ type getCurrentBlockResult struct {
 type getCurrentBlockResponse = HttpResponse[getCurrentBlockResult]
 
 func (h *HttpHandler) GetCurrentBlock(ctx *fiber.Ctx) (err error) {
-	blockHeader, err := h.usecase.GetLatestBlock(ctx.UserContext())
-	if err != nil {
-		if !errors.Is(err, errs.NotFound) {
-			return errors.Wrap(err, "error during GetLatestBlock")
+	var req getCurrentBlockRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+
+	// without a pinned block, fallback to the starting block if no block is indexed yet
+	if req.AtHeight == 0 && req.AtHash == "" {
+		blockHeader, err := h.usecase.GetLatestBlock(ctx.UserContext())
+		if err != nil {
+			if !errors.Is(err, errs.NotFound) {
+				return errors.Wrap(err, "error during GetLatestBlock")
+			}
+			blockHeader = constants.StartingBlockHeader[h.network]
 		}
-		blockHeader = constants.StartingBlockHeader[h.network]
+		return errors.WithStack(sendSnapshotJSON(ctx, &snapshot{
+			Height: uint64(blockHeader.Height),
+			Hash:   blockHeader.Hash,
+		}, getCurrentBlockResponse{
+			Result: &getCurrentBlockResult{
+				Hash:   blockHeader.Hash.String(),
+				Height: blockHeader.Height,
+			},
+		}))
+	}
+
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
 	}
 
 	resp := getCurrentBlockResponse{
 		Result: &getCurrentBlockResult{
-			Hash:   blockHeader.Hash.String(),
-			Height: blockHeader.Height,
+			Hash:   snapshot.Hash.String(),
+			Height: int64(snapshot.Height),
 		},
 	}
 
-	return errors.WithStack(ctx.JSON(resp))
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/gaze-network/indexer-network/common/errs"
 	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
 	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/indexer-network/pkg/btcutils"
 	"github.com/gaze-network/uint128"
 	"github.com/gofiber/fiber/v2"
 	"github.com/shopspring/decimal"
@@ -18,6 +19,7 @@ import (
 
 type getHoldersRequest struct {
 	paginationRequest
+	snapshotRequest
 	Id          string `params:"id"`
 	BlockHeight uint64 `query:"blockHeight"`
 }
@@ -42,6 +44,9 @@ func (r *getHoldersRequest) Validate() error {
 	if r.Limit > getHoldersMaxLimit {
 		errList = append(errList, errors.Errorf("'limit' cannot exceed %d", getHoldersMaxLimit))
 	}
+	if err := r.validateCursor(true); err != nil {
+		errList = append(errList, err)
+	}
 	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
 }
 
@@ -58,6 +63,7 @@ type getHoldersResult struct {
 	MintedAmount uint128.Uint128  `json:"mintedAmount"`
 	Decimals     uint8            `json:"decimals"`
 	List         []holdingBalance `json:"list"`
+	NextCursor   string           `json:"nextCursor,omitempty"`
 }
 
 type getHoldersResponse = HttpResponse[getHoldersResult]
@@ -77,14 +83,25 @@ func (h *HttpHandler) GetHolders(ctx *fiber.Ctx) (err error) {
 		return errors.WithStack(err)
 	}
 
-	blockHeight := req.BlockHeight
-	if blockHeight == 0 {
-		blockHeader, err := h.usecase.GetLatestBlock(ctx.UserContext())
-		if err != nil {
-			return errors.Wrap(err, "error during GetLatestBlock")
+	cursor, err := decodeCursor[entity.BalanceCursor](req.Cursor)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := req.pin(req.BlockHeight, "blockHeight"); err != nil {
+		return errors.WithStack(err)
+	}
+	if cursor != nil {
+		// pages after the first page are pinned to the snapshot of the first page
+		if err := req.pin(cursor.Height, "cursor"); err != nil {
+			return errors.WithStack(err)
 		}
-		blockHeight = uint64(blockHeader.Height)
 	}
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	blockHeight := snapshot.Height
 
 	var runeId runes.RuneId
 	if req.Id != "" {
@@ -102,7 +119,11 @@ func (h *HttpHandler) GetHolders(ctx *fiber.Ctx) (err error) {
 		}
 		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeight")
 	}
-	holdingBalances, err := h.usecase.GetBalancesByRuneId(ctx.UserContext(), runeId, blockHeight, req.Limit, req.Offset)
+	var cursorKey *entity.BalanceCursor
+	if cursor != nil {
+		cursorKey = &cursor.Key
+	}
+	holdingBalances, err := h.usecase.GetBalancesByRuneId(ctx.UserContext(), runeId, blockHeight, cursorKey, req.Limit, req.Offset)
 	if err != nil {
 		if errors.Is(err, errs.NotFound) {
 			return errs.NewPublicError("balances not found")
@@ -121,7 +142,7 @@ func (h *HttpHandler) GetHolders(ctx *fiber.Ctx) (err error) {
 
 	list := make([]holdingBalance, 0, len(holdingBalances))
 	for _, balance := range holdingBalances {
-		address := addressFromPkScript(balance.PkScript, h.network)
+		address := btcutils.EncodeAddressFromPkScript(balance.PkScript, h.network.ChainParams())
 		amount := decimal.NewFromBigInt(balance.Amount.Big(), 0)
 		percent := amount.Div(decimal.NewFromBigInt(totalSupply.Big(), 0))
 		list = append(list, holdingBalance{
@@ -140,6 +161,16 @@ func (h *HttpHandler) GetHolders(ctx *fiber.Ctx) (err error) {
 		return b2.Amount.Cmp(b1.Amount)
 	})
 
+	nextCursor, err := encodeNextCursor(blockHeight, holdingBalances, req.Limit, func(balance *entity.Balance) entity.BalanceCursor {
+		return entity.BalanceCursor{
+			Amount:   balance.Amount,
+			PkScript: balance.PkScript,
+		}
+	})
+	if err != nil {
+		return errors.Wrap(err, "error during encodeNextCursor")
+	}
+
 	resp := getHoldersResponse{
 		Result: &getHoldersResult{
 			BlockHeight:  blockHeight,
@@ -147,8 +178,9 @@ func (h *HttpHandler) GetHolders(ctx *fiber.Ctx) (err error) {
 			MintedAmount: mintedAmount,
 			Decimals:     runeEntry.Divisibility,
 			List:         list,
+			NextCursor:   nextCursor,
 		},
 	}
 
-	return errors.WithStack(ctx.JSON(resp))
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
 }
@@ -0,0 +1,158 @@
+package httphandler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/indexer-network/pkg/btcutils"
+	"github.com/gaze-network/uint128"
+	"github.com/gofiber/fiber/v2"
+)
+
+type getHoldersDiffRequest struct {
+	paginationRequest
+	snapshotRequest
+	Id        string `params:"id"`
+	FromBlock uint64 `query:"fromBlock"`
+	ToBlock   uint64 `query:"toBlock"`
+	Type      string `query:"type"` // "appeared", "disappeared", "increased" or "decreased", returns all types if empty
+}
+
+const (
+	getHoldersDiffMaxLimit = 1000
+)
+
+func (r *getHoldersDiffRequest) Validate() error {
+	var errList []error
+	id, err := url.QueryUnescape(r.Id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	r.Id = id
+	if !isRuneIdOrRuneName(r.Id) {
+		errList = append(errList, errors.Errorf("id '%s' is not valid rune id or rune name", r.Id))
+	}
+	if r.FromBlock == 0 {
+		errList = append(errList, errors.New("'fromBlock' is required"))
+	}
+	if r.ToBlock != 0 && r.FromBlock >= r.ToBlock {
+		errList = append(errList, errors.New("'fromBlock' must be less than 'toBlock'"))
+	}
+	if r.Type != "" && !entity.HolderChangeType(r.Type).IsValid() {
+		errList = append(errList, errors.Errorf("'type' must be one of '%s', '%s', '%s' or '%s'", entity.HolderChangeTypeAppeared, entity.HolderChangeTypeDisappeared, entity.HolderChangeTypeIncreased, entity.HolderChangeTypeDecreased))
+	}
+	if r.Limit < 0 {
+		errList = append(errList, errors.New("'limit' must be non-negative"))
+	}
+	if r.Limit > getHoldersDiffMaxLimit {
+		errList = append(errList, errors.Errorf("'limit' cannot exceed %d", getHoldersDiffMaxLimit))
+	}
+	if err := r.validateCursor(false); err != nil {
+		errList = append(errList, err)
+	}
+	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
+}
+
+type holderChange struct {
+	Address    string                  `json:"address"`
+	PkScript   string                  `json:"pkScript"`
+	Type       entity.HolderChangeType `json:"type"`
+	FromAmount uint128.Uint128         `json:"fromAmount"`
+	ToAmount   uint128.Uint128         `json:"toAmount"`
+	Delta      string                  `json:"delta"` // toAmount - fromAmount, negative if the balance decreased
+}
+
+type getHoldersDiffResult struct {
+	Id        runes.RuneId     `json:"id"`
+	Name      runes.SpacedRune `json:"name"`
+	Symbol    string           `json:"symbol"`
+	Decimals  uint8            `json:"decimals"`
+	FromBlock uint64           `json:"fromBlock"`
+	ToBlock   uint64           `json:"toBlock"`
+	List      []holderChange   `json:"list"`
+}
+
+type getHoldersDiffResponse = HttpResponse[getHoldersDiffResult]
+
+// GetHoldersDiff returns the holders of the rune whose balance changed between 'fromBlock' and 'toBlock', ordered by the absolute change descending.
+func (h *HttpHandler) GetHoldersDiff(ctx *fiber.Ctx) (err error) {
+	var req getHoldersDiffRequest
+	if err := ctx.ParamsParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.ParseDefault(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// default to snapshot block
+	if req.ToBlock == 0 {
+		req.ToBlock = snapshot.Height
+	}
+	if req.ToBlock > snapshot.Height {
+		return errs.NewPublicError(fmt.Sprintf("toBlock must be less than or equal to the snapshot block height, got toBlock=%d, snapshot=%d", req.ToBlock, snapshot.Height))
+	}
+	if req.FromBlock >= req.ToBlock {
+		return errs.NewPublicError(fmt.Sprintf("fromBlock must be less than toBlock, got fromBlock=%d, toBlock=%d", req.FromBlock, req.ToBlock))
+	}
+
+	runeId, ok := h.resolveRuneId(ctx.UserContext(), req.Id)
+	if !ok {
+		return errs.NewPublicError(fmt.Sprintf("unable to resolve rune id \"%s\" from \"id\"", req.Id))
+	}
+	runeEntry, err := h.usecase.GetRuneEntryByRuneIdAndHeight(ctx.UserContext(), runeId, snapshot.Height)
+	if err != nil {
+		if errors.Is(err, errs.NotFound) {
+			return errs.NewPublicError("rune not found")
+		}
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeight")
+	}
+
+	changes, err := h.usecase.GetHolderChangesByRuneId(ctx.UserContext(), runeId, req.FromBlock, req.ToBlock, entity.HolderChangeType(req.Type), req.Limit, req.Offset)
+	if err != nil {
+		return errors.Wrap(err, "error during GetHolderChangesByRuneId")
+	}
+
+	list := make([]holderChange, 0, len(changes))
+	for _, change := range changes {
+		delta := new(big.Int).Sub(change.ToAmount.Big(), change.FromAmount.Big())
+		list = append(list, holderChange{
+			Address:    btcutils.EncodeAddressFromPkScript(change.PkScript, h.network.ChainParams()),
+			PkScript:   hex.EncodeToString(change.PkScript),
+			Type:       change.Type(),
+			FromAmount: change.FromAmount,
+			ToAmount:   change.ToAmount,
+			Delta:      delta.String(),
+		})
+	}
+
+	resp := getHoldersDiffResponse{
+		Result: &getHoldersDiffResult{
+			Id:        runeId,
+			Name:      runeEntry.SpacedRune,
+			Symbol:    string(runeEntry.Symbol),
+			Decimals:  runeEntry.Divisibility,
+			FromBlock: req.FromBlock,
+			ToBlock:   req.ToBlock,
+			List:      list,
+		},
+	}
+
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
+}
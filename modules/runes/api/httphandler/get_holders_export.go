@@ -0,0 +1,97 @@
+package httphandler
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/usecase"
+	"github.com/gaze-network/indexer-network/pkg/logger"
+	"github.com/gaze-network/indexer-network/pkg/logger/slogx"
+	"github.com/gofiber/fiber/v2"
+)
+
+type getHoldersExportRequest struct {
+	snapshotRequest
+	Id          string `params:"id"`
+	BlockHeight uint64 `query:"blockHeight"`
+	Format      string `query:"format"` // "csv" or "ndjson", defaults to "csv"
+}
+
+func (r *getHoldersExportRequest) Validate() error {
+	var errList []error
+	id, err := url.QueryUnescape(r.Id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	r.Id = id
+	if !isRuneIdOrRuneName(r.Id) {
+		errList = append(errList, errors.Errorf("id '%s' is not valid rune id or rune name", r.Id))
+	}
+	if r.Format == "" {
+		r.Format = string(usecase.HoldersExportFormatCSV)
+	}
+	if !usecase.HoldersExportFormat(r.Format).IsValid() {
+		errList = append(errList, errors.Errorf("'format' must be '%s' or '%s'", usecase.HoldersExportFormatCSV, usecase.HoldersExportFormatNDJSON))
+	}
+	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
+}
+
+// GetHoldersExport streams every holder of the rune at the snapshot block. Unlike GetHolders, it is not paginated.
+// Errors after the response has started are only logged, so clients must check the trailer row to detect an incomplete export.
+func (h *HttpHandler) GetHoldersExport(ctx *fiber.Ctx) (err error) {
+	var req getHoldersExportRequest
+	if err := ctx.ParamsParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := req.pin(req.BlockHeight, "blockHeight"); err != nil {
+		return errors.WithStack(err)
+	}
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	runeId, ok := h.resolveRuneId(ctx.UserContext(), req.Id)
+	if !ok {
+		return errs.NewPublicError(fmt.Sprintf("unable to resolve rune id \"%s\" from \"id\"", req.Id))
+	}
+	// check the rune before streaming, as errors cannot be returned once the response has started
+	if _, err := h.usecase.GetRuneEntryByRuneIdAndHeight(ctx.UserContext(), runeId, snapshot.Height); err != nil {
+		if errors.Is(err, errs.NotFound) {
+			return errs.NewPublicError("rune not found")
+		}
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeight")
+	}
+
+	format := usecase.HoldersExportFormat(req.Format)
+	contentType := "text/csv"
+	if format == usecase.HoldersExportFormatNDJSON {
+		contentType = "application/x-ndjson"
+	}
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="holders-%s-%d.%s"`, runeId, snapshot.Height, format))
+	setSnapshotHeaders(ctx, snapshot)
+
+	// the fiber context must not be used after the handler returns, so only keep the user context
+	userCtx := ctx.UserContext()
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if _, err := h.usecase.ExportHolders(userCtx, w, runeId, snapshot.Height, format, h.network); err != nil {
+			logger.ErrorContext(userCtx, "Failed to export holders", slogx.Error(err), slogx.Stringer("runeId", runeId), slogx.Uint64("blockHeight", snapshot.Height))
+			return
+		}
+		if err := w.Flush(); err != nil {
+			logger.WarnContext(userCtx, "Failed to flush holders export", slogx.Error(err))
+		}
+	})
+	return nil
+}
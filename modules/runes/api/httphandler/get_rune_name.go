@@ -0,0 +1,156 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+)
+
+type getRuneNameRequest struct {
+	snapshotRequest
+	Name string `params:"name"`
+}
+
+type runeNameStatus string
+
+const (
+	runeNameStatusAvailable runeNameStatus = "available"
+	runeNameStatusTaken     runeNameStatus = "taken"
+	runeNameStatusReserved  runeNameStatus = "reserved"
+	runeNameStatusLocked    runeNameStatus = "locked"
+)
+
+type getRuneNameResult struct {
+	Name      runes.SpacedRune `json:"name"`
+	Rune      runes.Rune       `json:"rune"`
+	Status    runeNameStatus   `json:"status"`
+	Available bool             `json:"available"` // true if the name can be etched in the block after the snapshot block
+	Id        *runes.RuneId    `json:"id"`        // id of the rune etched with the name, null if the name is not taken
+	// UnlockHeight is the first block height that the name can be etched at. Null if the name is reserved.
+	UnlockHeight      *uint64 `json:"unlockHeight"`
+	BlocksUntilUnlock *uint64 `json:"blocksUntilUnlock"` // null if the name is reserved or already unlocked
+}
+
+type getRuneNameResponse = HttpResponse[getRuneNameResult]
+
+// GetRuneName returns whether the rune name can be etched in the block after the snapshot block, and if not, why.
+func (h *HttpHandler) GetRuneName(ctx *fiber.Ctx) (err error) {
+	var req getRuneNameRequest
+	if err := ctx.ParamsParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	name, err := url.QueryUnescape(req.Name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	spacedRune, err := runes.NewSpacedRuneFromString(name)
+	// names that are too long overflow the rune, so they do not encode back to the same name
+	if err != nil || spacedRune.Rune.String() != strings.NewReplacer(".", "", "•", "").Replace(name) {
+		return errs.NewPublicError(fmt.Sprintf("'%s' is not a valid rune name", name))
+	}
+
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	nextHeight := snapshot.Height + 1
+
+	result := &getRuneNameResult{
+		Name: spacedRune,
+		Rune: spacedRune.Rune,
+	}
+
+	// spacers are not part of the rune, so a name is taken if the rune is etched with any spacers
+	runeId, err := h.usecase.GetRuneIdFromRune(ctx.UserContext(), spacedRune.Rune)
+	if err != nil && !errors.Is(err, errs.NotFound) {
+		return errors.Wrap(err, "error during GetRuneIdFromRune")
+	}
+	if err == nil && runeId.BlockHeight <= snapshot.Height {
+		result.Status = runeNameStatusTaken
+		result.Id = &runeId
+	}
+
+	if !spacedRune.Rune.IsReserved() {
+		unlockHeight := runes.UnlockHeight(h.network, spacedRune.Rune)
+		result.UnlockHeight = &unlockHeight
+		if nextHeight < unlockHeight {
+			result.BlocksUntilUnlock = lo.ToPtr(unlockHeight - nextHeight)
+		}
+	}
+
+	if result.Status != runeNameStatusTaken {
+		switch {
+		case spacedRune.Rune.IsReserved():
+			result.Status = runeNameStatusReserved
+		case result.BlocksUntilUnlock != nil:
+			result.Status = runeNameStatusLocked
+		default:
+			result.Status = runeNameStatusAvailable
+		}
+	}
+	result.Available = result.Status == runeNameStatusAvailable
+
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, getRuneNameResponse{Result: result}))
+}
+
+type runeNameUnlock struct {
+	Length int `json:"length"` // 13 covers names of 13 or more characters
+	// StartHeight is the first block height that a name of the length can be etched at.
+	StartHeight uint64 `json:"startHeight"`
+	// EndHeight is the first block height that every name of the length can be etched at.
+	EndHeight uint64 `json:"endHeight"`
+	Unlocked  bool   `json:"unlocked"` // true if every name of the length can be etched in the block after the snapshot block
+}
+
+type getRuneNameUnlockScheduleResult struct {
+	Network         string           `json:"network"`
+	FirstRuneHeight uint64           `json:"firstRuneHeight"`
+	MinimumRune     runes.Rune       `json:"minimumRune"` // shortest name that can be etched in the block after the snapshot block
+	List            []runeNameUnlock `json:"list"`
+}
+
+type getRuneNameUnlockScheduleResponse = HttpResponse[getRuneNameUnlockScheduleResult]
+
+// GetRuneNameUnlockSchedule returns the block heights that names of each length unlock at on the configured network.
+func (h *HttpHandler) GetRuneNameUnlockSchedule(ctx *fiber.Ctx) (err error) {
+	var req snapshotRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	nextHeight := snapshot.Height + 1
+
+	list := make([]runeNameUnlock, 0, 13)
+	for length := 1; length <= 13; length++ {
+		startHeight, endHeight := runes.UnlockHeightsOfLength(h.network, length)
+		list = append(list, runeNameUnlock{
+			Length:      length,
+			StartHeight: startHeight,
+			EndHeight:   endHeight,
+			Unlocked:    nextHeight >= endHeight,
+		})
+	}
+
+	resp := getRuneNameUnlockScheduleResponse{
+		Result: &getRuneNameUnlockScheduleResult{
+			Network:         h.network.String(),
+			FirstRuneHeight: runes.FirstRuneHeight(h.network),
+			MinimumRune:     runes.MinimumRuneAtHeight(h.network, nextHeight),
+			List:            list,
+		},
+	}
+
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
+}
@@ -14,6 +14,7 @@ import (
 )
 
 type getTokenInfoRequest struct {
+	snapshotRequest
 	Id                  string `params:"id"`
 	BlockHeight         uint64 `query:"blockHeight"`
 	AdditionalFieldsRaw string `query:"additionalFields"` // comma-separated list of additional fields
@@ -96,17 +97,14 @@ func (h *HttpHandler) GetTokenInfo(ctx *fiber.Ctx) (err error) {
 		return errors.WithStack(err)
 	}
 
-	blockHeight := req.BlockHeight
-	if blockHeight == 0 {
-		blockHeader, err := h.usecase.GetLatestBlock(ctx.UserContext())
-		if err != nil {
-			if errors.Is(err, errs.NotFound) {
-				return errs.NewPublicError("latest block not found")
-			}
-			return errors.Wrap(err, "error during GetLatestBlock")
-		}
-		blockHeight = uint64(blockHeader.Height)
+	if err := req.pin(req.BlockHeight, "blockHeight"); err != nil {
+		return errors.WithStack(err)
+	}
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
 	}
+	blockHeight := snapshot.Height
 
 	var runeId runes.RuneId
 	if req.Id != "" {
@@ -145,7 +143,7 @@ func (h *HttpHandler) GetTokenInfo(ctx *fiber.Ctx) (err error) {
 		Result: result,
 	}
 
-	return errors.WithStack(ctx.JSON(resp))
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
 }
 
 func createTokenInfoResult(runeEntry *runes.RuneEntry, holdersCount *int64) (*getTokenInfoResult, error) {
@@ -12,6 +12,7 @@ import (
 )
 
 type getTokenInfoBatchRequest struct {
+	snapshotRequest
 	Ids              []string `json:"ids"`
 	BlockHeight      uint64   `json:"blockHeight"`
 	AdditionalFields []string `json:"additionalFields"`
@@ -52,21 +53,21 @@ func (h *HttpHandler) GetTokenInfoBatch(ctx *fiber.Ctx) (err error) {
 	if err := ctx.BodyParser(&req); err != nil {
 		return errors.WithStack(err)
 	}
+	if err := ctx.QueryParser(&req.snapshotRequest); err != nil {
+		return errors.WithStack(err)
+	}
 	if err := req.Validate(); err != nil {
 		return errors.WithStack(err)
 	}
 
-	blockHeight := req.BlockHeight
-	if blockHeight == 0 {
-		blockHeader, err := h.usecase.GetLatestBlock(ctx.UserContext())
-		if err != nil {
-			if errors.Is(err, errs.NotFound) {
-				return errs.NewPublicError("latest block not found")
-			}
-			return errors.Wrap(err, "error during GetLatestBlock")
-		}
-		blockHeight = uint64(blockHeader.Height)
+	if err := req.pin(req.BlockHeight, "blockHeight"); err != nil {
+		return errors.WithStack(err)
+	}
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
 	}
+	blockHeight := snapshot.Height
 
 	runeIds := make([]runes.RuneId, 0)
 	for i, id := range req.Ids {
@@ -114,5 +115,5 @@ func (h *HttpHandler) GetTokenInfoBatch(ctx *fiber.Ctx) (err error) {
 		},
 	}
 
-	return errors.WithStack(ctx.JSON(resp))
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
 }
@@ -0,0 +1,219 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+)
+
+type getTokenInfoHistoryRequest struct {
+	paginationRequest
+	snapshotRequest
+	Id        string `params:"id"`
+	FromBlock uint64 `query:"fromBlock"`
+	ToBlock   uint64 `query:"toBlock"`
+	FromTime  int64  `query:"fromTime"` // unix timestamp, cannot be used with fromBlock
+	ToTime    int64  `query:"toTime"`   // unix timestamp, cannot be used with toBlock
+	Interval  uint64 `query:"interval"` // number of blocks between points
+}
+
+const (
+	getTokenInfoHistoryMaxLimit = 500
+)
+
+func (r *getTokenInfoHistoryRequest) Validate() error {
+	var errList []error
+	id, err := url.QueryUnescape(r.Id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	r.Id = id
+	if !isRuneIdOrRuneName(r.Id) {
+		errList = append(errList, errors.Errorf("id '%s' is not valid rune id or rune name", r.Id))
+	}
+	if r.FromTime != 0 && r.FromBlock != 0 {
+		errList = append(errList, errors.New("'fromTime' cannot be used with 'fromBlock'"))
+	}
+	if r.ToTime != 0 && r.ToBlock != 0 {
+		errList = append(errList, errors.New("'toTime' cannot be used with 'toBlock'"))
+	}
+	if r.FromTime < 0 || r.ToTime < 0 {
+		errList = append(errList, errors.New("'fromTime' and 'toTime' must be non-negative"))
+	}
+	if r.ToBlock != 0 && r.FromBlock > r.ToBlock {
+		errList = append(errList, errors.New("'fromBlock' must be less than or equal to 'toBlock'"))
+	}
+	if r.ToTime != 0 && r.FromTime > r.ToTime {
+		errList = append(errList, errors.New("'fromTime' must be less than or equal to 'toTime'"))
+	}
+	if r.Limit < 0 {
+		errList = append(errList, errors.New("'limit' must be non-negative"))
+	}
+	if r.Limit > getTokenInfoHistoryMaxLimit {
+		errList = append(errList, errors.Errorf("'limit' cannot exceed %d", getTokenInfoHistoryMaxLimit))
+	}
+	if err := r.validateCursor(false); err != nil {
+		errList = append(errList, err)
+	}
+	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
+}
+
+func (r *getTokenInfoHistoryRequest) ParseDefault() error {
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+	return errors.WithStack(r.paginationRequest.ParseDefault())
+}
+
+type tokenInfoPoint struct {
+	BlockHeight       uint64          `json:"blockHeight"`
+	Timestamp         *int64          `json:"timestamp"` // unix timestamp of the latest rune transaction at or before the block, null if there is none
+	Mints             uint128.Uint128 `json:"mints"`
+	MintedAmount      uint128.Uint128 `json:"mintedAmount"`
+	BurnedAmount      uint128.Uint128 `json:"burnedAmount"`
+	CirculatingSupply uint128.Uint128 `json:"circulatingSupply"`
+	HoldersCount      int64           `json:"holdersCount"`
+}
+
+type getTokenInfoHistoryResult struct {
+	Id          runes.RuneId     `json:"id"`
+	Name        runes.SpacedRune `json:"name"`
+	Symbol      string           `json:"symbol"`
+	Decimals    uint8            `json:"decimals"`
+	TotalSupply uint128.Uint128  `json:"totalSupply"`
+	FromBlock   uint64           `json:"fromBlock"`
+	ToBlock     uint64           `json:"toBlock"`
+	Interval    uint64           `json:"interval"`
+	List        []tokenInfoPoint `json:"list"`
+}
+
+type getTokenInfoHistoryResponse = HttpResponse[getTokenInfoHistoryResult]
+
+// GetTokenInfoHistory returns the mints, burned amount, circulating supply and holders count of the rune every 'interval' blocks.
+// The range can be given in block heights or unix timestamps, and defaults to the etching block up to the snapshot block.
+func (h *HttpHandler) GetTokenInfoHistory(ctx *fiber.Ctx) (err error) {
+	var req getTokenInfoHistoryRequest
+	if err := ctx.ParamsParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.ParseDefault(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	runeId, ok := h.resolveRuneId(ctx.UserContext(), req.Id)
+	if !ok {
+		return errs.NewPublicError(fmt.Sprintf("unable to resolve rune id \"%s\" from \"id\"", req.Id))
+	}
+	runeEntry, err := h.usecase.GetRuneEntryByRuneIdAndHeight(ctx.UserContext(), runeId, snapshot.Height)
+	if err != nil {
+		if errors.Is(err, errs.NotFound) {
+			return errs.NewPublicError("rune not found")
+		}
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeight")
+	}
+
+	if req.FromTime != 0 {
+		req.FromBlock, err = h.usecase.GetBlockHeightAtTimestamp(ctx.UserContext(), time.Unix(req.FromTime, 0))
+		if err != nil && !errors.Is(err, errs.NotFound) {
+			return errors.Wrap(err, "error during GetBlockHeightAtTimestamp")
+		}
+	}
+	if req.ToTime != 0 {
+		req.ToBlock, err = h.usecase.GetBlockHeightAtTimestamp(ctx.UserContext(), time.Unix(req.ToTime, 0))
+		if err != nil {
+			if errors.Is(err, errs.NotFound) {
+				return errs.NewPublicError("no indexed block at or before 'toTime'")
+			}
+			return errors.Wrap(err, "error during GetBlockHeightAtTimestamp")
+		}
+		// toTime may be after the snapshot block
+		req.ToBlock = min(req.ToBlock, snapshot.Height)
+	}
+
+	// default to the etching block, or the earliest block with full history, and the snapshot block
+	if req.FromBlock == 0 {
+		prunedHeight, err := h.usecase.GetPrunedHeight(ctx.UserContext())
+		if err != nil {
+			return errors.Wrap(err, "error during GetPrunedHeight")
+		}
+		req.FromBlock = max(runeEntry.EtchingBlock, prunedHeight)
+	}
+	if req.ToBlock == 0 {
+		req.ToBlock = snapshot.Height
+	}
+	if req.ToBlock > snapshot.Height {
+		return errs.NewPublicError(fmt.Sprintf("toBlock must be less than or equal to the snapshot block height, got toBlock=%d, snapshot=%d", req.ToBlock, snapshot.Height))
+	}
+	if req.FromBlock > req.ToBlock {
+		return errs.NewPublicError(fmt.Sprintf("fromBlock must be less than or equal to toBlock, got fromBlock=%d, toBlock=%d", req.FromBlock, req.ToBlock))
+	}
+
+	// skip the points before the etching, so they don't count towards limit and offset
+	if req.FromBlock < runeEntry.EtchingBlock {
+		req.FromBlock += (runeEntry.EtchingBlock - req.FromBlock + req.Interval - 1) / req.Interval * req.Interval
+	}
+
+	states, err := h.usecase.GetRuneEntryStateHistory(ctx.UserContext(), runeId, req.FromBlock, req.ToBlock, req.Interval, req.Limit, req.Offset)
+	if err != nil {
+		return errors.Wrap(err, "error during GetRuneEntryStateHistory")
+	}
+
+	totalSupply, err := runeEntry.Supply()
+	if err != nil {
+		return errors.Wrap(err, "cannot get total supply of rune")
+	}
+
+	list := make([]tokenInfoPoint, 0, len(states))
+	for _, state := range states {
+		// the minted amount depends on the premine and terms, which do not change after etching
+		pointEntry := *runeEntry
+		pointEntry.Mints = state.Mints
+		mintedAmount, err := pointEntry.MintedAmount()
+		if err != nil {
+			return errors.Wrap(err, "cannot get minted amount of rune")
+		}
+		list = append(list, tokenInfoPoint{
+			BlockHeight:       state.BlockHeight,
+			Timestamp:         lo.Ternary(state.Timestamp.IsZero(), nil, lo.ToPtr(state.Timestamp.Unix())),
+			Mints:             state.Mints,
+			MintedAmount:      mintedAmount,
+			BurnedAmount:      state.BurnedAmount,
+			CirculatingSupply: mintedAmount.Sub(state.BurnedAmount),
+			HoldersCount:      state.HoldersCount,
+		})
+	}
+
+	resp := getTokenInfoHistoryResponse{
+		Result: &getTokenInfoHistoryResult{
+			Id:          runeId,
+			Name:        runeEntry.SpacedRune,
+			Symbol:      string(runeEntry.Symbol),
+			Decimals:    runeEntry.Divisibility,
+			TotalSupply: totalSupply,
+			FromBlock:   req.FromBlock,
+			ToBlock:     req.ToBlock,
+			Interval:    req.Interval,
+			List:        list,
+		},
+	}
+
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
+}
@@ -0,0 +1,214 @@
+package httphandler
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+type getTokenMintabilityRequest struct {
+	snapshotRequest
+	Id     string `params:"id"`
+	Height uint64 `query:"height"` // block height to check minting at, defaults to the block after the snapshot block
+}
+
+func (r *getTokenMintabilityRequest) Validate() error {
+	var errList []error
+	id, err := url.QueryUnescape(r.Id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	r.Id = id
+	if !isRuneIdOrRuneName(r.Id) {
+		errList = append(errList, errors.Errorf("id '%s' is not valid rune id or rune name", r.Id))
+	}
+	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
+}
+
+// mintRateWindow is the number of blocks before the snapshot block that the mint rate is measured over, about a day of blocks.
+const mintRateWindow = 144
+
+type mintabilityStatus string
+
+const (
+	mintabilityStatusOpen       mintabilityStatus = "open"
+	mintabilityStatusUnmintable mintabilityStatus = "unmintable"
+	mintabilityStatusNotStarted mintabilityStatus = "notStarted"
+	mintabilityStatusEnded      mintabilityStatus = "ended"
+	mintabilityStatusCapReached mintabilityStatus = "capReached"
+)
+
+type getTokenMintabilityResult struct {
+	Id               runes.RuneId      `json:"id"`
+	Name             runes.SpacedRune  `json:"name"`
+	Symbol           string            `json:"symbol"`
+	Decimals         uint8             `json:"decimals"`
+	Height           uint64            `json:"height"` // block height that minting is checked at
+	Mintable         bool              `json:"mintable"`
+	Status           mintabilityStatus `json:"status"`
+	AmountPerMint    uint128.Uint128   `json:"amountPerMint"`
+	Cap              uint128.Uint128   `json:"cap"`
+	Mints            uint128.Uint128   `json:"mints"` // mints before 'height', or as of the snapshot block if 'height' is after it
+	RemainingMints   uint128.Uint128   `json:"remainingMints"`
+	StartHeight      *uint64           `json:"startHeight"`      // null if the rune has no terms
+	EndHeight        *uint64           `json:"endHeight"`        // first block height that the rune can no longer be minted at, null if minting never ends
+	BlocksUntilStart *uint64           `json:"blocksUntilStart"` // null if minting has started at 'height'
+	BlocksUntilEnd   *uint64           `json:"blocksUntilEnd"`   // null if minting never ends or has ended at 'height'
+	// MintRate is the average number of mints per block over the last 'mintRateWindow' blocks before the snapshot block.
+	MintRate       float64 `json:"mintRate"`
+	MintRateWindow uint64  `json:"mintRateWindow"`
+	// ProjectedCompletionHeight is the block height that the cap is projected to be reached at with the current mint rate.
+	// Null if the cap is not projected to be reached before minting ends, or if the rune is not minted recently.
+	ProjectedCompletionHeight *uint64 `json:"projectedCompletionHeight"`
+}
+
+type getTokenMintabilityResponse = HttpResponse[getTokenMintabilityResult]
+
+// GetTokenMintability returns whether the rune can be minted at the given block height, along with the progress of minting.
+func (h *HttpHandler) GetTokenMintability(ctx *fiber.Ctx) (err error) {
+	var req getTokenMintabilityRequest
+	if err := ctx.ParamsParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if req.Height == 0 {
+		req.Height = snapshot.Height + 1
+	}
+
+	runeId, ok := h.resolveRuneId(ctx.UserContext(), req.Id)
+	if !ok {
+		return errs.NewPublicError(fmt.Sprintf("unable to resolve rune id \"%s\" from \"id\"", req.Id))
+	}
+	runeEntry, err := h.usecase.GetRuneEntryByRuneIdAndHeight(ctx.UserContext(), runeId, snapshot.Height)
+	if err != nil {
+		if errors.Is(err, errs.NotFound) {
+			return errs.NewPublicError("rune not found")
+		}
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeight")
+	}
+
+	// minting in a block depends on the mints before the block
+	entryAtHeight := runeEntry
+	if stateHeight := req.Height - 1; stateHeight < snapshot.Height {
+		if stateHeight < runeEntry.EtchingBlock {
+			entryAtHeight = lo.ToPtr(*runeEntry)
+			entryAtHeight.Mints = uint128.Zero
+		} else {
+			entryAtHeight, err = h.usecase.GetRuneEntryByRuneIdAndHeight(ctx.UserContext(), runeId, stateHeight)
+			if err != nil {
+				return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeight")
+			}
+		}
+	}
+
+	result := &getTokenMintabilityResult{
+		Id:             runeId,
+		Name:           runeEntry.SpacedRune,
+		Symbol:         string(runeEntry.Symbol),
+		Decimals:       runeEntry.Divisibility,
+		Height:         req.Height,
+		Mints:          entryAtHeight.Mints,
+		MintRateWindow: mintRateWindow,
+	}
+
+	result.Status, err = getMintabilityStatus(entryAtHeight, req.Height)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	result.Mintable = result.Status == mintabilityStatusOpen
+
+	if runeEntry.Terms == nil {
+		return errors.WithStack(sendSnapshotJSON(ctx, snapshot, getTokenMintabilityResponse{Result: result}))
+	}
+
+	result.AmountPerMint = lo.FromPtr(runeEntry.Terms.Amount)
+	result.Cap = lo.FromPtr(runeEntry.Terms.Cap)
+	if entryAtHeight.Mints.Cmp(result.Cap) < 0 {
+		result.RemainingMints = result.Cap.Sub(entryAtHeight.Mints)
+	}
+
+	// the rune can't be minted before it is etched, even if its terms have no start
+	startHeight := max(runeEntry.MintStartHeight(), runeEntry.EtchingBlock)
+	result.StartHeight = &startHeight
+	if req.Height < startHeight {
+		result.BlocksUntilStart = lo.ToPtr(startHeight - req.Height)
+	}
+	endHeight, hasEnd := runeEntry.MintEndHeight()
+	if hasEnd {
+		result.EndHeight = &endHeight
+		if req.Height < endHeight {
+			result.BlocksUntilEnd = lo.ToPtr(endHeight - req.Height)
+		}
+	}
+
+	// measure the mint rate over the window before the snapshot block, or since etching if the rune is newer
+	windowStart := max(snapshot.Height-min(snapshot.Height, mintRateWindow), runeEntry.EtchingBlock)
+	mintsAtWindowStart := uint128.Zero
+	if windowStart > runeEntry.EtchingBlock {
+		entryAtWindowStart, err := h.usecase.GetRuneEntryByRuneIdAndHeight(ctx.UserContext(), runeId, windowStart)
+		if err != nil {
+			return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeight")
+		}
+		mintsAtWindowStart = entryAtWindowStart.Mints
+	}
+	var mintRate decimal.Decimal
+	if windowBlocks := snapshot.Height - windowStart; windowBlocks > 0 {
+		windowMints := decimal.NewFromBigInt(runeEntry.Mints.Sub(mintsAtWindowStart).Big(), 0)
+		mintRate = windowMints.Div(decimal.NewFromInt(int64(windowBlocks)))
+		result.MintRate = mintRate.InexactFloat64()
+	}
+
+	if mintRate.IsPositive() && runeEntry.Mints.Cmp(result.Cap) < 0 {
+		remainingMints := decimal.NewFromBigInt(result.Cap.Sub(runeEntry.Mints).Big(), 0)
+		remainingBlocks := remainingMints.Div(mintRate).Ceil()
+		if remainingBlocks.LessThan(decimal.NewFromInt(math.MaxUint32)) {
+			completionHeight := max(snapshot.Height+uint64(remainingBlocks.IntPart()), startHeight)
+			if !hasEnd || completionHeight < endHeight {
+				result.ProjectedCompletionHeight = &completionHeight
+			}
+		}
+	}
+
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, getTokenMintabilityResponse{Result: result}))
+}
+
+// getMintabilityStatus returns the mintability status of the rune at the given block height. entry must be the rune entry as of the block before it.
+func getMintabilityStatus(entry *runes.RuneEntry, height uint64) (mintabilityStatus, error) {
+	// the rune can't be minted before it is etched, even if its terms have no start
+	if entry.Terms != nil && height < entry.EtchingBlock {
+		return mintabilityStatusNotStarted, nil
+	}
+	_, err := entry.GetMintableAmount(height)
+	switch {
+	case err == nil:
+		return mintabilityStatusOpen, nil
+	case errors.Is(err, runes.ErrUnmintable):
+		return mintabilityStatusUnmintable, nil
+	case errors.Is(err, runes.ErrMintBeforeStart):
+		return mintabilityStatusNotStarted, nil
+	case errors.Is(err, runes.ErrMintAfterEnd):
+		return mintabilityStatusEnded, nil
+	case errors.Is(err, runes.ErrMintCapReached):
+		return mintabilityStatusCapReached, nil
+	default:
+		return "", errors.Wrap(err, "error during GetMintableAmount")
+	}
+}
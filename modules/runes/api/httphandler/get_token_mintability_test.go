@@ -0,0 +1,90 @@
+package httphandler
+
+import (
+	"testing"
+
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMintabilityStatus(t *testing.T) {
+	type testcase struct {
+		name     string
+		terms    *runes.Terms
+		mints    uint128.Uint128
+		height   uint64
+		expected mintabilityStatus
+	}
+	const etchingBlock = 840000
+	noStartTerms := &runes.Terms{
+		Amount: lo.ToPtr(uint128.From64(100)),
+		Cap:    lo.ToPtr(uint128.From64(10)),
+	}
+	heightTerms := &runes.Terms{
+		Amount:      lo.ToPtr(uint128.From64(100)),
+		Cap:         lo.ToPtr(uint128.From64(10)),
+		HeightStart: lo.ToPtr(uint64(840100)),
+		HeightEnd:   lo.ToPtr(uint64(840200)),
+	}
+
+	testcases := []testcase{
+		{
+			name:     "no terms",
+			height:   etchingBlock + 1,
+			expected: mintabilityStatusUnmintable,
+		},
+		{
+			name:     "no start terms before etching block",
+			terms:    noStartTerms,
+			height:   etchingBlock - 1,
+			expected: mintabilityStatusNotStarted,
+		},
+		{
+			name:     "no start terms at etching block",
+			terms:    noStartTerms,
+			height:   etchingBlock,
+			expected: mintabilityStatusOpen,
+		},
+		{
+			name:     "before start height",
+			terms:    heightTerms,
+			height:   840099,
+			expected: mintabilityStatusNotStarted,
+		},
+		{
+			name:     "at start height",
+			terms:    heightTerms,
+			height:   840100,
+			expected: mintabilityStatusOpen,
+		},
+		{
+			name:     "at end height",
+			terms:    heightTerms,
+			height:   840200,
+			expected: mintabilityStatusEnded,
+		},
+		{
+			name:     "cap reached",
+			terms:    heightTerms,
+			mints:    uint128.From64(10),
+			height:   840150,
+			expected: mintabilityStatusCapReached,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := &runes.RuneEntry{
+				RuneId:       runes.RuneId{BlockHeight: etchingBlock, TxIndex: 1},
+				EtchingBlock: etchingBlock,
+				Terms:        tc.terms,
+				Mints:        tc.mints,
+			}
+			status, err := getMintabilityStatus(entry, tc.height)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, status)
+		})
+	}
+}
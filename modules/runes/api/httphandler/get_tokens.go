@@ -6,6 +6,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
 	"github.com/gaze-network/indexer-network/modules/runes/runes"
 	"github.com/gofiber/fiber/v2"
 	"github.com/samber/lo"
@@ -32,6 +33,7 @@ func (s GetTokensScope) IsValid() bool {
 
 type getTokensRequest struct {
 	paginationRequest
+	snapshotRequest
 	Search              string         `query:"search"`
 	BlockHeight         uint64         `query:"blockHeight"`
 	Scope               GetTokensScope `query:"scope"`
@@ -44,6 +46,9 @@ func (r *getTokensRequest) Validate() error {
 	if err := r.paginationRequest.Validate(); err != nil {
 		errList = append(errList, err)
 	}
+	if err := r.validateCursor(true); err != nil {
+		errList = append(errList, err)
+	}
 	if r.Limit > getTokensMaxLimit {
 		errList = append(errList, errors.Errorf("limit must be less than or equal to 1000"))
 	}
@@ -71,7 +76,8 @@ func (req *getTokensRequest) ParseDefault() error {
 }
 
 type getTokensResult struct {
-	List []*getTokenInfoResult `json:"list"`
+	List       []*getTokenInfoResult `json:"list"`
+	NextCursor string                `json:"nextCursor,omitempty"`
 }
 
 type getTokensResponse = HttpResponse[getTokensResult]
@@ -88,30 +94,43 @@ func (h *HttpHandler) GetTokens(ctx *fiber.Ctx) (err error) {
 		return errors.WithStack(err)
 	}
 
-	blockHeight := req.BlockHeight
-	if blockHeight == 0 {
-		blockHeader, err := h.usecase.GetLatestBlock(ctx.UserContext())
-		if err != nil {
-			if errors.Is(err, errs.NotFound) {
-				return errs.NewPublicError("latest block not found")
-			}
-			return errors.Wrap(err, "error during GetLatestBlock")
+	cursor, err := decodeCursor[entity.RuneEntryCursor](req.Cursor)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := req.pin(req.BlockHeight, "blockHeight"); err != nil {
+		return errors.WithStack(err)
+	}
+	if cursor != nil {
+		// pages after the first page are pinned to the snapshot of the first page
+		if err := req.pin(cursor.Height, "cursor"); err != nil {
+			return errors.WithStack(err)
 		}
-		blockHeight = uint64(blockHeader.Height)
 	}
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	blockHeight := snapshot.Height
 
 	// remove spacers
 	search := strings.Replace(strings.Replace(req.Search, "•", "", -1), ".", "", -1)
 
+	var cursorKey *entity.RuneEntryCursor
+	if cursor != nil {
+		cursorKey = &cursor.Key
+	}
+
 	var entries []*runes.RuneEntry
 	switch req.Scope {
 	case GetTokensScopeAll:
-		entries, err = h.usecase.GetRuneEntries(ctx.UserContext(), search, blockHeight, req.Limit, req.Offset)
+		entries, err = h.usecase.GetRuneEntries(ctx.UserContext(), search, blockHeight, cursorKey, req.Limit, req.Offset)
 		if err != nil {
 			return errors.Wrap(err, "error during GetRuneEntryList")
 		}
 	case GetTokensScopeOngoing:
-		entries, err = h.usecase.GetOngoingRuneEntries(ctx.UserContext(), search, blockHeight, req.Limit, req.Offset)
+		entries, err = h.usecase.GetOngoingRuneEntries(ctx.UserContext(), search, blockHeight, cursorKey, req.Limit, req.Offset)
 		if err != nil {
 			return errors.Wrap(err, "error during GetRuneEntryList")
 		}
@@ -142,9 +161,20 @@ func (h *HttpHandler) GetTokens(ctx *fiber.Ctx) (err error) {
 		results = append(results, result)
 	}
 
-	return errors.WithStack(ctx.JSON(getTokensResponse{
+	nextCursor, err := encodeNextCursor(blockHeight, entries, req.Limit, func(entry *runes.RuneEntry) entity.RuneEntryCursor {
+		return entity.RuneEntryCursor{
+			Number: entry.Number,
+			Mints:  entry.Mints,
+		}
+	})
+	if err != nil {
+		return errors.Wrap(err, "error during encodeNextCursor")
+	}
+
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, getTokensResponse{
 		Result: &getTokensResult{
-			List: results,
+			List:       results,
+			NextCursor: nextCursor,
 		},
 	}))
 }
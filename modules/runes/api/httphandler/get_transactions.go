@@ -10,7 +10,9 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/cockroachdb/errors"
 	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
 	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/indexer-network/pkg/btcutils"
 	"github.com/gaze-network/uint128"
 	"github.com/gofiber/fiber/v2"
 	"github.com/samber/lo"
@@ -18,6 +20,7 @@ import (
 
 type getTransactionsRequest struct {
 	paginationRequest
+	snapshotRequest
 	Wallet    string `query:"wallet"`
 	Id        string `query:"id"`
 	FromBlock int64  `query:"fromBlock"`
@@ -52,6 +55,9 @@ func (r *getTransactionsRequest) Validate() error {
 	if r.Limit > getTransactionsMaxLimit {
 		errList = append(errList, errors.Errorf("'limit' cannot exceed %d", getTransactionsMaxLimit))
 	}
+	if err := r.validateCursor(true); err != nil {
+		errList = append(errList, err)
+	}
 	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
 }
 
@@ -121,7 +127,8 @@ type transaction struct {
 }
 
 type getTransactionsResult struct {
-	List []transaction `json:"list"`
+	List       []transaction `json:"list"`
+	NextCursor string        `json:"nextCursor,omitempty"`
 }
 
 type getTransactionsResponse = HttpResponse[getTransactionsResult]
@@ -156,34 +163,46 @@ func (h *HttpHandler) GetTransactions(ctx *fiber.Ctx) (err error) {
 		}
 	}
 
-	// default to latest block
-	if req.ToBlock == 0 {
-		req.ToBlock = -1
+	cursor, err := decodeCursor[entity.RuneTransactionCursor](req.Cursor)
+	if err != nil {
+		return errors.WithStack(err)
 	}
 
-	// get latest block height if block height is -1
-	if req.FromBlock == -1 || req.ToBlock == -1 {
-		blockHeader, err := h.usecase.GetLatestBlock(ctx.UserContext())
-		if err != nil {
-			if errors.Is(err, errs.NotFound) {
-				return errs.NewPublicError("latest block not found")
-			}
-			return errors.Wrap(err, "error during GetLatestBlock")
-		}
-		if req.FromBlock == -1 {
-			req.FromBlock = blockHeader.Height
-		}
-		if req.ToBlock == -1 {
-			req.ToBlock = blockHeader.Height
+	if cursor != nil {
+		// pages after the first page are pinned to the snapshot of the first page
+		if err := req.pin(cursor.Height, "cursor"); err != nil {
+			return errors.WithStack(err)
 		}
 	}
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// default to snapshot block
+	if req.ToBlock == 0 {
+		req.ToBlock = -1
+	}
+	if req.FromBlock == -1 {
+		req.FromBlock = int64(snapshot.Height)
+	}
+	if req.ToBlock == -1 {
+		req.ToBlock = int64(snapshot.Height)
+	}
+	if uint64(req.ToBlock) > snapshot.Height {
+		return errs.NewPublicError(fmt.Sprintf("toBlock must be less than or equal to the snapshot block height, got toBlock=%d, snapshot=%d", req.ToBlock, snapshot.Height))
+	}
 
 	// validate block height range
 	if req.FromBlock > req.ToBlock {
 		return errs.NewPublicError(fmt.Sprintf("fromBlock must be less than or equal to toBlock, got fromBlock=%d, toBlock=%d", req.FromBlock, req.ToBlock))
 	}
 
-	txs, err := h.usecase.GetRuneTransactions(ctx.UserContext(), pkScript, runeId, uint64(req.FromBlock), uint64(req.ToBlock), req.Limit, req.Offset)
+	var cursorKey *entity.RuneTransactionCursor
+	if cursor != nil {
+		cursorKey = &cursor.Key
+	}
+	txs, err := h.usecase.GetRuneTransactions(ctx.UserContext(), pkScript, runeId, uint64(req.FromBlock), uint64(req.ToBlock), cursorKey, req.Limit, req.Offset)
 	if err != nil {
 		if errors.Is(err, errs.NotFound) {
 			return errs.NewPublicError("transactions not found")
@@ -206,108 +225,17 @@ func (h *HttpHandler) GetTransactions(ctx *fiber.Ctx) (err error) {
 			allRuneIds[output.RuneId] = struct{}{}
 		}
 	}
-	runeEntries, err := h.usecase.GetRuneEntryByRuneIdBatch(ctx.UserContext(), lo.Keys(allRuneIds))
+	runeEntries, err := h.usecase.GetRuneEntryByRuneIdAndHeightBatch(ctx.UserContext(), lo.Keys(allRuneIds), snapshot.Height)
 	if err != nil {
 		if errors.Is(err, errs.NotFound) {
 			return errs.NewPublicError("rune entries not found")
 		}
-		return errors.Wrap(err, "error during GetRuneEntryByRuneIdBatch")
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeightBatch")
 	}
 
 	txList := make([]transaction, 0, len(txs))
 	for _, tx := range txs {
-		respTx := transaction{
-			TxHash:      tx.Hash,
-			BlockHeight: tx.BlockHeight,
-			Index:       tx.Index,
-			Timestamp:   tx.Timestamp.Unix(),
-			Inputs:      make([]txInputOutput, 0, len(tx.Inputs)),
-			Outputs:     make([]txInputOutput, 0, len(tx.Outputs)),
-			Mints:       make(map[string]amountWithDecimal, len(tx.Mints)),
-			Burns:       make(map[string]amountWithDecimal, len(tx.Burns)),
-			Extend: runeTransactionExtend{
-				RuneEtched: tx.RuneEtched,
-				Runestone:  nil,
-			},
-		}
-		for _, input := range tx.Inputs {
-			address := addressFromPkScript(input.PkScript, h.network)
-			respTx.Inputs = append(respTx.Inputs, txInputOutput{
-				PkScript: hex.EncodeToString(input.PkScript),
-				Address:  address,
-				Id:       input.RuneId,
-				Amount:   input.Amount,
-				Decimals: runeEntries[input.RuneId].Divisibility,
-				Index:    input.Index,
-			})
-		}
-		for _, output := range tx.Outputs {
-			address := addressFromPkScript(output.PkScript, h.network)
-			respTx.Outputs = append(respTx.Outputs, txInputOutput{
-				PkScript: hex.EncodeToString(output.PkScript),
-				Address:  address,
-				Id:       output.RuneId,
-				Amount:   output.Amount,
-				Decimals: runeEntries[output.RuneId].Divisibility,
-				Index:    output.Index,
-			})
-		}
-		for id, amount := range tx.Mints {
-			respTx.Mints[id.String()] = amountWithDecimal{
-				Amount:   amount,
-				Decimals: runeEntries[id].Divisibility,
-			}
-		}
-		for id, amount := range tx.Burns {
-			respTx.Burns[id.String()] = amountWithDecimal{
-				Amount:   amount,
-				Decimals: runeEntries[id].Divisibility,
-			}
-		}
-		if tx.Runestone != nil {
-			var e *etching
-			if tx.Runestone.Etching != nil {
-				var symbol *string
-				if tx.Runestone.Etching.Symbol != nil {
-					symbol = lo.ToPtr(string(*tx.Runestone.Etching.Symbol))
-				}
-				var t *terms
-				if tx.Runestone.Etching.Terms != nil {
-					t = &terms{
-						Amount:      tx.Runestone.Etching.Terms.Amount,
-						Cap:         tx.Runestone.Etching.Terms.Cap,
-						HeightStart: tx.Runestone.Etching.Terms.HeightStart,
-						HeightEnd:   tx.Runestone.Etching.Terms.HeightEnd,
-						OffsetStart: tx.Runestone.Etching.Terms.OffsetStart,
-						OffsetEnd:   tx.Runestone.Etching.Terms.OffsetEnd,
-					}
-				}
-				e = &etching{
-					Divisibility: tx.Runestone.Etching.Divisibility,
-					Premine:      tx.Runestone.Etching.Premine,
-					Rune:         tx.Runestone.Etching.Rune,
-					Spacers:      tx.Runestone.Etching.Spacers,
-					Symbol:       symbol,
-					Terms:        t,
-					Turbo:        tx.Runestone.Etching.Turbo,
-				}
-			}
-			respTx.Extend.Runestone = &runestone{
-				Cenotaph: tx.Runestone.Cenotaph,
-				Flaws:    lo.Ternary(tx.Runestone.Cenotaph, tx.Runestone.Flaws.CollectAsString(), nil),
-				Etching:  e,
-				Edicts: lo.Map(tx.Runestone.Edicts, func(ed runes.Edict, _ int) edict {
-					return edict{
-						Id:     ed.Id,
-						Amount: ed.Amount,
-						Output: ed.Output,
-					}
-				}),
-				Mint:    tx.Runestone.Mint,
-				Pointer: tx.Runestone.Pointer,
-			}
-		}
-		txList = append(txList, respTx)
+		txList = append(txList, h.mapRuneTransaction(tx, runeEntries))
 	}
 	// sort by block height DESC, then index DESC
 	slices.SortFunc(txList, func(t1, t2 transaction) int {
@@ -317,11 +245,123 @@ func (h *HttpHandler) GetTransactions(ctx *fiber.Ctx) (err error) {
 		return cmp.Compare(t2.Index, t1.Index)
 	})
 
+	nextCursor, err := encodeNextCursor(snapshot.Height, txs, req.Limit, func(tx *entity.RuneTransaction) entity.RuneTransactionCursor {
+		return entity.RuneTransactionCursor{
+			BlockHeight: tx.BlockHeight,
+			Index:       tx.Index,
+		}
+	})
+	if err != nil {
+		return errors.Wrap(err, "error during encodeNextCursor")
+	}
+
 	resp := getTransactionsResponse{
 		Result: &getTransactionsResult{
-			List: txList,
+			List:       txList,
+			NextCursor: nextCursor,
 		},
 	}
 
-	return errors.WithStack(ctx.JSON(resp))
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
+}
+
+// mapRuneTransaction maps the rune transaction to the response. runeEntries must contain all rune ids in the transaction.
+func (h *HttpHandler) mapRuneTransaction(tx *entity.RuneTransaction, runeEntries map[runes.RuneId]*runes.RuneEntry) transaction {
+	respTx := transaction{
+		TxHash:      tx.Hash,
+		BlockHeight: tx.BlockHeight,
+		Index:       tx.Index,
+		Timestamp:   tx.Timestamp.Unix(),
+		Inputs:      make([]txInputOutput, 0, len(tx.Inputs)),
+		Outputs:     make([]txInputOutput, 0, len(tx.Outputs)),
+		Mints:       make(map[string]amountWithDecimal, len(tx.Mints)),
+		Burns:       make(map[string]amountWithDecimal, len(tx.Burns)),
+		Extend: runeTransactionExtend{
+			RuneEtched: tx.RuneEtched,
+			Runestone:  nil,
+		},
+	}
+	for _, input := range tx.Inputs {
+		address := btcutils.EncodeAddressFromPkScript(input.PkScript, h.network.ChainParams())
+		respTx.Inputs = append(respTx.Inputs, txInputOutput{
+			PkScript: hex.EncodeToString(input.PkScript),
+			Address:  address,
+			Id:       input.RuneId,
+			Amount:   input.Amount,
+			Decimals: runeEntries[input.RuneId].Divisibility,
+			Index:    input.Index,
+		})
+	}
+	for _, output := range tx.Outputs {
+		address := btcutils.EncodeAddressFromPkScript(output.PkScript, h.network.ChainParams())
+		respTx.Outputs = append(respTx.Outputs, txInputOutput{
+			PkScript: hex.EncodeToString(output.PkScript),
+			Address:  address,
+			Id:       output.RuneId,
+			Amount:   output.Amount,
+			Decimals: runeEntries[output.RuneId].Divisibility,
+			Index:    output.Index,
+		})
+	}
+	for id, amount := range tx.Mints {
+		respTx.Mints[id.String()] = amountWithDecimal{
+			Amount:   amount,
+			Decimals: runeEntries[id].Divisibility,
+		}
+	}
+	for id, amount := range tx.Burns {
+		respTx.Burns[id.String()] = amountWithDecimal{
+			Amount:   amount,
+			Decimals: runeEntries[id].Divisibility,
+		}
+	}
+	respTx.Extend.Runestone = mapRunestone(tx.Runestone)
+	return respTx
+}
+
+func mapRunestone(src *runes.Runestone) *runestone {
+	if src == nil {
+		return nil
+	}
+	var e *etching
+	if src.Etching != nil {
+		var symbol *string
+		if src.Etching.Symbol != nil {
+			symbol = lo.ToPtr(string(*src.Etching.Symbol))
+		}
+		var t *terms
+		if src.Etching.Terms != nil {
+			t = &terms{
+				Amount:      src.Etching.Terms.Amount,
+				Cap:         src.Etching.Terms.Cap,
+				HeightStart: src.Etching.Terms.HeightStart,
+				HeightEnd:   src.Etching.Terms.HeightEnd,
+				OffsetStart: src.Etching.Terms.OffsetStart,
+				OffsetEnd:   src.Etching.Terms.OffsetEnd,
+			}
+		}
+		e = &etching{
+			Divisibility: src.Etching.Divisibility,
+			Premine:      src.Etching.Premine,
+			Rune:         src.Etching.Rune,
+			Spacers:      src.Etching.Spacers,
+			Symbol:       symbol,
+			Terms:        t,
+			Turbo:        src.Etching.Turbo,
+		}
+	}
+	return &runestone{
+		Cenotaph: src.Cenotaph,
+		Flaws:    lo.Ternary(src.Cenotaph, src.Flaws.CollectAsString(), nil),
+		Etching:  e,
+		Edicts: lo.Map(src.Edicts, func(ed runes.Edict, _ int) edict {
+			return edict{
+				Id:     ed.Id,
+				Amount: ed.Amount,
+				Output: ed.Output,
+			}
+		}),
+		Mint:    src.Mint,
+		Pointer: src.Pointer,
+	}
 }
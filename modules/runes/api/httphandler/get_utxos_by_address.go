@@ -4,6 +4,7 @@ import (
 	"net/url"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/cockroachdb/errors"
 	"github.com/gaze-network/indexer-network/common/errs"
 	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
@@ -15,6 +16,7 @@ import (
 
 type getUTXOsRequest struct {
 	paginationRequest
+	snapshotRequest
 	Wallet      string `params:"wallet"`
 	Id          string `query:"id"`
 	BlockHeight uint64 `query:"blockHeight"`
@@ -45,6 +47,9 @@ func (r *getUTXOsRequest) Validate() error {
 	if r.Limit > getUTXOsMaxLimit {
 		errList = append(errList, errors.Errorf("'limit' cannot exceed %d", getUTXOsMaxLimit))
 	}
+	if err := r.validateCursor(true); err != nil {
+		errList = append(errList, err)
+	}
 	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
 }
 
@@ -70,6 +75,7 @@ type utxoItem struct {
 type getUTXOsResult struct {
 	List        []utxoItem `json:"list"`
 	BlockHeight uint64     `json:"blockHeight"`
+	NextCursor  string     `json:"nextCursor,omitempty"`
 }
 
 type getUTXOsResponse = HttpResponse[getUTXOsResult]
@@ -94,21 +100,34 @@ func (h *HttpHandler) GetUTXOs(ctx *fiber.Ctx) (err error) {
 		return errs.NewPublicError("unable to resolve pkscript from \"wallet\"")
 	}
 
-	blockHeight := req.BlockHeight
-	if blockHeight == 0 {
-		blockHeader, err := h.usecase.GetLatestBlock(ctx.UserContext())
-		if err != nil {
-			if errors.Is(err, errs.NotFound) {
-				return errs.NewPublicError("latest block not found")
-			}
-			return errors.Wrap(err, "error during GetLatestBlock")
+	cursor, err := decodeCursor[wire.OutPoint](req.Cursor)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := req.pin(req.BlockHeight, "blockHeight"); err != nil {
+		return errors.WithStack(err)
+	}
+	if cursor != nil {
+		// pages after the first page are pinned to the snapshot of the first page
+		if err := req.pin(cursor.Height, "cursor"); err != nil {
+			return errors.WithStack(err)
 		}
-		blockHeight = uint64(blockHeader.Height)
+	}
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	blockHeight := snapshot.Height
+
+	var cursorKey *wire.OutPoint
+	if cursor != nil {
+		cursorKey = &cursor.Key
 	}
 
 	var utxos []*entity.RunesUTXOWithSats
 	if runeId, ok := h.resolveRuneId(ctx.UserContext(), req.Id); ok {
-		utxos, err = h.usecase.GetRunesUTXOsByRuneIdAndPkScript(ctx.UserContext(), runeId, pkScript, blockHeight, req.Limit, req.Offset)
+		utxos, err = h.usecase.GetRunesUTXOsByRuneIdAndPkScript(ctx.UserContext(), runeId, pkScript, blockHeight, cursorKey, req.Limit, req.Offset)
 		if err != nil {
 			if errors.Is(err, errs.NotFound) {
 				return errs.NewPublicError("utxos not found")
@@ -116,7 +135,7 @@ func (h *HttpHandler) GetUTXOs(ctx *fiber.Ctx) (err error) {
 			return errors.Wrap(err, "error during GetBalancesByPkScript")
 		}
 	} else {
-		utxos, err = h.usecase.GetRunesUTXOsByPkScript(ctx.UserContext(), pkScript, blockHeight, req.Limit, req.Offset)
+		utxos, err = h.usecase.GetRunesUTXOsByPkScript(ctx.UserContext(), pkScript, blockHeight, cursorKey, req.Limit, req.Offset)
 		if err != nil {
 			if errors.Is(err, errs.NotFound) {
 				return errs.NewPublicError("utxos not found")
@@ -132,12 +151,12 @@ func (h *HttpHandler) GetUTXOs(ctx *fiber.Ctx) (err error) {
 		}
 	}
 	runeIdsList := lo.Keys(runeIds)
-	runeEntries, err := h.usecase.GetRuneEntryByRuneIdBatch(ctx.UserContext(), runeIdsList)
+	runeEntries, err := h.usecase.GetRuneEntryByRuneIdAndHeightBatch(ctx.UserContext(), runeIdsList, blockHeight)
 	if err != nil {
 		if errors.Is(err, errs.NotFound) {
 			return errs.NewPublicError("rune entries not found")
 		}
-		return errors.Wrap(err, "error during GetRuneEntryByRuneIdBatch")
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeightBatch")
 	}
 
 	utxoRespList := make([]utxoItem, 0, len(utxos))
@@ -164,12 +183,20 @@ func (h *HttpHandler) GetUTXOs(ctx *fiber.Ctx) (err error) {
 		})
 	}
 
+	nextCursor, err := encodeNextCursor(blockHeight, utxos, req.Limit, func(utxo *entity.RunesUTXOWithSats) wire.OutPoint {
+		return utxo.OutPoint
+	})
+	if err != nil {
+		return errors.Wrap(err, "error during encodeNextCursor")
+	}
+
 	resp := getUTXOsResponse{
 		Result: &getUTXOsResult{
 			BlockHeight: blockHeight,
 			List:        utxoRespList,
+			NextCursor:  nextCursor,
 		},
 	}
 
-	return errors.WithStack(ctx.JSON(resp))
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
 }
@@ -11,6 +11,7 @@ import (
 )
 
 type getUTXOsOutputByLocationRequest struct {
+	snapshotRequest
 	TxHash      string `params:"txHash"`
 	OutputIndex int32  `query:"outputIndex"`
 }
@@ -45,7 +46,12 @@ func (h *HttpHandler) GetUTXOsOutputByLocation(ctx *fiber.Ctx) (err error) {
 		return errs.WithPublicMessage(err, "unable to resolve txHash")
 	}
 
-	utxo, err := h.usecase.GetUTXOsOutputByLocation(ctx.UserContext(), *txHash, uint32(req.OutputIndex))
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	utxo, err := h.usecase.GetUTXOsOutputByLocation(ctx.UserContext(), *txHash, uint32(req.OutputIndex), snapshot.Height)
 	if err != nil {
 		if errors.Is(err, usecase.ErrUTXONotFound) {
 			return errs.NewPublicError("utxo not found")
@@ -58,12 +64,12 @@ func (h *HttpHandler) GetUTXOsOutputByLocation(ctx *fiber.Ctx) (err error) {
 		runeIds[balance.RuneId] = struct{}{}
 	}
 	runeIdsList := lo.Keys(runeIds)
-	runeEntries, err := h.usecase.GetRuneEntryByRuneIdBatch(ctx.UserContext(), runeIdsList)
+	runeEntries, err := h.usecase.GetRuneEntryByRuneIdAndHeightBatch(ctx.UserContext(), runeIdsList, snapshot.Height)
 	if err != nil {
 		if errors.Is(err, errs.NotFound) {
 			return errs.NewPublicError("rune entries not found")
 		}
-		return errors.Wrap(err, "error during GetRuneEntryByRuneIdBatch")
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeightBatch")
 	}
 
 	runeBalances := make([]runeBalance, 0, len(utxo.RuneBalances))
@@ -88,5 +94,5 @@ func (h *HttpHandler) GetUTXOsOutputByLocation(ctx *fiber.Ctx) (err error) {
 			},
 		},
 	}
-	return errors.WithStack(ctx.JSON(resp))
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
 }
@@ -20,6 +20,7 @@ type getUTXOsOutputByLocationQuery struct {
 }
 
 type getUTXOsOutputByLocationBatchRequest struct {
+	snapshotRequest
 	Queries []getUTXOsOutputByLocationQuery `json:"queries"`
 }
 
@@ -55,17 +56,25 @@ func (h *HttpHandler) GetUTXOsOutputByLocationBatch(ctx *fiber.Ctx) (err error)
 	if err := ctx.BodyParser(&req); err != nil {
 		return errors.WithStack(err)
 	}
+	if err := ctx.QueryParser(&req.snapshotRequest); err != nil {
+		return errors.WithStack(err)
+	}
 	if err := req.Validate(); err != nil {
 		return errors.WithStack(err)
 	}
 
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	processQuery := func(ctx context.Context, query getUTXOsOutputByLocationQuery, queryIndex int) (*utxoItem, error) {
 		txHash, err := chainhash.NewHashFromStr(query.TxHash)
 		if err != nil {
 			return nil, errs.WithPublicMessage(err, fmt.Sprintf("unable to parse txHash from \"queries[%d].txHash\"", queryIndex))
 		}
 
-		utxo, err := h.usecase.GetUTXOsOutputByLocation(ctx, *txHash, uint32(query.OutputIndex))
+		utxo, err := h.usecase.GetUTXOsOutputByLocation(ctx, *txHash, uint32(query.OutputIndex), snapshot.Height)
 		if err != nil {
 			if errors.Is(err, usecase.ErrUTXONotFound) {
 				return nil, errs.NewPublicError(fmt.Sprintf("utxo not found for queries[%d]", queryIndex))
@@ -78,12 +87,12 @@ func (h *HttpHandler) GetUTXOsOutputByLocationBatch(ctx *fiber.Ctx) (err error)
 			runeIds[balance.RuneId] = struct{}{}
 		}
 		runeIdsList := lo.Keys(runeIds)
-		runeEntries, err := h.usecase.GetRuneEntryByRuneIdBatch(ctx, runeIdsList)
+		runeEntries, err := h.usecase.GetRuneEntryByRuneIdAndHeightBatch(ctx, runeIdsList, snapshot.Height)
 		if err != nil {
 			if errors.Is(err, errs.NotFound) {
 				return nil, errs.NewPublicError(fmt.Sprintf("rune entries not found for queries[%d]", queryIndex))
 			}
-			return nil, errors.Wrap(err, "error during GetRuneEntryByRuneIdBatch")
+			return nil, errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeightBatch")
 		}
 
 		runeBalances := make([]runeBalance, 0, len(utxo.RuneBalances))
@@ -132,5 +141,5 @@ func (h *HttpHandler) GetUTXOsOutputByLocationBatch(ctx *fiber.Ctx) (err error)
 		},
 	}
 
-	return errors.WithStack(ctx.JSON(resp))
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, resp))
 }
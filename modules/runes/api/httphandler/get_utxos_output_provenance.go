@@ -0,0 +1,217 @@
+package httphandler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/indexer-network/modules/runes/usecase"
+	"github.com/gaze-network/indexer-network/pkg/btcutils"
+	"github.com/gaze-network/uint128"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+)
+
+type getUTXOsOutputProvenanceRequest struct {
+	snapshotRequest
+	TxHash      string `params:"txHash"`
+	OutputIndex int32  `query:"outputIndex"`
+	Id          string `query:"id"`
+	MaxDepth    int    `query:"maxDepth"`
+	MinAmount   string `query:"minAmount"`
+}
+
+const (
+	getUTXOsOutputProvenanceDefaultMaxDepth = 10
+	getUTXOsOutputProvenanceMaxDepth        = 100
+)
+
+func (r *getUTXOsOutputProvenanceRequest) Validate() error {
+	var errList []error
+	if r.TxHash == "" {
+		errList = append(errList, errors.New("'txHash' is required"))
+	}
+	if r.OutputIndex < 0 {
+		errList = append(errList, errors.New("'outputIndex' must be non-negative"))
+	}
+	if r.Id != "" {
+		id, err := url.QueryUnescape(r.Id)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		r.Id = id
+		if !isRuneIdOrRuneName(r.Id) {
+			errList = append(errList, errors.Errorf("id '%s' is not valid rune id or rune name", r.Id))
+		}
+	}
+	if r.MaxDepth < 0 {
+		errList = append(errList, errors.New("'maxDepth' must be non-negative"))
+	}
+	if r.MaxDepth > getUTXOsOutputProvenanceMaxDepth {
+		errList = append(errList, errors.Errorf("'maxDepth' cannot exceed %d", getUTXOsOutputProvenanceMaxDepth))
+	}
+	if r.MinAmount != "" {
+		if _, err := uint128.FromString(r.MinAmount); err != nil {
+			errList = append(errList, errors.New("'minAmount' must be a non-negative integer"))
+		}
+	}
+	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
+}
+
+func (r *getUTXOsOutputProvenanceRequest) ParseDefault() error {
+	if r.MaxDepth == 0 {
+		r.MaxDepth = getUTXOsOutputProvenanceDefaultMaxDepth
+	}
+	return nil
+}
+
+type provenanceInput struct {
+	PkScript string          `json:"pkScript"`
+	Address  string          `json:"address"`
+	Id       runes.RuneId    `json:"id"`
+	Amount   uint128.Uint128 `json:"amount"`
+	Decimals uint8           `json:"decimals"`
+	Index    uint32          `json:"index"`
+	// TxHash and OutputIndex are the outpoint spent by this input.
+	TxHash      chainhash.Hash `json:"txHash"`
+	OutputIndex uint32         `json:"outputIndex"`
+}
+
+type provenanceOrigin struct {
+	Type     entity.ProvenanceOriginType `json:"type"`
+	Id       runes.RuneId                `json:"id"`
+	Amount   uint128.Uint128             `json:"amount"`
+	Decimals uint8                       `json:"decimals"`
+}
+
+type provenanceNode struct {
+	TxHash      chainhash.Hash     `json:"txHash"`
+	BlockHeight uint64             `json:"blockHeight"`
+	Index       uint32             `json:"index"`
+	Depth       int                `json:"depth"`
+	Inputs      []provenanceInput  `json:"inputs"`
+	Origins     []provenanceOrigin `json:"origins"`
+}
+
+type getUTXOsOutputProvenanceResult struct {
+	TxHash      chainhash.Hash   `json:"txHash"`
+	OutputIndex uint32           `json:"outputIndex"`
+	Truncated   bool             `json:"truncated"`
+	Nodes       []provenanceNode `json:"nodes"`
+}
+
+type getUTXOsOutputProvenanceResponse = HttpResponse[getUTXOsOutputProvenanceResult]
+
+func (h *HttpHandler) GetUTXOsOutputProvenance(ctx *fiber.Ctx) (err error) {
+	var req getUTXOsOutputProvenanceRequest
+	if err := ctx.ParamsParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.ParseDefault(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	txHash, err := chainhash.NewHashFromStr(req.TxHash)
+	if err != nil {
+		return errs.WithPublicMessage(err, "unable to resolve txHash")
+	}
+
+	var runeId runes.RuneId
+	if req.Id != "" {
+		var ok bool
+		runeId, ok = h.resolveRuneId(ctx.UserContext(), req.Id)
+		if !ok {
+			return errs.NewPublicError(fmt.Sprintf("unable to resolve rune id \"%s\" from \"id\"", req.Id))
+		}
+	}
+
+	minAmount := uint128.Zero
+	if req.MinAmount != "" {
+		// already validated
+		minAmount = lo.Must(uint128.FromString(req.MinAmount))
+	}
+
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	provenance, err := h.usecase.GetRuneProvenance(ctx.UserContext(), wire.OutPoint{
+		Hash:  *txHash,
+		Index: uint32(req.OutputIndex),
+	}, snapshot.Height, runeId, req.MaxDepth, minAmount)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUTXONotFound) {
+			return errs.NewPublicError("utxo not found")
+		}
+		return errors.Wrap(err, "error during GetRuneProvenance")
+	}
+
+	allRuneIds := make(map[runes.RuneId]struct{})
+	for _, node := range provenance.Nodes {
+		for _, input := range node.Inputs {
+			allRuneIds[input.RuneId] = struct{}{}
+		}
+		for _, origin := range node.Origins {
+			allRuneIds[origin.RuneId] = struct{}{}
+		}
+	}
+	runeEntries, err := h.usecase.GetRuneEntryByRuneIdAndHeightBatch(ctx.UserContext(), lo.Keys(allRuneIds), snapshot.Height)
+	if err != nil {
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeightBatch")
+	}
+
+	nodes := make([]provenanceNode, 0, len(provenance.Nodes))
+	for _, node := range provenance.Nodes {
+		respNode := provenanceNode{
+			TxHash:      node.TxHash,
+			BlockHeight: node.BlockHeight,
+			Index:       node.Index,
+			Depth:       node.Depth,
+			Inputs:      make([]provenanceInput, 0, len(node.Inputs)),
+			Origins:     make([]provenanceOrigin, 0, len(node.Origins)),
+		}
+		for _, input := range node.Inputs {
+			respNode.Inputs = append(respNode.Inputs, provenanceInput{
+				PkScript:    hex.EncodeToString(input.PkScript),
+				Address:     btcutils.EncodeAddressFromPkScript(input.PkScript, h.network.ChainParams()),
+				Id:          input.RuneId,
+				Amount:      input.Amount,
+				Decimals:    runeEntries[input.RuneId].Divisibility,
+				Index:       input.Index,
+				TxHash:      input.TxHash,
+				OutputIndex: input.TxOutIndex,
+			})
+		}
+		for _, origin := range node.Origins {
+			respNode.Origins = append(respNode.Origins, provenanceOrigin{
+				Type:     origin.Type,
+				Id:       origin.RuneId,
+				Amount:   origin.Amount,
+				Decimals: runeEntries[origin.RuneId].Divisibility,
+			})
+		}
+		nodes = append(nodes, respNode)
+	}
+
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, getUTXOsOutputProvenanceResponse{
+		Result: &getUTXOsOutputProvenanceResult{
+			TxHash:      provenance.OutPoint.Hash,
+			OutputIndex: provenance.OutPoint.Index,
+			Truncated:   provenance.Truncated,
+			Nodes:       nodes,
+		},
+	}))
+}
@@ -0,0 +1,130 @@
+package httphandler
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/indexer-network/modules/runes/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+)
+
+type getUTXOsOutputSpendingRequest struct {
+	snapshotRequest
+	TxHash      string `params:"txHash"`
+	OutputIndex int32  `query:"outputIndex"`
+}
+
+func (r getUTXOsOutputSpendingRequest) Validate() error {
+	var errList []error
+	if r.TxHash == "" {
+		errList = append(errList, errors.New("'txHash' is required"))
+	}
+	if r.OutputIndex < 0 {
+		errList = append(errList, errors.New("'outputIndex' must be non-negative"))
+	}
+	return errs.WithPublicMessage(errors.Join(errList...), "validation error")
+}
+
+type utxoSpending struct {
+	TxHash      chainhash.Hash `json:"txHash"`
+	OutputIndex uint32         `json:"outputIndex"`
+	Runes       []runeBalance  `json:"runes"`
+	Spent       bool           `json:"spent"`
+	SpentHeight *uint64        `json:"spentHeight"`
+	// SpendingTxHash and SpendingInputIndex identify the transaction input that spent the output.
+	SpendingTxHash     *chainhash.Hash `json:"spendingTxHash"`
+	SpendingInputIndex *uint32         `json:"spendingInputIndex"`
+	// SpendingTx is the rune transaction that spent the output. Its outputs and burns show where the runes went.
+	SpendingTx *transaction `json:"spendingTx"`
+}
+
+type getUTXOsOutputSpendingResponse = HttpResponse[utxoSpending]
+
+func (h *HttpHandler) GetUTXOsOutputSpending(ctx *fiber.Ctx) (err error) {
+	var req getUTXOsOutputSpendingRequest
+	if err := ctx.ParamsParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ctx.QueryParser(&req); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := req.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	txHash, err := chainhash.NewHashFromStr(req.TxHash)
+	if err != nil {
+		return errs.WithPublicMessage(err, "unable to resolve txHash")
+	}
+
+	snapshot, err := h.resolveSnapshot(ctx.UserContext(), req.snapshotRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	balances, spendingTx, err := h.usecase.GetOutPointSpendingTransaction(ctx.UserContext(), wire.OutPoint{
+		Hash:  *txHash,
+		Index: uint32(req.OutputIndex),
+	}, snapshot.Height)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUTXONotFound) {
+			return errs.NewPublicError("utxo not found")
+		}
+		return errors.Wrap(err, "error during GetOutPointSpendingTransaction")
+	}
+
+	allRuneIds := make(map[runes.RuneId]struct{})
+	for _, balance := range balances {
+		allRuneIds[balance.RuneId] = struct{}{}
+	}
+	if spendingTx != nil {
+		for id := range spendingTx.Mints {
+			allRuneIds[id] = struct{}{}
+		}
+		for id := range spendingTx.Burns {
+			allRuneIds[id] = struct{}{}
+		}
+		for _, input := range spendingTx.Inputs {
+			allRuneIds[input.RuneId] = struct{}{}
+		}
+		for _, output := range spendingTx.Outputs {
+			allRuneIds[output.RuneId] = struct{}{}
+		}
+	}
+	runeEntries, err := h.usecase.GetRuneEntryByRuneIdAndHeightBatch(ctx.UserContext(), lo.Keys(allRuneIds), snapshot.Height)
+	if err != nil {
+		return errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeightBatch")
+	}
+
+	result := utxoSpending{
+		TxHash:      *txHash,
+		OutputIndex: uint32(req.OutputIndex),
+		Runes:       make([]runeBalance, 0, len(balances)),
+	}
+	for _, balance := range balances {
+		runeEntry := runeEntries[balance.RuneId]
+		result.Runes = append(result.Runes, runeBalance{
+			RuneId:       balance.RuneId,
+			Rune:         runeEntry.SpacedRune,
+			Symbol:       string(runeEntry.Symbol),
+			Amount:       balance.Amount,
+			Divisibility: runeEntry.Divisibility,
+		})
+	}
+	if balance := balances[0]; balance.SpentHeight != nil {
+		result.Spent = true
+		result.SpentHeight = balance.SpentHeight
+		result.SpendingTxHash = balance.SpentTxHash
+		result.SpendingInputIndex = balance.SpentTxInputIndex
+	}
+	if spendingTx != nil {
+		result.SpendingTx = lo.ToPtr(h.mapRuneTransaction(spendingTx, runeEntries))
+	}
+
+	return errors.WithStack(sendSnapshotJSON(ctx, snapshot, getUTXOsOutputSpendingResponse{
+		Result: &result,
+	}))
+}
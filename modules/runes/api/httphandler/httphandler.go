@@ -12,8 +12,6 @@ import (
 	"github.com/gaze-network/indexer-network/common/errs"
 	"github.com/gaze-network/indexer-network/modules/runes/runes"
 	"github.com/gaze-network/indexer-network/modules/runes/usecase"
-	"github.com/gaze-network/indexer-network/pkg/logger"
-	"github.com/gaze-network/indexer-network/pkg/logger/slogx"
 )
 
 type HttpHandler struct {
@@ -31,11 +29,15 @@ func New(network common.Network, usecase *usecase.Usecase) *HttpHandler {
 type HttpResponse[T any] struct {
 	Error  *string `json:"error"`
 	Result *T      `json:"result,omitempty"`
+	// BlockHeight and BlockHash are the block that the result was served at.
+	BlockHeight *uint64 `json:"blockHeight,omitempty"`
+	BlockHash   *string `json:"blockHash,omitempty"`
 }
 
 type paginationRequest struct {
-	Offset int32 `query:"offset"`
-	Limit  int32 `query:"limit"`
+	Offset int32  `query:"offset"`
+	Limit  int32  `query:"limit"`
+	Cursor string `query:"cursor"` // Opaque cursor from the previous page. Pages are pinned to the block height of the first page. Only supported by some endpoints, see validateCursor.
 
 	// OrderBy string `query:"orderBy"` // ASC or DESC
 	// SortBy  string `query:"sortBy"`  // column name
@@ -117,21 +119,6 @@ func resolvePkScript(network common.Network, wallet string) ([]byte, bool) {
 	return pkScript, true
 }
 
-// TODO: extract this function somewhere else
-// addressFromPkScript returns the address from the given pkScript. If the pkScript is invalid or not standard, it returns empty string.
-func addressFromPkScript(pkScript []byte, network common.Network) string {
-	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, network.ChainParams())
-	if err != nil {
-		logger.Debug("unable to extract address from pkscript", slogx.Error(err))
-		return ""
-	}
-	if len(addrs) != 1 {
-		logger.Debug("invalid number of addresses extracted from pkscript. Expected only 1.", slogx.Int("numAddresses", len(addrs)))
-		return ""
-	}
-	return addrs[0].EncodeAddress()
-}
-
 func (h *HttpHandler) resolveRuneId(ctx context.Context, id string) (runes.RuneId, bool) {
 	if id == "" {
 		return runes.RuneId{}, false
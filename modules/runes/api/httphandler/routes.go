@@ -9,15 +9,26 @@ func (h *HttpHandler) Mount(router fiber.Router) error {
 
 	r.Post("/balances/wallet/batch", h.GetBalancesBatch)
 	r.Get("/balances/wallet/:wallet", h.GetBalances)
+	r.Get("/balances/wallet/:wallet/history", h.GetBalanceHistory)
 	r.Get("/transactions", h.GetTransactions)
 	r.Get("/transactions/hash/:hash", h.GetTransactionByHash)
 	r.Get("/holders/:id", h.GetHolders)
+	r.Get("/holders/:id/export", h.GetHoldersExport)
+	r.Get("/holders/:id/diff", h.GetHoldersDiff)
 	r.Post("/info/batch", h.GetTokenInfoBatch)
 	r.Get("/info/:id", h.GetTokenInfo)
+	r.Get("/info/:id/history", h.GetTokenInfoHistory)
+	r.Get("/info/:id/mintability", h.GetTokenMintability)
 	r.Get("/utxos/wallet/:wallet", h.GetUTXOs)
 	r.Post("/utxos/output/batch", h.GetUTXOsOutputByLocationBatch)
 	r.Get("/utxos/output/:txHash", h.GetUTXOsOutputByLocation)
+	r.Get("/utxos/output/:txHash/spending", h.GetUTXOsOutputSpending)
+	r.Get("/utxos/output/:txHash/provenance", h.GetUTXOsOutputProvenance)
 	r.Get("/block", h.GetCurrentBlock)
+	r.Get("/blocks/:height", h.GetBlockActivity)
 	r.Get("/tokens", h.GetTokens)
+	r.Get("/names/unlock-schedule", h.GetRuneNameUnlockSchedule)
+	r.Get("/names/:name", h.GetRuneName)
+	r.Post("/decode", h.DecodeRunestone)
 	return nil
 }
@@ -0,0 +1,114 @@
+package httphandler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	blockHeightHeader = "X-Block-Height"
+	blockHashHeader   = "X-Block-Hash"
+)
+
+// snapshotRequest pins the block that a request is served at, so that clients can read a consistent view across calls.
+// If neither is set, the request is served at the latest indexed block. Batch endpoints also accept them in the request body.
+type snapshotRequest struct {
+	AtHeight uint64 `query:"atHeight" json:"atHeight"`
+	AtHash   string `query:"atHash" json:"atHash"`
+}
+
+// pin sets the snapshot height from another parameter that implies it, such as the legacy 'blockHeight' parameter or a pagination cursor.
+func (r *snapshotRequest) pin(height uint64, param string) error {
+	if height == 0 {
+		return nil
+	}
+	if r.AtHeight != 0 && r.AtHeight != height {
+		return errs.NewPublicError(fmt.Sprintf("'%s' does not match 'atHeight'", param))
+	}
+	r.AtHeight = height
+	return nil
+}
+
+// snapshot is the block that a response is served at.
+type snapshot struct {
+	Height uint64
+	Hash   chainhash.Hash
+	Latest bool // true if the snapshot is the latest indexed block, as it was not pinned by the request
+}
+
+// resolveSnapshot returns the block that the request should be served at.
+func (h *HttpHandler) resolveSnapshot(ctx context.Context, req snapshotRequest) (*snapshot, error) {
+	latestBlock, err := h.usecase.GetLatestBlock(ctx)
+	if err != nil {
+		if errors.Is(err, errs.NotFound) {
+			return nil, errs.NewPublicError("latest block not found")
+		}
+		return nil, errors.Wrap(err, "error during GetLatestBlock")
+	}
+
+	if req.AtHash != "" {
+		hash, err := chainhash.NewHashFromStr(req.AtHash)
+		if err != nil {
+			return nil, errs.NewPublicError("invalid 'atHash'")
+		}
+		indexedBlock, err := h.usecase.GetIndexedBlockByHash(ctx, *hash)
+		if err != nil {
+			if errors.Is(err, errs.NotFound) {
+				// the block is not indexed yet, or was reorged out of the chain
+				return nil, errs.NewPublicError(fmt.Sprintf("block %s is not indexed", hash))
+			}
+			return nil, errors.Wrap(err, "error during GetIndexedBlockByHash")
+		}
+		if req.AtHeight != 0 && req.AtHeight != uint64(indexedBlock.Height) {
+			return nil, errs.NewPublicError(fmt.Sprintf("block %s is at height %d, not %d", hash, indexedBlock.Height, req.AtHeight))
+		}
+		return &snapshot{
+			Height: uint64(indexedBlock.Height),
+			Hash:   indexedBlock.Hash,
+		}, nil
+	}
+
+	if req.AtHeight != 0 {
+		if req.AtHeight > uint64(latestBlock.Height) {
+			return nil, errs.NewPublicError(fmt.Sprintf("block height %d is not indexed yet, latest block height is %d", req.AtHeight, latestBlock.Height))
+		}
+		indexedBlock, err := h.usecase.GetIndexedBlockByHeight(ctx, int64(req.AtHeight))
+		if err != nil {
+			if errors.Is(err, errs.NotFound) {
+				return nil, errs.NewPublicError(fmt.Sprintf("block height %d is not indexed", req.AtHeight))
+			}
+			return nil, errors.Wrap(err, "error during GetIndexedBlockByHeight")
+		}
+		return &snapshot{
+			Height: uint64(indexedBlock.Height),
+			Hash:   indexedBlock.Hash,
+		}, nil
+	}
+
+	return &snapshot{
+		Height: uint64(latestBlock.Height),
+		Hash:   latestBlock.Hash,
+		Latest: true,
+	}, nil
+}
+
+// setSnapshotHeaders sets the X-Block-Height and X-Block-Hash headers to the block that the response is served at.
+func setSnapshotHeaders(ctx *fiber.Ctx, snapshot *snapshot) {
+	ctx.Set(blockHeightHeader, strconv.FormatUint(snapshot.Height, 10))
+	ctx.Set(blockHashHeader, snapshot.Hash.String())
+}
+
+// sendSnapshotJSON sends the response along with the block it was served at, in the body and the X-Block-Height and X-Block-Hash headers.
+func sendSnapshotJSON[T any](ctx *fiber.Ctx, snapshot *snapshot, resp HttpResponse[T]) error {
+	hash := snapshot.Hash.String()
+	resp.BlockHeight = &snapshot.Height
+	resp.BlockHash = &hash
+	setSnapshotHeaders(ctx, snapshot)
+	return errors.WithStack(ctx.JSON(resp))
+}
@@ -0,0 +1,74 @@
+package runes
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/pkg/btcclient"
+	"github.com/gaze-network/indexer-network/pkg/logger"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// backfillBatchSize is the number of transactions to backfill at a time.
+	backfillBatchSize = 1000
+	// backfillConcurrency is the number of concurrent requests to the bitcoin node when backfilling.
+	backfillConcurrency = 16
+)
+
+// BackfillOutPointValues records the value of outpoint balances indexed before values were recorded, from the transactions fetched from the bitcoin node.
+// Returns the number of backfilled transactions. It can be resumed after an interruption.
+func BackfillOutPointValues(ctx context.Context, backfillDg datagateway.BackfillDataGateway, bitcoinClient btcclient.Contract) (int, error) {
+	var after chainhash.Hash
+	count := 0
+	for {
+		txs, err := backfillDg.GetOutPointTxsWithoutValue(ctx, after, backfillBatchSize)
+		if err != nil {
+			return count, errors.Wrap(err, "failed to get transactions without outpoint values")
+		}
+		if len(txs) == 0 {
+			break
+		}
+
+		var mu sync.Mutex
+		values := make([]*entity.OutPointValue, 0, len(txs))
+		eg, ectx := errgroup.WithContext(ctx)
+		eg.SetLimit(backfillConcurrency)
+		for _, tx := range txs {
+			tx := tx
+			eg.Go(func() error {
+				msgTx, err := bitcoinClient.GetRawTransactionByTxHash(ectx, tx.TxHash)
+				if err != nil {
+					return errors.Wrapf(err, "failed to get transaction %s", tx.TxHash)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for i, txOut := range msgTx.TxOut {
+					values = append(values, &entity.OutPointValue{
+						OutPoint:    wire.OutPoint{Hash: tx.TxHash, Index: uint32(i)},
+						BlockHeight: tx.BlockHeight,
+						Value:       txOut.Value,
+					})
+				}
+				return nil
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			return count, errors.WithStack(err)
+		}
+		if err := backfillDg.SetOutPointValues(ctx, values); err != nil {
+			return count, errors.Wrap(err, "failed to set outpoint values")
+		}
+
+		count += len(txs)
+		after = txs[len(txs)-1].TxHash
+		logger.InfoContext(ctx, "Backfilled outpoint values", slog.Int("count", count))
+	}
+	return count, nil
+}
@@ -0,0 +1,93 @@
+package runes
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBackfillDg stores the outpoint values of transactions. A transaction without values has nil values.
+type fakeBackfillDg struct {
+	txs    []*entity.TxAtHeight
+	values map[chainhash.Hash][]int64
+}
+
+func (d *fakeBackfillDg) GetOutPointTxsWithoutValue(ctx context.Context, after chainhash.Hash, limit int32) ([]*entity.TxAtHeight, error) {
+	result := make([]*entity.TxAtHeight, 0)
+	for _, tx := range d.txs {
+		if d.values[tx.TxHash] == nil && strings.Compare(tx.TxHash.String(), after.String()) > 0 && len(result) < int(limit) {
+			result = append(result, tx)
+		}
+	}
+	return result, nil
+}
+
+func (d *fakeBackfillDg) SetOutPointValues(ctx context.Context, values []*entity.OutPointValue) error {
+	for _, value := range values {
+		d.values[value.OutPoint.Hash] = append(d.values[value.OutPoint.Hash], value.Value)
+	}
+	return nil
+}
+
+// fakeBitcoinClient returns transactions with an output of 1000 sats per output index.
+type fakeBitcoinClient struct {
+	outputs map[chainhash.Hash]int
+}
+
+func (c *fakeBitcoinClient) GetRawTransactionAndHeightByTxHash(ctx context.Context, txHash chainhash.Hash) (*wire.MsgTx, int64, error) {
+	panic("not supported")
+}
+
+func (c *fakeBitcoinClient) GetRawTransactionByTxHash(ctx context.Context, txHash chainhash.Hash) (*wire.MsgTx, error) {
+	outputs, ok := c.outputs[txHash]
+	if !ok {
+		return nil, errors.New("No such mempool or blockchain transaction.")
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	for i := 0; i < outputs; i++ {
+		tx.AddTxOut(wire.NewTxOut(int64(i+1)*1000, nil))
+	}
+	return tx, nil
+}
+
+func TestBackfillOutPointValues(t *testing.T) {
+	dg := &fakeBackfillDg{values: make(map[chainhash.Hash][]int64)}
+	client := &fakeBitcoinClient{outputs: make(map[chainhash.Hash]int)}
+	// more transactions than a batch, sorted by hash like the database
+	for i := 0; i < backfillBatchSize+10; i++ {
+		txHash := chainhash.DoubleHashH([]byte{byte(i), byte(i >> 8)})
+		dg.txs = append(dg.txs, &entity.TxAtHeight{TxHash: txHash, BlockHeight: 840000})
+		client.outputs[txHash] = i%3 + 1
+	}
+	slices.SortFunc(dg.txs, func(tx1, tx2 *entity.TxAtHeight) int {
+		return strings.Compare(tx1.TxHash.String(), tx2.TxHash.String())
+	})
+
+	count, err := BackfillOutPointValues(context.Background(), dg, client)
+	assert.NoError(t, err)
+	assert.Equal(t, backfillBatchSize+10, count)
+	for _, tx := range dg.txs {
+		expected := lo.Times(client.outputs[tx.TxHash], func(i int) int64 { return int64(i+1) * 1000 })
+		assert.ElementsMatch(t, expected, dg.values[tx.TxHash])
+	}
+
+	t.Run("transaction not found", func(t *testing.T) {
+		txHash := chainhash.Hash{1}
+		dg := &fakeBackfillDg{
+			txs:    []*entity.TxAtHeight{{TxHash: txHash, BlockHeight: 840000}},
+			values: make(map[chainhash.Hash][]int64),
+		}
+		count, err := BackfillOutPointValues(context.Background(), dg, &fakeBitcoinClient{})
+		assert.Error(t, err)
+		assert.Equal(t, 0, count)
+		assert.Empty(t, dg.values)
+	})
+}
@@ -7,4 +7,11 @@ type Config struct {
 	Database    string          `mapstructure:"database"`     // Database to store runes data.
 	APIHandlers []string        `mapstructure:"api_handlers"` // List of API handlers to enable. (e.g. `http`)
 	Postgres    postgres.Config `mapstructure:"postgres"`
+	Pruning     PruningConfig   `mapstructure:"pruning"`
+	AutoMigrate bool            `mapstructure:"auto_migrate"` // If true, apply pending database migrations up to the version required by this build before indexing.
+}
+
+type PruningConfig struct {
+	Enabled    bool  `mapstructure:"enabled"`     // If true, only keep full history for the last `keep_blocks` blocks. Older spent outpoints and superseded balances are deleted.
+	KeepBlocks int64 `mapstructure:"keep_blocks"` // Number of most recent blocks to keep full history for. Must be large enough to handle reorgs.
 }
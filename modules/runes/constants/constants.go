@@ -15,10 +15,17 @@ import (
 
 const (
 	Version          = "v0.0.1"
-	DBVersion        = 1
+	DBVersion        = 10
 	EventHashVersion = 1
 )
 
+// PruneInterval is the number of blocks between each pruning run. Each run advances the pruned height by at least PruneInterval blocks.
+const PruneInterval = 100
+
+// IndexerLockKey is the Postgres advisory lock key held by a running runes indexer.
+// Maintenance commands that modify the indexed data acquire it too, so they can't run concurrently with the indexer.
+const IndexerLockKey int64 = 0x72756e6573 // "runes"
+
 // starting block heights and hashes should be 1 block before activation block, as indexer will start from the block after this value
 var StartingBlockHeader = map[common.Network]types.BlockHeader{
 	common.NetworkMainnet: {
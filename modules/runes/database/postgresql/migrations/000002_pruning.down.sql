@@ -0,0 +1,8 @@
+BEGIN;
+
+DELETE FROM "runes_indexer_state" WHERE "db_version" = 2;
+
+DROP INDEX IF EXISTS runes_outpoint_balances_spent_height_idx;
+DROP TABLE IF EXISTS "runes_prune_state";
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Pruning state. History before "pruned_height" has been compacted and can no longer be queried.
+CREATE TABLE IF NOT EXISTS "runes_prune_state" (
+	"id" BIGSERIAL PRIMARY KEY,
+	"pruned_height" INT NOT NULL,
+	"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS runes_prune_state_created_at_idx ON "runes_prune_state" USING BTREE ("created_at" DESC);
+
+CREATE INDEX IF NOT EXISTS runes_outpoint_balances_spent_height_idx ON "runes_outpoint_balances" USING BTREE ("spent_height");
+
+-- bump db version of existing indexer state
+INSERT INTO "runes_indexer_state" ("db_version", "event_hash_version")
+	SELECT 2, "event_hash_version" FROM "runes_indexer_state" ORDER BY "created_at" DESC LIMIT 1;
+
+COMMIT;
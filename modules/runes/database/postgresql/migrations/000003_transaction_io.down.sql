@@ -0,0 +1,8 @@
+BEGIN;
+
+DELETE FROM "runes_indexer_state" WHERE "db_version" = 3;
+
+CREATE INDEX IF NOT EXISTS runes_transactions_jsonb_idx ON "runes_transactions" USING GIN ("inputs", "outputs", "mints", "burns");
+DROP TABLE IF EXISTS "runes_transaction_io";
+
+COMMIT;
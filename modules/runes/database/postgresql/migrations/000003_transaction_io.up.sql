@@ -0,0 +1,42 @@
+BEGIN;
+
+-- Normalized inputs, outputs, mints and burns of rune transactions. Used to filter transactions by pkscript and rune id.
+CREATE TABLE IF NOT EXISTS "runes_transaction_io" (
+	"tx_hash" TEXT NOT NULL,
+	"block_height" INT NOT NULL,
+	"tx_index" INT NOT NULL, -- index of the transaction in the block
+	"io_type" TEXT NOT NULL, -- "input" | "output" | "mint" | "burn"
+	"io_index" INT NOT NULL, -- input or output index, 0 for mints and burns
+	"pkscript" TEXT, -- null for mints and burns
+	"rune_id" TEXT NOT NULL,
+	"amount" DECIMAL NOT NULL,
+	PRIMARY KEY ("tx_hash", "io_type", "io_index", "rune_id")
+);
+
+-- backfill from existing transactions
+INSERT INTO "runes_transaction_io" ("tx_hash", "block_height", "tx_index", "io_type", "io_index", "pkscript", "rune_id", "amount")
+	SELECT t."hash", t."block_height", t."index", 'input', (io->>'index')::INT, io->>'pkScript', io->>'runeId', (io->>'amount')::DECIMAL
+	FROM "runes_transactions" AS t, jsonb_array_elements(t."inputs") AS io;
+INSERT INTO "runes_transaction_io" ("tx_hash", "block_height", "tx_index", "io_type", "io_index", "pkscript", "rune_id", "amount")
+	SELECT t."hash", t."block_height", t."index", 'output', (io->>'index')::INT, io->>'pkScript', io->>'runeId', (io->>'amount')::DECIMAL
+	FROM "runes_transactions" AS t, jsonb_array_elements(t."outputs") AS io;
+INSERT INTO "runes_transaction_io" ("tx_hash", "block_height", "tx_index", "io_type", "io_index", "pkscript", "rune_id", "amount")
+	SELECT t."hash", t."block_height", t."index", 'mint', 0, NULL, m.key, m.value::DECIMAL
+	FROM "runes_transactions" AS t, jsonb_each_text(t."mints") AS m;
+INSERT INTO "runes_transaction_io" ("tx_hash", "block_height", "tx_index", "io_type", "io_index", "pkscript", "rune_id", "amount")
+	SELECT t."hash", t."block_height", t."index", 'burn', 0, NULL, b.key, b.value::DECIMAL
+	FROM "runes_transactions" AS t, jsonb_each_text(t."burns") AS b;
+
+-- create indexes after backfill for faster inserts
+CREATE INDEX IF NOT EXISTS runes_transaction_io_pkscript_block_height_idx ON "runes_transaction_io" USING BTREE ("pkscript", "block_height");
+CREATE INDEX IF NOT EXISTS runes_transaction_io_rune_id_block_height_idx ON "runes_transaction_io" USING BTREE ("rune_id", "block_height");
+CREATE INDEX IF NOT EXISTS runes_transaction_io_block_height_idx ON "runes_transaction_io" USING BTREE ("block_height");
+
+-- JSONB filters are replaced by "runes_transaction_io"
+DROP INDEX IF EXISTS runes_transactions_jsonb_idx;
+
+-- bump db version of existing indexer state
+INSERT INTO "runes_indexer_state" ("db_version", "event_hash_version")
+	SELECT 3, "event_hash_version" FROM "runes_indexer_state" ORDER BY "created_at" DESC LIMIT 1;
+
+COMMIT;
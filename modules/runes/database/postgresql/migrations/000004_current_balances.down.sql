@@ -0,0 +1,7 @@
+BEGIN;
+
+DELETE FROM "runes_indexer_state" WHERE "db_version" = 4;
+
+DROP TABLE IF EXISTS "runes_current_balances";
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+-- Latest non-zero balance of each pkscript and rune. Used to serve balance queries at the latest block height.
+CREATE TABLE IF NOT EXISTS "runes_current_balances" (
+	"pkscript" TEXT NOT NULL,
+	"rune_id" TEXT NOT NULL,
+	"amount" DECIMAL NOT NULL,
+	"block_height" INT NOT NULL, -- block height of the latest balance change
+	PRIMARY KEY ("pkscript", "rune_id")
+);
+
+-- backfill from balance history
+INSERT INTO "runes_current_balances" ("pkscript", "rune_id", "amount", "block_height")
+	SELECT "pkscript", "rune_id", "amount", "block_height" FROM (
+		SELECT DISTINCT ON ("pkscript", "rune_id") "pkscript", "rune_id", "amount", "block_height" FROM "runes_balances" ORDER BY "pkscript", "rune_id", "block_height" DESC
+	) AS latest WHERE "amount" > 0;
+
+CREATE INDEX IF NOT EXISTS runes_current_balances_rune_id_idx ON "runes_current_balances" USING BTREE ("rune_id");
+
+-- bump db version of existing indexer state
+INSERT INTO "runes_indexer_state" ("db_version", "event_hash_version")
+	SELECT 4, "event_hash_version" FROM "runes_indexer_state" ORDER BY "created_at" DESC LIMIT 1;
+
+COMMIT;
@@ -0,0 +1,98 @@
+BEGIN;
+
+DELETE FROM "runes_indexer_state" WHERE "db_version" = 5;
+
+-- rename partitioned tables and drop their constraints and indexes to free up the names
+ALTER TABLE "runes_transactions" RENAME TO "runes_transactions_partitioned";
+ALTER TABLE "runes_transactions_partitioned" DROP CONSTRAINT IF EXISTS "runes_transactions_pkey";
+DROP INDEX IF EXISTS runes_transactions_block_height_idx;
+
+ALTER TABLE "runes_runestones" RENAME TO "runes_runestones_partitioned";
+ALTER TABLE "runes_runestones_partitioned" DROP CONSTRAINT IF EXISTS "runes_runestones_pkey";
+
+ALTER TABLE "runes_outpoint_balances" RENAME TO "runes_outpoint_balances_partitioned";
+ALTER TABLE "runes_outpoint_balances_partitioned" DROP CONSTRAINT IF EXISTS "runes_outpoint_balances_pkey";
+DROP INDEX IF EXISTS runes_outpoint_balances_tx_hash_tx_idx_idx;
+DROP INDEX IF EXISTS runes_outpoint_balances_pkscript_block_height_spent_height_idx;
+DROP INDEX IF EXISTS runes_outpoint_balances_spent_height_idx;
+
+ALTER TABLE "runes_balances" RENAME TO "runes_balances_partitioned";
+ALTER TABLE "runes_balances_partitioned" DROP CONSTRAINT IF EXISTS "runes_balances_pkey";
+DROP INDEX IF EXISTS runes_balances_rune_id_block_height_idx;
+DROP INDEX IF EXISTS runes_balances_pkscript_block_height_idx;
+
+CREATE TABLE "runes_transactions" (
+	"hash" TEXT NOT NULL PRIMARY KEY,
+	"block_height" INT NOT NULL,
+	"index" INT NOT NULL,
+	"timestamp" TIMESTAMP NOT NULL,
+	"inputs" JSONB NOT NULL,
+	"outputs" JSONB NOT NULL,
+	"mints" JSONB NOT NULL,
+	"burns" JSONB NOT NULL,
+	"rune_etched" BOOLEAN NOT NULL
+);
+
+CREATE TABLE "runes_runestones" (
+	"tx_hash" TEXT NOT NULL PRIMARY KEY,
+	"block_height" INT NOT NULL,
+	"etching" BOOLEAN NOT NULL,
+	"etching_divisibility" SMALLINT,
+	"etching_premine" DECIMAL,
+	"etching_rune" TEXT,
+	"etching_spacers" INT,
+	"etching_symbol" INT,
+	"etching_terms" BOOLEAN,
+	"etching_terms_amount" DECIMAL,
+	"etching_terms_cap" DECIMAL,
+	"etching_terms_height_start" INT,
+	"etching_terms_height_end" INT,
+	"etching_terms_offset_start" INT,
+	"etching_terms_offset_end" INT,
+	"etching_turbo" BOOLEAN,
+	"edicts" JSONB NOT NULL DEFAULT '[]',
+	"mint" TEXT,
+	"pointer" INT,
+	"cenotaph" BOOLEAN NOT NULL,
+	"flaws" INT NOT NULL
+);
+
+CREATE TABLE "runes_outpoint_balances" (
+	"rune_id" TEXT NOT NULL,
+	"pkscript" TEXT NOT NULL,
+	"tx_hash" TEXT NOT NULL,
+	"tx_idx" INT NOT NULL, -- output index
+	"amount" DECIMAL NOT NULL,
+	"block_height" INT NOT NULL, -- block height when this output was created
+	"spent_height" INT, -- block height when this output was spent
+	PRIMARY KEY ("rune_id", "tx_hash", "tx_idx")
+);
+
+CREATE TABLE "runes_balances" (
+	"pkscript" TEXT NOT NULL,
+	"block_height" INT NOT NULL,
+	"rune_id" TEXT NOT NULL,
+	"amount" DECIMAL NOT NULL,
+	PRIMARY KEY ("pkscript", "rune_id", "block_height")
+);
+
+INSERT INTO "runes_transactions" SELECT "hash", "block_height", "index", "timestamp", "inputs", "outputs", "mints", "burns", "rune_etched" FROM "runes_transactions_partitioned";
+INSERT INTO "runes_runestones" SELECT "tx_hash", "block_height", "etching", "etching_divisibility", "etching_premine", "etching_rune", "etching_spacers", "etching_symbol", "etching_terms", "etching_terms_amount", "etching_terms_cap", "etching_terms_height_start", "etching_terms_height_end", "etching_terms_offset_start", "etching_terms_offset_end", "etching_turbo", "edicts", "mint", "pointer", "cenotaph", "flaws" FROM "runes_runestones_partitioned";
+INSERT INTO "runes_outpoint_balances" SELECT "rune_id", "pkscript", "tx_hash", "tx_idx", "amount", "block_height", "spent_height" FROM "runes_outpoint_balances_partitioned";
+INSERT INTO "runes_balances" SELECT "pkscript", "block_height", "rune_id", "amount" FROM "runes_balances_partitioned";
+
+-- dropping partitioned tables also drops their partitions
+DROP TABLE "runes_transactions_partitioned";
+DROP TABLE "runes_runestones_partitioned";
+DROP TABLE "runes_outpoint_balances_partitioned";
+DROP TABLE "runes_balances_partitioned";
+DROP FUNCTION IF EXISTS runes_create_partitions(INT, INT);
+
+CREATE INDEX IF NOT EXISTS runes_transactions_block_height_idx ON "runes_transactions" USING BTREE ("block_height");
+CREATE INDEX IF NOT EXISTS runes_outpoint_balances_tx_hash_tx_idx_idx ON "runes_outpoint_balances" USING BTREE ("tx_hash", "tx_idx");
+CREATE INDEX IF NOT EXISTS runes_outpoint_balances_pkscript_block_height_spent_height_idx ON "runes_outpoint_balances" USING BTREE ("pkscript", "block_height", "spent_height");
+CREATE INDEX IF NOT EXISTS runes_outpoint_balances_spent_height_idx ON "runes_outpoint_balances" USING BTREE ("spent_height");
+CREATE INDEX IF NOT EXISTS runes_balances_rune_id_block_height_idx ON "runes_balances" USING BTREE ("rune_id", "block_height");
+CREATE INDEX IF NOT EXISTS runes_balances_pkscript_block_height_idx ON "runes_balances" USING BTREE ("pkscript", "block_height");
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DELETE FROM "runes_indexer_state" WHERE "db_version" = 6;
+
+ALTER TABLE "runes_outpoint_balances" DROP COLUMN IF EXISTS "spent_tx_input_idx";
+ALTER TABLE "runes_outpoint_balances" DROP COLUMN IF EXISTS "spent_tx_hash";
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Reference to the transaction input that spent the outpoint
+ALTER TABLE "runes_outpoint_balances" ADD COLUMN IF NOT EXISTS "spent_tx_hash" TEXT; -- hash of the transaction that spent this output
+ALTER TABLE "runes_outpoint_balances" ADD COLUMN IF NOT EXISTS "spent_tx_input_idx" INT; -- input index of the transaction that spent this output
+
+-- backfill from inputs of existing transactions
+UPDATE "runes_outpoint_balances" AS o
+	SET "spent_tx_hash" = t."hash", "spent_tx_input_idx" = (io->>'index')::INT
+	FROM "runes_transactions" AS t, jsonb_array_elements(t."inputs") AS io
+	WHERE o."spent_height" IS NOT NULL
+		AND t."block_height" = o."spent_height"
+		AND io->>'txHash' = o."tx_hash"
+		AND (io->>'txOutIndex')::INT = o."tx_idx";
+
+-- bump db version of existing indexer state
+INSERT INTO "runes_indexer_state" ("db_version", "event_hash_version")
+	SELECT 6, "event_hash_version" FROM "runes_indexer_state" ORDER BY "created_at" DESC LIMIT 1;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DELETE FROM "runes_indexer_state" WHERE "db_version" = 7;
+
+ALTER TABLE "runes_outpoint_balances" DROP COLUMN IF EXISTS "value";
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- BTC value of the output in sats. Null for outputs indexed before this column was added.
+ALTER TABLE "runes_outpoint_balances" ADD COLUMN IF NOT EXISTS "value" BIGINT;
+
+-- bump db version of existing indexer state
+INSERT INTO "runes_indexer_state" ("db_version", "event_hash_version")
+	SELECT 7, "event_hash_version" FROM "runes_indexer_state" ORDER BY "created_at" DESC LIMIT 1;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DELETE FROM "runes_indexer_state" WHERE "db_version" = 8;
+
+DROP INDEX IF EXISTS runes_indexed_blocks_hash_idx;
+
+COMMIT;
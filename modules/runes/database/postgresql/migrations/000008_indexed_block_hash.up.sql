@@ -0,0 +1,10 @@
+BEGIN;
+
+-- lookup indexed blocks by hash, for reads pinned to a block hash
+CREATE INDEX IF NOT EXISTS runes_indexed_blocks_hash_idx ON "runes_indexed_blocks" USING BTREE ("hash");
+
+-- bump db version of existing indexer state
+INSERT INTO "runes_indexer_state" ("db_version", "event_hash_version")
+	SELECT 8, "event_hash_version" FROM "runes_indexer_state" ORDER BY "created_at" DESC LIMIT 1;
+
+COMMIT;
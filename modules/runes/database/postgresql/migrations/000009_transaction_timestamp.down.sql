@@ -0,0 +1,7 @@
+BEGIN;
+
+DELETE FROM "runes_indexer_state" WHERE "db_version" = 9;
+
+DROP INDEX IF EXISTS runes_transactions_timestamp_idx;
+
+COMMIT;
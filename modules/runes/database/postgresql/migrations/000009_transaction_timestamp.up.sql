@@ -0,0 +1,10 @@
+BEGIN;
+
+-- lookup block heights by timestamp, for time ranges of rune histories
+CREATE INDEX IF NOT EXISTS runes_transactions_timestamp_idx ON "runes_transactions" USING BTREE ("timestamp");
+
+-- bump db version of existing indexer state
+INSERT INTO "runes_indexer_state" ("db_version", "event_hash_version")
+	SELECT 9, "event_hash_version" FROM "runes_indexer_state" ORDER BY "created_at" DESC LIMIT 1;
+
+COMMIT;
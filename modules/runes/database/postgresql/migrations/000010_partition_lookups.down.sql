@@ -0,0 +1,8 @@
+BEGIN;
+
+DELETE FROM "runes_indexer_state" WHERE "db_version" = 10;
+
+DROP TABLE IF EXISTS "runes_outpoint_spends";
+DROP TABLE IF EXISTS "runes_transaction_heights";
+
+COMMIT;
@@ -0,0 +1,33 @@
+BEGIN;
+
+-- Primary keys of partitioned tables must include the partition key, so they can't enforce uniqueness across partitions,
+-- and lookups by hash alone scan every partition. These tables are keyed by hash and record the block height of the partition.
+
+-- block height of each rune transaction. Transaction hashes are unique across all partitions.
+CREATE TABLE IF NOT EXISTS "runes_transaction_heights" (
+	"hash" TEXT NOT NULL PRIMARY KEY,
+	"block_height" INT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS runes_transaction_heights_block_height_idx ON "runes_transaction_heights" USING BTREE ("block_height");
+
+INSERT INTO "runes_transaction_heights" ("hash", "block_height")
+	SELECT "hash", "block_height" FROM "runes_transactions";
+
+-- spent outpoints and the block height they were created at. Outpoints can only be spent once.
+CREATE TABLE IF NOT EXISTS "runes_outpoint_spends" (
+	"tx_hash" TEXT NOT NULL,
+	"tx_idx" INT NOT NULL, -- output index
+	"block_height" INT NOT NULL, -- block height when the output was created
+	"spent_height" INT NOT NULL, -- block height when the output was spent
+	PRIMARY KEY ("tx_hash", "tx_idx")
+);
+CREATE INDEX IF NOT EXISTS runes_outpoint_spends_spent_height_idx ON "runes_outpoint_spends" USING BTREE ("spent_height");
+
+INSERT INTO "runes_outpoint_spends" ("tx_hash", "tx_idx", "block_height", "spent_height")
+	SELECT DISTINCT "tx_hash", "tx_idx", "block_height", "spent_height" FROM "runes_outpoint_balances" WHERE "spent_height" IS NOT NULL;
+
+-- bump db version of existing indexer state
+INSERT INTO "runes_indexer_state" ("db_version", "event_hash_version")
+	SELECT 10, "event_hash_version" FROM "runes_indexer_state" ORDER BY "created_at" DESC LIMIT 1;
+
+COMMIT;
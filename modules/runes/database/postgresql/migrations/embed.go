@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations of the Runes module, so the binary can apply them from any working directory.
+package migrations
+
+import "embed"
+
+// MigrationsTable is the table used by golang-migrate to track the applied Runes migrations.
+const MigrationsTable = "runes_schema_migrations"
+
+//go:embed *.sql
+var FS embed.FS
@@ -42,7 +42,7 @@ VALUES(
 );
 
 -- name: BatchCreateRunesOutpointBalances :exec
-INSERT INTO runes_outpoint_balances ("rune_id", "pkscript", "tx_hash", "tx_idx", "amount", "block_height", "spent_height")
+INSERT INTO runes_outpoint_balances ("rune_id", "pkscript", "tx_hash", "tx_idx", "amount", "block_height", "spent_height", "value")
 VALUES(
   unnest(@rune_id_arr::TEXT[]),
   unnest(@pkscript_arr::TEXT[]),
@@ -50,18 +50,55 @@ VALUES(
   unnest(@tx_idx_arr::INT[]),
   unnest(@amount_arr::DECIMAL[]),
   unnest(@block_height_arr::INT[]),
-  unnest(@spent_height_arr::INT[]) -- nullable (need patch)
+  unnest(@spent_height_arr::INT[]), -- nullable (need patch)
+  unnest(@value_arr::BIGINT[]) -- nullable (need patch)
 );
 
 -- name: BatchSpendOutpointBalances :exec
+-- the block height range of the outpoints bounds the partitions that are scanned
 UPDATE runes_outpoint_balances
-	SET "spent_height" = @spent_height::INT
+	SET "spent_height" = @spent_height::INT, "spent_tx_hash" = "input"."spent_tx_hash", "spent_tx_input_idx" = "input"."spent_tx_input_idx"
 	FROM (
     SELECT 
       unnest(@tx_hash_arr::TEXT[]) AS tx_hash, 
-      unnest(@tx_idx_arr::INT[]) AS tx_idx
+      unnest(@tx_idx_arr::INT[]) AS tx_idx,
+      unnest(@block_height_arr::INT[]) AS block_height,
+      unnest(@spent_tx_hash_arr::TEXT[]) AS spent_tx_hash,
+      unnest(@spent_tx_input_idx_arr::INT[]) AS spent_tx_input_idx
     ) AS input
-	WHERE "runes_outpoint_balances"."tx_hash" = "input"."tx_hash" AND "runes_outpoint_balances"."tx_idx" = "input"."tx_idx";
+	WHERE "runes_outpoint_balances"."tx_hash" = "input"."tx_hash" AND "runes_outpoint_balances"."tx_idx" = "input"."tx_idx" AND "runes_outpoint_balances"."block_height" = "input"."block_height"
+		AND "runes_outpoint_balances"."block_height" BETWEEN @min_block_height::INT AND @max_block_height::INT;
+
+-- name: BatchSetOutPointValues :exec
+-- only sets the value of outpoint balances without a recorded value
+UPDATE runes_outpoint_balances
+	SET "value" = "input"."value"
+	FROM (
+    SELECT 
+      unnest(@tx_hash_arr::TEXT[]) AS tx_hash, 
+      unnest(@tx_idx_arr::INT[]) AS tx_idx,
+      unnest(@block_height_arr::INT[]) AS block_height,
+      unnest(@value_arr::BIGINT[]) AS value
+    ) AS input
+	WHERE "runes_outpoint_balances"."tx_hash" = "input"."tx_hash" AND "runes_outpoint_balances"."tx_idx" = "input"."tx_idx" AND "runes_outpoint_balances"."block_height" = "input"."block_height"
+		AND "runes_outpoint_balances"."value" IS NULL
+		AND "runes_outpoint_balances"."block_height" BETWEEN @min_block_height::INT AND @max_block_height::INT;
+
+-- name: BatchCreateOutPointSpends :exec
+INSERT INTO runes_outpoint_spends ("tx_hash", "tx_idx", "block_height", "spent_height")
+VALUES(
+  unnest(@tx_hash_arr::TEXT[]),
+  unnest(@tx_idx_arr::INT[]),
+  unnest(@block_height_arr::INT[]),
+  unnest(@spent_height_arr::INT[])
+);
+
+-- name: BatchCreateRuneTransactionHeights :exec
+INSERT INTO runes_transaction_heights ("hash", "block_height")
+VALUES(
+  unnest(@hash_arr::TEXT[]),
+  unnest(@block_height_arr::INT[])
+);
 
 -- name: BatchCreateRunestones :exec
 INSERT INTO runes_runestones ("tx_hash", "block_height", "etching", "etching_divisibility", "etching_premine", "etching_rune", "etching_spacers", "etching_symbol", "etching_terms", "etching_terms_amount", "etching_terms_cap", "etching_terms_height_start", "etching_terms_height_end", "etching_terms_offset_start", "etching_terms_offset_end", "etching_turbo", "edicts", "mint", "pointer", "cenotaph", "flaws")
@@ -102,3 +139,35 @@ VALUES (
   unnest(@burns_arr::JSONB[]),
   unnest(@rune_etched_arr::BOOLEAN[])
 );
+
+-- name: BatchCreateRuneTransactionIO :exec
+INSERT INTO runes_transaction_io ("tx_hash", "block_height", "tx_index", "io_type", "io_index", "pkscript", "rune_id", "amount")
+VALUES (
+  unnest(@tx_hash_arr::TEXT[]),
+  unnest(@block_height_arr::INT[]),
+  unnest(@tx_index_arr::INT[]),
+  unnest(@io_type_arr::TEXT[]),
+  unnest(@io_index_arr::INT[]),
+  unnest(@pkscript_arr::TEXT[]), -- nullable (need patch)
+  unnest(@rune_id_arr::TEXT[]),
+  unnest(@amount_arr::DECIMAL[])
+);
+
+-- name: BatchUpsertCurrentBalances :exec
+INSERT INTO runes_current_balances ("pkscript", "rune_id", "amount", "block_height")
+VALUES(
+  unnest(@pkscript_arr::TEXT[]),
+  unnest(@rune_id_arr::TEXT[]),
+  unnest(@amount_arr::DECIMAL[]),
+  unnest(@block_height_arr::INT[])
+)
+ON CONFLICT ("pkscript", "rune_id") DO UPDATE SET "amount" = EXCLUDED."amount", "block_height" = EXCLUDED."block_height";
+
+-- name: BatchDeleteCurrentBalances :exec
+DELETE FROM runes_current_balances
+	USING (
+    SELECT
+      unnest(@pkscript_arr::TEXT[]) AS pkscript,
+      unnest(@rune_id_arr::TEXT[]) AS rune_id
+    ) AS input
+	WHERE "runes_current_balances"."pkscript" = "input"."pkscript" AND "runes_current_balances"."rune_id" = "input"."rune_id";
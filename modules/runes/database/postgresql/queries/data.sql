@@ -4,15 +4,61 @@ WITH balances AS (
 )
 SELECT * FROM balances WHERE amount > 0 ORDER BY amount DESC, rune_id LIMIT $3 OFFSET $4;
 
+-- name: GetCurrentBalancesByPkScript :many
+SELECT * FROM runes_current_balances WHERE pkscript = $1 ORDER BY amount DESC, rune_id LIMIT $2 OFFSET $3;
+
 -- name: GetBalancesByRuneId :many
+-- The latest balance of each pkscript is selected with an anti-join instead of DISTINCT ON, so the cursor filters the scanned balances instead of the deduplicated result.
 WITH balances AS (
-  SELECT DISTINCT ON (pkscript) * FROM runes_balances WHERE rune_id = $1 AND block_height <= $2 ORDER BY pkscript, block_height DESC
+  SELECT * FROM runes_balances WHERE rune_id = $1 AND block_height <= $2 AND amount > 0 AND (
+      @use_cursor::BOOLEAN = FALSE -- if @use_cursor is TRUE, only return balances after the cursor
+      OR amount < @cursor_amount::DECIMAL
+      OR (amount = @cursor_amount::DECIMAL AND pkscript > @cursor_pkscript::TEXT)
+    ) AND NOT EXISTS (
+      SELECT 1 FROM runes_balances AS newer WHERE newer.pkscript = runes_balances.pkscript AND newer.rune_id = runes_balances.rune_id AND newer.block_height > runes_balances.block_height AND newer.block_height <= $2
+    )
 )
-SELECT * FROM balances WHERE amount > 0 ORDER BY amount DESC, pkscript LIMIT $3 OFFSET $4;
+SELECT * FROM balances ORDER BY amount DESC, pkscript LIMIT $3 OFFSET $4;
+
+-- name: GetHolderChangesByRuneId :many
+WITH changed AS (
+  SELECT DISTINCT pkscript FROM runes_balances WHERE rune_id = @rune_id AND block_height > @from_block AND block_height <= @to_block
+), diffs AS (
+  SELECT changed.pkscript,
+    COALESCE((SELECT amount FROM runes_balances WHERE runes_balances.pkscript = changed.pkscript AND rune_id = @rune_id AND block_height <= @from_block ORDER BY block_height DESC LIMIT 1), 0)::DECIMAL AS from_amount,
+    COALESCE((SELECT amount FROM runes_balances WHERE runes_balances.pkscript = changed.pkscript AND rune_id = @rune_id AND block_height <= @to_block ORDER BY block_height DESC LIMIT 1), 0)::DECIMAL AS to_amount
+  FROM changed
+)
+SELECT * FROM diffs WHERE from_amount <> to_amount AND (
+    @change_type::TEXT = '' -- if @change_type is not empty, only return changes of that type
+    OR (@change_type::TEXT = 'appeared' AND from_amount = 0)
+    OR (@change_type::TEXT = 'disappeared' AND to_amount = 0)
+    OR (@change_type::TEXT = 'increased' AND from_amount > 0 AND to_amount > from_amount)
+    OR (@change_type::TEXT = 'decreased' AND to_amount > 0 AND to_amount < from_amount)
+  ) ORDER BY ABS(to_amount - from_amount) DESC, pkscript LIMIT $1 OFFSET $2;
 
 -- name: GetBalanceByPkScriptAndRuneId :one
 SELECT * FROM runes_balances WHERE pkscript = $1 AND rune_id = $2 AND block_height <= $3 ORDER BY block_height DESC LIMIT 1;
 
+-- name: GetBalanceHistoryByPkScript :many
+WITH changes AS (
+  SELECT rune_id, block_height, amount, LAG(amount, 1, 0) OVER (PARTITION BY rune_id ORDER BY block_height) AS previous_amount FROM runes_balances
+    WHERE pkscript = @pkscript AND (
+      @filter_rune_id::BOOLEAN = FALSE -- if @filter_rune_id is TRUE, apply rune_id filter
+      OR rune_id = @rune_id::TEXT
+    ) AND block_height <= @to_block
+), buckets AS (
+  -- group changes into buckets of @interval blocks, starting from @from_block
+  SELECT rune_id, block_height, amount,
+    FIRST_VALUE(previous_amount) OVER (PARTITION BY rune_id, (block_height - @from_block) / @interval::INTEGER ORDER BY block_height) AS bucket_previous_amount,
+    ROW_NUMBER() OVER (PARTITION BY rune_id, (block_height - @from_block) / @interval::INTEGER ORDER BY block_height DESC) AS bucket_row
+  FROM changes WHERE block_height >= @from_block
+)
+SELECT rune_id, block_height, amount, bucket_previous_amount::DECIMAL AS previous_amount,
+  -- every balance change comes from a rune transaction in the same block
+  (SELECT "timestamp" FROM runes_transactions WHERE runes_transactions.block_height = buckets.block_height LIMIT 1)::TIMESTAMP AS "timestamp"
+  FROM buckets WHERE bucket_row = 1 ORDER BY block_height, rune_id LIMIT $1 OFFSET $2;
+
 -- name: GetTotalHoldersByRuneIds :many
 WITH balances AS (
   SELECT DISTINCT ON (rune_id, pkscript) * FROM runes_balances WHERE rune_id = ANY(@rune_ids::TEXT[]) AND block_height <= @block_height ORDER BY rune_id, pkscript, block_height DESC
@@ -22,24 +68,30 @@ SELECT rune_id, COUNT(DISTINCT pkscript) FROM balances WHERE amount > 0 GROUP BY
 -- name: GetOutPointBalancesAtOutPoint :many
 SELECT * FROM runes_outpoint_balances WHERE tx_hash = $1 AND tx_idx = $2;
 
+-- name: GetOutPointTxsWithoutValue :many
+-- returns the transactions after the cursor that created outpoint balances without a recorded value
+SELECT DISTINCT tx_hash, block_height FROM runes_outpoint_balances WHERE value IS NULL AND tx_hash > @cursor_tx_hash ORDER BY tx_hash LIMIT @limit;
+
 -- name: GetRunesUTXOsByPkScript :many
-SELECT tx_hash, tx_idx, max("pkscript") as pkscript, array_agg("rune_id") as rune_ids, array_agg("amount") as amounts 
+SELECT tx_hash, tx_idx, max("pkscript") as pkscript, array_agg("rune_id") as rune_ids, array_agg("amount") as amounts, max("value") as value 
   FROM runes_outpoint_balances 
   WHERE
     pkscript = @pkScript AND
     block_height <= @block_height AND
-    (spent_height IS NULL OR spent_height > @block_height)
+    (spent_height IS NULL OR spent_height > @block_height) AND
+    (@use_cursor::BOOLEAN = FALSE OR (tx_hash, tx_idx) > (@cursor_tx_hash::TEXT, @cursor_tx_idx::INTEGER)) -- if @use_cursor is TRUE, only return outpoints after the cursor
   GROUP BY tx_hash, tx_idx
   ORDER BY tx_hash, tx_idx 
   LIMIT $1 OFFSET $2;
 
 -- name: GetRunesUTXOsByRuneIdAndPkScript :many
-SELECT tx_hash, tx_idx, max("pkscript") as pkscript, array_agg("rune_id") as rune_ids, array_agg("amount") as amounts 
+SELECT tx_hash, tx_idx, max("pkscript") as pkscript, array_agg("rune_id") as rune_ids, array_agg("amount") as amounts, max("value") as value 
   FROM runes_outpoint_balances 
   WHERE
     pkscript = @pkScript AND 
     block_height <= @block_height AND 
-    (spent_height IS NULL OR spent_height > @block_height)
+    (spent_height IS NULL OR spent_height > @block_height) AND
+    (@use_cursor::BOOLEAN = FALSE OR (tx_hash, tx_idx) > (@cursor_tx_hash::TEXT, @cursor_tx_idx::INTEGER)) -- if @use_cursor is TRUE, only return outpoints after the cursor
   GROUP BY tx_hash, tx_idx
   HAVING array_agg("rune_id") @> @rune_ids::text[] 
   ORDER BY tx_hash, tx_idx 
@@ -63,6 +115,33 @@ SELECT * FROM runes_entries
   LEFT JOIN states ON runes_entries.rune_id = states.rune_id
   WHERE runes_entries.rune_id = ANY(@rune_ids::text[]) AND etching_block <= @height;
 
+-- name: GetRuneEntryStateHistory :many
+-- Only the points of the requested page are generated. Holders counts are computed in a single pass over the balances of the rune:
+-- each balance change adds 1 when a pkscript starts holding the rune and subtracts 1 when it stops, and is counted at the first point at or after the change.
+WITH bounds AS (
+  SELECT @from_block::BIGINT + @offset::BIGINT * @interval::BIGINT AS first_point,
+    LEAST(@to_block::BIGINT, @from_block::BIGINT + (@offset::BIGINT + @limit::BIGINT - 1) * @interval::BIGINT) AS last_point
+), points AS (
+  SELECT generate_series(bounds.first_point, bounds.last_point, @interval::BIGINT) AS block_height FROM bounds
+), holder_changes AS (
+  SELECT GREATEST(bounds.first_point, @from_block::BIGINT + CEIL((runes_balances.block_height - @from_block::BIGINT)::NUMERIC / @interval::BIGINT)::BIGINT * @interval::BIGINT) AS point,
+    (amount > 0)::INTEGER - (COALESCE(LAG(amount) OVER (PARTITION BY pkscript ORDER BY runes_balances.block_height), 0) > 0)::INTEGER AS delta
+    FROM runes_balances, bounds WHERE rune_id = @rune_id AND runes_balances.block_height <= bounds.last_point
+), holders AS (
+  SELECT points.block_height, SUM(COALESCE(SUM(holder_changes.delta), 0)) OVER (ORDER BY points.block_height) AS holders_count
+    FROM points LEFT JOIN holder_changes ON holder_changes.point = points.block_height
+    GROUP BY points.block_height
+)
+SELECT holders.block_height::INTEGER AS block_height, states.mints, states.burned_amount, holders.holders_count::BIGINT AS holders_count,
+  -- blocks are not timestamped, so use the timestamp of the latest rune transaction at or before the point
+  (SELECT "timestamp" FROM runes_transactions WHERE runes_transactions.block_height <= holders.block_height ORDER BY runes_transactions.block_height DESC LIMIT 1)::TIMESTAMP AS "timestamp"
+  FROM holders
+  JOIN LATERAL (
+    -- select latest state at the point, skipping points before the etching
+    SELECT mints, burned_amount FROM runes_entry_states WHERE runes_entry_states.rune_id = @rune_id AND runes_entry_states.block_height <= holders.block_height ORDER BY runes_entry_states.block_height DESC LIMIT 1
+  ) AS states ON TRUE
+  ORDER BY holders.block_height;
+
 -- name: GetRuneEntries :many
 WITH states AS (
   -- select latest state
@@ -73,6 +152,8 @@ SELECT * FROM runes_entries
   WHERE (
     @search = '' OR
     runes_entries.rune ILIKE @search || '%'
+  ) AND (
+    @use_cursor::BOOLEAN = FALSE OR runes_entries.number > @cursor_number::BIGINT -- if @use_cursor is TRUE, only return entries after the cursor
   )
   ORDER BY runes_entries.number 
   LIMIT @_limit OFFSET @_offset;
@@ -102,8 +183,12 @@ SELECT * FROM runes_entries
   ) AND (
     @search::text = '' OR
     runes_entries.rune ILIKE '%' || @search::text || '%'
+  ) AND (
+    @use_cursor::BOOLEAN = FALSE -- if @use_cursor is TRUE, only return entries after the cursor
+    OR states.mints < @cursor_mints::DECIMAL
+    OR (states.mints = @cursor_mints::DECIMAL AND runes_entries.number > @cursor_number::BIGINT)
   )
-  ORDER BY states.mints DESC
+  ORDER BY states.mints DESC, runes_entries.number
   LIMIT @_limit OFFSET @_offset;
 
 -- name: GetRuneIdFromRune :one
@@ -114,24 +199,30 @@ SELECT * FROM runes_transactions
 	LEFT JOIN runes_runestones ON runes_transactions.hash = runes_runestones.tx_hash
 	WHERE (
     @filter_pk_script::BOOLEAN = FALSE -- if @filter_pk_script is TRUE, apply pk_script filter
-    OR runes_transactions.outputs @> @pk_script_param::JSONB 
-    OR runes_transactions.inputs @> @pk_script_param::JSONB
+    OR runes_transactions.hash IN (
+      SELECT runes_transaction_io.tx_hash FROM runes_transaction_io
+        WHERE runes_transaction_io.pkscript = @pk_script::TEXT AND @from_block <= runes_transaction_io.block_height AND runes_transaction_io.block_height <= @to_block
+    )
   ) AND (
     @filter_rune_id::BOOLEAN = FALSE -- if @filter_rune_id is TRUE, apply rune_id filter
-    OR runes_transactions.outputs @> @rune_id_param::JSONB 
-    OR runes_transactions.inputs @> @rune_id_param::JSONB 
-    OR runes_transactions.mints ? @rune_id 
-    OR runes_transactions.burns ? @rune_id
+    OR runes_transactions.hash IN (
+      SELECT runes_transaction_io.tx_hash FROM runes_transaction_io
+        WHERE runes_transaction_io.rune_id = @rune_id::TEXT AND @from_block <= runes_transaction_io.block_height AND runes_transaction_io.block_height <= @to_block
+    )
     OR (runes_transactions.rune_etched = TRUE AND runes_transactions.block_height = @rune_id_block_height AND runes_transactions.index = @rune_id_tx_index)
   ) AND (
     @from_block <= runes_transactions.block_height AND runes_transactions.block_height <= @to_block
+  ) AND (
+    @use_cursor::BOOLEAN = FALSE -- if @use_cursor is TRUE, only return transactions before the cursor
+    OR (runes_transactions.block_height, runes_transactions.index) < (@cursor_block_height::INTEGER, @cursor_index::INTEGER)
   )
 ORDER BY runes_transactions.block_height DESC, runes_transactions.index DESC LIMIT $1 OFFSET $2;
 
 -- name: GetRuneTransaction :one
+-- the block height is looked up first, so that only the partition of the transaction is scanned
 SELECT * FROM runes_transactions
-  LEFT JOIN runes_runestones ON runes_transactions.hash = runes_runestones.tx_hash
-  WHERE hash = $1 LIMIT 1;
+  LEFT JOIN runes_runestones ON runes_transactions.hash = runes_runestones.tx_hash AND runes_transactions.block_height = runes_runestones.block_height
+  WHERE hash = $1 AND runes_transactions.block_height = (SELECT runes_transaction_heights.block_height FROM runes_transaction_heights WHERE runes_transaction_heights.hash = $1) LIMIT 1;
 
 -- name: CountRuneEntries :one
 SELECT COUNT(*) FROM runes_entries;
@@ -151,7 +242,7 @@ INSERT INTO runes_runestones (tx_hash, block_height, etching, etching_divisibili
   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21);
 
 -- name: CreateOutPointBalance :exec
-INSERT INTO runes_outpoint_balances (rune_id, pkscript, tx_hash, tx_idx, amount, block_height, spent_height) VALUES ($1, $2, $3, $4, $5, $6, $7);
+INSERT INTO runes_outpoint_balances (rune_id, pkscript, tx_hash, tx_idx, amount, block_height, spent_height, value) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
 
 -- name: SpendOutPointBalance :exec
 UPDATE runes_outpoint_balances SET spent_height = $1 WHERE tx_hash = $2 AND tx_idx = $3;
@@ -165,6 +256,12 @@ SELECT * FROM runes_indexed_blocks ORDER BY height DESC LIMIT 1;
 -- name: GetIndexedBlockByHeight :one
 SELECT * FROM runes_indexed_blocks WHERE height = $1;
 
+-- name: GetIndexedBlockByHash :one
+SELECT * FROM runes_indexed_blocks WHERE hash = $1;
+
+-- name: GetBlockHeightAtTimestamp :one
+SELECT block_height FROM runes_transactions WHERE "timestamp" <= $1 ORDER BY "timestamp" DESC LIMIT 1;
+
 -- name: CreateIndexedBlock :exec
 INSERT INTO runes_indexed_blocks (hash, height, prev_hash, event_hash, cumulative_event_hash) VALUES ($1, $2, $3, $4, $5);
 
@@ -180,6 +277,12 @@ DELETE FROM runes_entry_states WHERE block_height >= $1;
 -- name: DeleteRuneTransactionsSinceHeight :exec
 DELETE FROM runes_transactions WHERE block_height >= $1;
 
+-- name: DeleteRuneTransactionHeightsSinceHeight :exec
+DELETE FROM runes_transaction_heights WHERE block_height >= $1;
+
+-- name: DeleteRuneTransactionIOSinceHeight :exec
+DELETE FROM runes_transaction_io WHERE block_height >= $1;
+
 -- name: DeleteRunestonesSinceHeight :exec
 DELETE FROM runes_runestones WHERE block_height >= $1;
 
@@ -187,7 +290,76 @@ DELETE FROM runes_runestones WHERE block_height >= $1;
 DELETE FROM runes_outpoint_balances WHERE block_height >= $1;
 
 -- name: UnspendOutPointBalancesSinceHeight :exec
-UPDATE runes_outpoint_balances SET spent_height = NULL WHERE spent_height >= $1;
+-- the outpoints and their creation heights are looked up from the spends, so that only the partitions of the spent outpoints are scanned
+UPDATE runes_outpoint_balances SET spent_height = NULL, spent_tx_hash = NULL, spent_tx_input_idx = NULL
+  FROM runes_outpoint_spends AS spent
+  WHERE spent.spent_height >= @spent_height
+    AND runes_outpoint_balances.tx_hash = spent.tx_hash AND runes_outpoint_balances.tx_idx = spent.tx_idx AND runes_outpoint_balances.block_height = spent.block_height
+    AND runes_outpoint_balances.block_height >= (SELECT COALESCE(MIN(block_height), @spent_height) FROM runes_outpoint_spends WHERE runes_outpoint_spends.spent_height >= @spent_height);
+
+-- name: DeleteOutPointSpendsSinceHeight :exec
+DELETE FROM runes_outpoint_spends WHERE spent_height >= $1;
 
 -- name: DeleteRuneBalancesSinceHeight :exec
 DELETE FROM runes_balances WHERE block_height >= $1;
+
+-- name: GetLatestPrunedHeight :one
+SELECT pruned_height FROM runes_prune_state ORDER BY created_at DESC LIMIT 1;
+
+-- name: CreatePruneState :exec
+INSERT INTO runes_prune_state (pruned_height) VALUES ($1);
+
+-- name: PruneSpentOutPointBalances :execrows
+DELETE FROM runes_outpoint_balances WHERE spent_height < @pruned_height;
+
+-- name: PruneOutPointSpends :exec
+DELETE FROM runes_outpoint_spends WHERE spent_height < @pruned_height;
+
+-- name: PruneRuneBalances :execrows
+DELETE FROM runes_balances AS b WHERE b.block_height < @pruned_height AND EXISTS (
+  SELECT 1 FROM runes_balances AS newer WHERE newer.pkscript = b.pkscript AND newer.rune_id = b.rune_id AND newer.block_height > b.block_height AND newer.block_height <= @pruned_height
+);
+
+-- name: DeleteCurrentBalancesSinceHeight :exec
+-- delete current balances of all pkscript and rune pairs that changed since the given height
+DELETE FROM runes_current_balances WHERE (pkscript, rune_id) IN (
+  SELECT DISTINCT pkscript, rune_id FROM runes_balances WHERE block_height >= @block_height
+);
+
+-- name: RestoreCurrentBalancesBeforeHeight :exec
+-- restore current balances of all pkscript and rune pairs that changed since the given height from the latest balance before that height
+INSERT INTO runes_current_balances (pkscript, rune_id, amount, block_height)
+SELECT latest.pkscript, latest.rune_id, latest.amount, latest.block_height FROM (
+  SELECT DISTINCT ON (runes_balances.pkscript, runes_balances.rune_id) runes_balances.pkscript, runes_balances.rune_id, runes_balances.amount, runes_balances.block_height FROM runes_balances
+    WHERE runes_balances.block_height < @block_height AND (runes_balances.pkscript, runes_balances.rune_id) IN (
+      SELECT DISTINCT changed.pkscript, changed.rune_id FROM runes_balances AS changed WHERE changed.block_height >= @block_height
+    )
+    ORDER BY runes_balances.pkscript, runes_balances.rune_id, runes_balances.block_height DESC
+) AS latest WHERE latest.amount > 0;
+
+-- name: CreatePartitions :one
+-- create partitions of partitioned tables to cover the given block height range, returns the exclusive upper bound of the created partitions
+SELECT runes_create_partitions(@from_height::INT, @to_height::INT)::INT AS partition_end_height;
+
+-- name: GetRuneTransactionsByHashes :many
+-- the block heights are looked up first, so that only the partitions of the transactions are scanned
+SELECT * FROM runes_transactions
+  LEFT JOIN runes_runestones ON runes_transactions.hash = runes_runestones.tx_hash AND runes_transactions.block_height = runes_runestones.block_height
+  WHERE (runes_transactions.hash, runes_transactions.block_height) IN (
+    SELECT runes_transaction_heights.hash, runes_transaction_heights.block_height FROM runes_transaction_heights WHERE runes_transaction_heights.hash = ANY(@hashes::TEXT[])
+  );
+
+-- name: CountRevertRowsSinceHeight :one
+-- count the rows that are deleted or updated when reverting data since the given height
+SELECT
+  (SELECT COUNT(*) FROM runes_indexed_blocks WHERE height >= @from_height) AS indexed_blocks,
+  (SELECT COUNT(*) FROM runes_entries WHERE etching_block >= @from_height) AS rune_entries,
+  (SELECT COUNT(*) FROM runes_entry_states WHERE block_height >= @from_height) AS rune_entry_states,
+  (SELECT COUNT(*) FROM runes_transactions WHERE block_height >= @from_height) AS rune_transactions,
+  (SELECT COUNT(*) FROM runes_transaction_io WHERE block_height >= @from_height) AS rune_transaction_io,
+  (SELECT COUNT(*) FROM runes_runestones WHERE block_height >= @from_height) AS runestones,
+  (SELECT COUNT(*) FROM runes_outpoint_balances WHERE block_height >= @from_height) AS outpoint_balances,
+  (SELECT COUNT(*) FROM runes_outpoint_spends AS spent JOIN runes_outpoint_balances ON runes_outpoint_balances.tx_hash = spent.tx_hash AND runes_outpoint_balances.tx_idx = spent.tx_idx AND runes_outpoint_balances.block_height = spent.block_height
+    WHERE spent.spent_height >= @from_height AND spent.block_height < @from_height) AS spent_outpoint_balances,
+  (SELECT COUNT(*) FROM runes_balances WHERE block_height >= @from_height) AS balances,
+  (SELECT COUNT(*) FROM (SELECT DISTINCT pkscript, rune_id FROM runes_balances WHERE block_height >= @from_height) AS changed_balances) AS current_balances;
@@ -0,0 +1,66 @@
+-- name: GetBalanceMismatches :many
+-- get pkscript and rune pairs whose sum of unspent outpoint balances doesn't match the balance at the given height
+WITH outpoint_balances AS (
+  SELECT pkscript, rune_id, SUM(amount) AS amount FROM runes_outpoint_balances
+    WHERE block_height <= @block_height AND (spent_height IS NULL OR spent_height > @block_height)
+    GROUP BY pkscript, rune_id
+), balances AS (
+  SELECT DISTINCT ON (pkscript, rune_id) pkscript, rune_id, amount FROM runes_balances WHERE block_height <= @block_height ORDER BY pkscript, rune_id, block_height DESC
+)
+SELECT COALESCE(outpoint_balances.pkscript, balances.pkscript)::TEXT AS pkscript, COALESCE(outpoint_balances.rune_id, balances.rune_id)::TEXT AS rune_id,
+  COALESCE(outpoint_balances.amount, 0)::DECIMAL AS outpoint_amount, COALESCE(balances.amount, 0)::DECIMAL AS balance_amount
+  FROM outpoint_balances
+  FULL OUTER JOIN balances ON outpoint_balances.pkscript = balances.pkscript AND outpoint_balances.rune_id = balances.rune_id
+  WHERE COALESCE(outpoint_balances.amount, 0) <> COALESCE(balances.amount, 0)
+  ORDER BY 1, 2;
+
+-- name: GetSupplyMismatches :many
+-- get runes whose circulating supply doesn't match premine + mints * terms amount - burned amount at the given height
+WITH states AS (
+  SELECT DISTINCT ON (rune_id) rune_id, mints, burned_amount FROM runes_entry_states WHERE block_height <= @block_height ORDER BY rune_id, block_height DESC
+), balances AS (
+  SELECT DISTINCT ON (pkscript, rune_id) pkscript, rune_id, amount FROM runes_balances WHERE block_height <= @block_height ORDER BY pkscript, rune_id, block_height DESC
+), circulating AS (
+  SELECT rune_id, SUM(amount) AS amount FROM balances GROUP BY rune_id
+)
+SELECT runes_entries.rune_id, runes_entries.premine, COALESCE(runes_entries.terms_amount, 0)::DECIMAL AS terms_amount,
+  COALESCE(states.mints, 0)::DECIMAL AS mints, COALESCE(states.burned_amount, 0)::DECIMAL AS burned_amount, COALESCE(circulating.amount, 0)::DECIMAL AS circulating_supply
+  FROM runes_entries
+  LEFT JOIN states ON runes_entries.rune_id = states.rune_id
+  LEFT JOIN circulating ON runes_entries.rune_id = circulating.rune_id
+  WHERE runes_entries.etching_block <= @block_height
+    AND runes_entries.premine + COALESCE(runes_entries.terms_amount, 0) * COALESCE(states.mints, 0) - COALESCE(states.burned_amount, 0) <> COALESCE(circulating.amount, 0)
+  ORDER BY runes_entries.number;
+
+-- name: GetIndexedBlocksInRange :many
+SELECT * FROM runes_indexed_blocks WHERE height >= @from_height AND height <= @to_height ORDER BY height;
+
+-- name: GetRuneEntriesEtchedAtHeight :many
+WITH states AS (
+  SELECT * FROM runes_entry_states WHERE block_height = @height
+)
+SELECT * FROM runes_entries
+  LEFT JOIN states ON runes_entries.rune_id = states.rune_id
+  WHERE runes_entries.etching_block = @height;
+
+-- name: GetRuneEntryStatesAtHeight :many
+WITH states AS (
+  SELECT * FROM runes_entry_states WHERE block_height = @height
+)
+SELECT * FROM runes_entries
+  LEFT JOIN states ON runes_entries.rune_id = states.rune_id
+  WHERE states.rune_id IS NOT NULL;
+
+-- name: GetOutPointBalancesCreatedAtHeight :many
+SELECT * FROM runes_outpoint_balances WHERE block_height = $1;
+
+-- name: GetOutPointBalancesSpentAtHeight :many
+SELECT * FROM runes_outpoint_balances WHERE spent_height = $1;
+
+-- name: GetRuneBalancesAtHeight :many
+SELECT * FROM runes_balances WHERE block_height = $1;
+
+-- name: GetRuneTransactionsAtHeight :many
+SELECT * FROM runes_transactions
+  LEFT JOIN runes_runestones ON runes_transactions.hash = runes_runestones.tx_hash AND runes_transactions.block_height = runes_runestones.block_height
+  WHERE runes_transactions.block_height = $1;
@@ -0,0 +1,23 @@
+-- name: GetSnapshotRuneEntries :many
+WITH entries AS (
+  SELECT * FROM runes_entries WHERE etching_block <= @height AND number > @after_number ORDER BY number LIMIT @_limit
+), states AS (
+  -- select latest state
+  SELECT DISTINCT ON (rune_id) * FROM runes_entry_states WHERE rune_id IN (SELECT rune_id FROM entries) AND block_height <= @height ORDER BY rune_id, block_height DESC
+)
+SELECT * FROM entries
+  LEFT JOIN states ON entries.rune_id = states.rune_id
+  ORDER BY entries.number;
+
+-- name: GetSnapshotOutPointBalances :many
+SELECT * FROM runes_outpoint_balances
+  WHERE block_height <= @block_height AND (spent_height IS NULL OR spent_height > @block_height)
+    AND (tx_hash, tx_idx, rune_id) > (@after_tx_hash::TEXT, @after_tx_idx::INT, @after_rune_id::TEXT)
+  ORDER BY tx_hash, tx_idx, rune_id
+  LIMIT @_limit;
+
+-- name: GetSnapshotBalances :many
+SELECT DISTINCT ON (pkscript, rune_id) * FROM runes_balances
+  WHERE block_height <= @block_height AND (pkscript, rune_id) > (@after_pkscript::TEXT, @after_rune_id::TEXT)
+  ORDER BY pkscript, rune_id, block_height DESC
+  LIMIT @_limit;
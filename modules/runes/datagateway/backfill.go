@@ -0,0 +1,17 @@
+package datagateway
+
+import (
+	"context"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+)
+
+// BackfillDataGateway provides the queries used to backfill data of outpoint balances indexed before it was recorded.
+type BackfillDataGateway interface {
+	// GetOutPointTxsWithoutValue returns up to limit transactions that created outpoint balances without a recorded value, sorted by tx hash.
+	// Only transactions with a tx hash greater than after are returned.
+	GetOutPointTxsWithoutValue(ctx context.Context, after chainhash.Hash, limit int32) ([]*entity.TxAtHeight, error)
+	// SetOutPointValues records the values of the outpoint balances that don't have a recorded value. Values of outpoints without balances are ignored.
+	SetOutPointValues(ctx context.Context, values []*entity.OutPointValue) error
+}
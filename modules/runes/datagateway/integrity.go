@@ -0,0 +1,21 @@
+package datagateway
+
+import (
+	"context"
+
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+)
+
+// IntegrityDataGateway provides the queries used to verify the internal consistency of the runes data.
+type IntegrityDataGateway interface {
+	// GetBalanceMismatches returns the pkscript and rune pairs whose sum of unspent outpoint balances doesn't match the balance at the given blockHeight.
+	GetBalanceMismatches(ctx context.Context, blockHeight uint64) ([]*entity.BalanceMismatch, error)
+	// GetSupplyMismatches returns the runes whose circulating supply doesn't match premine + mints * terms amount - burned amount at the given blockHeight.
+	GetSupplyMismatches(ctx context.Context, blockHeight uint64) ([]*entity.SupplyMismatch, error)
+	// GetIndexedBlocksInRange returns the indexed blocks in the given height range (inclusive), sorted by height.
+	GetIndexedBlocksInRange(ctx context.Context, fromHeight, toHeight int64) ([]*entity.IndexedBlock, error)
+	// GetBlockEventData returns the runes data persisted at the given blockHeight. Data of pruned heights is incomplete.
+	GetBlockEventData(ctx context.Context, blockHeight uint64) (*entity.BlockEventData, error)
+	// GetLatestIndexerState returns the latest indexer state, with the event hash version the data was indexed with. Returns errs.NotFound if the indexer state is not set.
+	GetLatestIndexerState(ctx context.Context) (entity.IndexerState, error)
+}
@@ -2,6 +2,7 @@ package datagateway
 
 import (
 	"context"
+	"time"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -26,13 +27,18 @@ type RunesDataGatewayWithTx interface {
 type RunesReaderDataGateway interface {
 	GetLatestBlock(ctx context.Context) (types.BlockHeader, error)
 	GetIndexedBlockByHeight(ctx context.Context, height int64) (*entity.IndexedBlock, error)
+	GetIndexedBlockByHash(ctx context.Context, hash chainhash.Hash) (*entity.IndexedBlock, error)
+	// GetBlockHeightAtTimestamp returns the height of the latest block with a rune transaction at or before the timestamp. Returns errs.NotFound if there is none.
+	GetBlockHeightAtTimestamp(ctx context.Context, timestamp time.Time) (uint64, error)
 	// GetRuneTransactions returns the runes transactions, filterable by pkScript, runeId and height. If pkScript, runeId or height is zero value, that filter is ignored.
-	GetRuneTransactions(ctx context.Context, pkScript []byte, runeId runes.RuneId, fromBlock, toBlock uint64, limit int32, offset int32) ([]*entity.RuneTransaction, error)
+	GetRuneTransactions(ctx context.Context, pkScript []byte, runeId runes.RuneId, fromBlock, toBlock uint64, cursor *entity.RuneTransactionCursor, limit int32, offset int32) ([]*entity.RuneTransaction, error)
 	GetRuneTransaction(ctx context.Context, txHash chainhash.Hash) (*entity.RuneTransaction, error)
+	// GetRuneTransactionsByHashes returns the runes transactions of the given hashes. Hashes that are not rune transactions are omitted from the result.
+	GetRuneTransactionsByHashes(ctx context.Context, txHashes []chainhash.Hash) (map[chainhash.Hash]*entity.RuneTransaction, error)
 
 	GetRunesBalancesAtOutPoint(ctx context.Context, outPoint wire.OutPoint) (map[runes.RuneId]*entity.OutPointBalance, error)
-	GetRunesUTXOsByRuneIdAndPkScript(ctx context.Context, runeId runes.RuneId, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.RunesUTXO, error)
-	GetRunesUTXOsByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.RunesUTXO, error)
+	GetRunesUTXOsByRuneIdAndPkScript(ctx context.Context, runeId runes.RuneId, pkScript []byte, blockHeight uint64, cursor *wire.OutPoint, limit int32, offset int32) ([]*entity.RunesUTXOWithValue, error)
+	GetRunesUTXOsByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, cursor *wire.OutPoint, limit int32, offset int32) ([]*entity.RunesUTXOWithValue, error)
 	// GetRuneIdFromRune returns the RuneId for the given rune. Returns errs.NotFound if the rune entry is not found.
 	GetRuneIdFromRune(ctx context.Context, rune runes.Rune) (runes.RuneId, error)
 	// GetRuneEntryByRuneId returns the RuneEntry for the given runeId. Returns errs.NotFound if the rune entry is not found.
@@ -43,32 +49,54 @@ type RunesReaderDataGateway interface {
 	GetRuneEntryByRuneIdAndHeight(ctx context.Context, runeId runes.RuneId, blockHeight uint64) (*runes.RuneEntry, error)
 	// GetRuneEntryByRuneIdAndHeightBatch returns the RuneEntries for the given runeIds and block height.
 	GetRuneEntryByRuneIdAndHeightBatch(ctx context.Context, runeIds []runes.RuneId, blockHeight uint64) (map[runes.RuneId]*runes.RuneEntry, error)
+	// GetRuneEntryStateHistory returns the state of the rune entry every interval blocks from fromBlock to toBlock. Points before the rune is etched are skipped, but count towards limit and offset.
+	// Use limit = -1 as no limit.
+	GetRuneEntryStateHistory(ctx context.Context, runeId runes.RuneId, fromBlock, toBlock uint64, interval uint64, limit int32, offset int32) ([]*entity.RuneEntryState, error)
 	// GetRuneEntries returns a list of rune entries, sorted by etching order. If search is not empty, it will filter the results by rune name (prefix).
-	GetRuneEntries(ctx context.Context, search string, blockHeight uint64, limit int32, offset int32) ([]*runes.RuneEntry, error)
+	GetRuneEntries(ctx context.Context, search string, blockHeight uint64, cursor *entity.RuneEntryCursor, limit int32, offset int32) ([]*runes.RuneEntry, error)
 	// GetOngoingRuneEntries returns a list of ongoing rune entries (can still mint), sorted by mint progress percent. If search is not empty, it will filter the results by rune name (prefix).
-	GetOngoingRuneEntries(ctx context.Context, search string, blockHeight uint64, limit int32, offset int32) ([]*runes.RuneEntry, error)
+	GetOngoingRuneEntries(ctx context.Context, search string, blockHeight uint64, cursor *entity.RuneEntryCursor, limit int32, offset int32) ([]*runes.RuneEntry, error)
 	// CountRuneEntries returns the number of existing rune entries.
 	CountRuneEntries(ctx context.Context) (uint64, error)
 
 	// GetBalancesByPkScript returns the balances for the given pkScript at the given blockHeight.
 	// Use limit = -1 as no limit.
 	GetBalancesByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error)
+	// GetBalanceHistoryByPkScript returns the balance changes of the given pkScript from fromBlock to toBlock, in ascending block height.
+	// Changes are grouped into intervals of interval blocks, starting from fromBlock, and only the last change of each interval is returned. If runeId is zero value, changes of all runes are returned.
+	// Use limit = -1 as no limit.
+	GetBalanceHistoryByPkScript(ctx context.Context, pkScript []byte, runeId runes.RuneId, fromBlock, toBlock uint64, interval uint64, limit int32, offset int32) ([]*entity.BalanceChange, error)
+	// GetCurrentBalancesByPkScript returns the balances for the given pkScript at blockHeight from the current balances, which is cheaper than GetBalancesByPkScript.
+	// The current balances only reflect the latest indexed block, so it returns errs.InvalidState if blockHeight is not the latest indexed block height.
+	// Use limit = -1 as no limit.
+	GetCurrentBalancesByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error)
 	// GetBalancesByRuneId returns the balances for the given runeId at the given blockHeight.
 	// Cannot use []byte as map key, so we're returning as slice.
 	// Use limit = -1 as no limit.
-	GetBalancesByRuneId(ctx context.Context, runeId runes.RuneId, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error)
+	GetBalancesByRuneId(ctx context.Context, runeId runes.RuneId, blockHeight uint64, cursor *entity.BalanceCursor, limit int32, offset int32) ([]*entity.Balance, error)
+	// GetHolderChangesByRuneId returns the holders of the given runeId whose balance changed between fromBlock and toBlock, ordered by the absolute change descending.
+	// If changeType is empty, changes of all types are returned.
+	// Use limit = -1 as no limit.
+	GetHolderChangesByRuneId(ctx context.Context, runeId runes.RuneId, fromBlock, toBlock uint64, changeType entity.HolderChangeType, limit int32, offset int32) ([]*entity.HolderChange, error)
 	// GetBalancesByPkScriptAndRuneId returns the balance for the given pkScript and runeId at the given blockHeight.
 	GetBalanceByPkScriptAndRuneId(ctx context.Context, pkScript []byte, runeId runes.RuneId, blockHeight uint64) (*entity.Balance, error)
 	// GetTotalHoldersByRuneIds returns the total holders of each the given runeIds.
 	GetTotalHoldersByRuneIds(ctx context.Context, runeIds []runes.RuneId, blockHeight uint64) (map[runes.RuneId]int64, error)
+	// GetPrunedHeight returns the lowest block height that can still be queried. Returns 0 if the data has never been pruned.
+	GetPrunedHeight(ctx context.Context) (uint64, error)
+	// CountRevertRowsSinceHeight returns the number of rows that would be deleted or updated by reverting the data since the given height.
+	CountRevertRowsSinceHeight(ctx context.Context, height uint64) (*entity.RevertCounts, error)
 }
 
 type RunesWriterDataGateway interface {
 	CreateRuneEntries(ctx context.Context, entries []*runes.RuneEntry) error
 	CreateRuneEntryStates(ctx context.Context, entries []*runes.RuneEntry, blockHeight uint64) error
 	CreateOutPointBalances(ctx context.Context, outPointBalances []*entity.OutPointBalance) error
-	SpendOutPointBalancesBatch(ctx context.Context, outPoints []wire.OutPoint, blockHeight uint64) error
+	// SpendOutPointBalancesBatch marks the outpoints as spent at the given block height by the given transaction inputs.
+	SpendOutPointBalancesBatch(ctx context.Context, spentOutPoints []*entity.SpentOutPoint, blockHeight uint64) error
 	CreateRuneBalances(ctx context.Context, params []*entity.Balance) error
+	// UpdateCurrentBalances sets the current balances to the given balances. Zero balances are removed.
+	UpdateCurrentBalances(ctx context.Context, balances []*entity.Balance) error
 	CreateRuneTransactions(ctx context.Context, txs []*entity.RuneTransaction) error
 	CreateIndexedBlock(ctx context.Context, block *entity.IndexedBlock) error
 
@@ -81,4 +109,16 @@ type RunesWriterDataGateway interface {
 	DeleteOutPointBalancesSinceHeight(ctx context.Context, height uint64) error
 	UnspendOutPointBalancesSinceHeight(ctx context.Context, height uint64) error
 	DeleteRuneBalancesSinceHeight(ctx context.Context, height uint64) error
+	// RevertCurrentBalancesSinceHeight restores the current balances to the state before the given height. Must be called before DeleteRuneBalancesSinceHeight.
+	RevertCurrentBalancesSinceHeight(ctx context.Context, height uint64) error
+
+	// PruneSpentOutPointBalances deletes outpoint balances spent before the given height. Returns the number of deleted rows.
+	PruneSpentOutPointBalances(ctx context.Context, prunedHeight uint64) (int64, error)
+	// PruneRuneBalances deletes balances before the given height that are superseded by a newer balance at or before the given height. Returns the number of deleted rows.
+	PruneRuneBalances(ctx context.Context, prunedHeight uint64) (int64, error)
+	SetPrunedHeight(ctx context.Context, prunedHeight uint64) error
+
+	// CreatePartitions creates the block height partitions of the partitioned tables covering the given range if they don't exist.
+	// Returns the exclusive upper bound height of the created partitions.
+	CreatePartitions(ctx context.Context, fromHeight, toHeight uint64) (uint64, error)
 }
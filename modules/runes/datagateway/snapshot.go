@@ -0,0 +1,32 @@
+package datagateway
+
+import (
+	"context"
+
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+)
+
+// SnapshotReaderDataGatewayWithTx reads from a single consistent snapshot of the database.
+type SnapshotReaderDataGatewayWithTx interface {
+	RunesReaderDataGateway
+	IntegrityDataGateway
+	SnapshotDataGateway
+	Tx
+}
+
+// SnapshotDataGateway provides the queries used to export the runes state at a block height.
+type SnapshotDataGateway interface {
+	// BeginSnapshotReaderTx begins a read-only transaction, in which all reads see the same snapshot of the database
+	// even if blocks are indexed or reverted concurrently. The transaction must be rolled back after use.
+	BeginSnapshotReaderTx(ctx context.Context) (SnapshotReaderDataGatewayWithTx, error)
+	// GetSnapshotRuneEntries returns up to limit rune entries etched at or before blockHeight with their state at blockHeight, sorted by number.
+	// Only entries with a number greater than afterNumber are returned. Use afterNumber = -1 to start from the first entry.
+	GetSnapshotRuneEntries(ctx context.Context, blockHeight uint64, afterNumber int64, limit int32) ([]*runes.RuneEntry, error)
+	// GetSnapshotOutPointBalances returns up to limit outpoint balances unspent at blockHeight, sorted by outpoint and rune id.
+	// Only outpoint balances after the given outpoint balance are returned. Use after = nil to start from the first outpoint balance.
+	GetSnapshotOutPointBalances(ctx context.Context, blockHeight uint64, after *entity.OutPointBalance, limit int32) ([]*entity.OutPointBalance, error)
+	// GetSnapshotBalances returns up to limit latest balances at blockHeight, sorted by pkscript and rune id.
+	// Only balances after the given balance are returned. Use after = nil to start from the first balance.
+	GetSnapshotBalances(ctx context.Context, blockHeight uint64, after *entity.Balance, limit int32) ([]*entity.Balance, error)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
 	"github.com/gaze-network/indexer-network/core/types"
 	"github.com/gaze-network/indexer-network/modules/runes/constants"
 	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
@@ -18,24 +19,60 @@ import (
 	"github.com/samber/lo"
 )
 
-// TODO: implement test to ensure that the event hash is calculated the same way for same version
+// eventHashInputs are the changes of a single block that its event hash commits to.
+type eventHashInputs struct {
+	newRuneEntries      []*runes.RuneEntry
+	newRuneEntryStates  []*runes.RuneEntry
+	newOutPointBalances map[wire.OutPoint][]*entity.OutPointBalance
+	spendOutPoints      []wire.OutPoint
+	newBalances         map[string]map[runes.RuneId]uint128.Uint128
+	newRuneTxs          []*entity.RuneTransaction
+}
+
+// hashPayloadFunc serializes the changes of a block into the payload its event hash is calculated from.
+type hashPayloadFunc func(blockHash chainhash.Hash, inputs *eventHashInputs) ([]byte, error)
+
+// hashPayloadFuncs are the serializations of every supported event hash version.
+// The serialization of a version must never change, otherwise the event hashes of databases indexed with it can't be verified.
+var hashPayloadFuncs = map[int32]hashPayloadFunc{
+	1: getHashPayloadV1,
+}
+
 func (p *Processor) calculateEventHash(header types.BlockHeader) (chainhash.Hash, error) {
-	payload, err := p.getHashPayload(header)
+	return calculateEventHash(constants.EventHashVersion, header.Hash, &eventHashInputs{
+		newRuneEntries:      lo.Values(p.newRuneEntries),
+		newRuneEntryStates:  lo.Values(p.newRuneEntryStates),
+		newOutPointBalances: p.newOutPointBalances,
+		spendOutPoints: lo.Map(p.newSpendOutPoints, func(spentOutPoint *entity.SpentOutPoint, _ int) wire.OutPoint {
+			return spentOutPoint.OutPoint
+		}),
+		newBalances: p.newBalances,
+		newRuneTxs:  p.newRuneTxs,
+	})
+}
+
+// calculateEventHash calculates the event hash of a block with the serialization of the given event hash version.
+func calculateEventHash(version int32, blockHash chainhash.Hash, inputs *eventHashInputs) (chainhash.Hash, error) {
+	getHashPayload, ok := hashPayloadFuncs[version]
+	if !ok {
+		return chainhash.Hash{}, errors.Wrapf(errs.Unsupported, "unsupported event hash version %d", version)
+	}
+	payload, err := getHashPayload(blockHash, inputs)
 	if err != nil {
 		return chainhash.Hash{}, errors.Wrap(err, "failed to get hash payload")
 	}
 	return chainhash.DoubleHashH(payload), nil
 }
 
-func (p *Processor) getHashPayload(header types.BlockHeader) ([]byte, error) {
+func getHashPayloadV1(blockHash chainhash.Hash, inputs *eventHashInputs) ([]byte, error) {
 	var sb strings.Builder
-	sb.WriteString("payload:v" + strconv.Itoa(constants.EventHashVersion) + ":")
+	sb.WriteString("payload:v1:")
 	sb.WriteString("blockHash:")
-	sb.Write(header.Hash[:])
+	sb.Write(blockHash[:])
 
 	// serialize new rune entries
 	{
-		runeEntries := lo.Values(p.newRuneEntries)
+		runeEntries := slices.Clone(inputs.newRuneEntries)
 		slices.SortFunc(runeEntries, func(t1, t2 *runes.RuneEntry) int {
 			return int(t1.Number) - int(t2.Number)
 		})
@@ -45,23 +82,23 @@ func (p *Processor) getHashPayload(header types.BlockHeader) ([]byte, error) {
 	}
 	// serialize new rune entry states
 	{
-		runeIds := lo.Keys(p.newRuneEntryStates)
-		slices.SortFunc(runeIds, func(t1, t2 runes.RuneId) int {
-			return t1.Cmp(t2)
+		runeEntryStates := slices.Clone(inputs.newRuneEntryStates)
+		slices.SortFunc(runeEntryStates, func(t1, t2 *runes.RuneEntry) int {
+			return t1.RuneId.Cmp(t2.RuneId)
 		})
-		for _, runeId := range runeIds {
-			sb.Write(serializeNewRuneEntryState(p.newRuneEntryStates[runeId]))
+		for _, entry := range runeEntryStates {
+			sb.Write(serializeNewRuneEntryState(entry))
 		}
 	}
 	// serialize new out point balances
-	sb.Write(serializeNewOutPointBalances(p.newOutPointBalances))
+	sb.Write(serializeNewOutPointBalances(inputs.newOutPointBalances))
 
 	// serialize spend out points
-	sb.Write(serializeSpendOutPoints(p.newSpendOutPoints))
+	sb.Write(serializeSpendOutPoints(inputs.spendOutPoints))
 
 	// serialize new balances
 	{
-		bytes, err := serializeNewBalances(p.newBalances)
+		bytes, err := serializeNewBalances(inputs.newBalances)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to serialize new balances")
 		}
@@ -71,7 +108,7 @@ func (p *Processor) getHashPayload(header types.BlockHeader) ([]byte, error) {
 	// serialize new txs
 	// sort txs by block height and index
 	{
-		bytes, err := serializeRuneTxs(p.newRuneTxs)
+		bytes, err := serializeRuneTxs(inputs.newRuneTxs)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to serialize new rune txs")
 		}
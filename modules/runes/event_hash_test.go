@@ -0,0 +1,212 @@
+package runes
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/core/types"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+// eventHashTestBlockHash is the hash of the block of newEventHashTestData.
+var eventHashTestBlockHash = chainhash.DoubleHashH([]byte("block"))
+
+// newEventHashTestData returns the persisted data of a block with an etching, a transfer of two runes and a cenotaph burning a rune.
+// Transactions burn a single rune, because burns of multiple runes are serialized in map iteration order,
+// and the runes are etched in different blocks, because RuneId.Cmp doesn't order rune ids of the same block consistently.
+func newEventHashTestData() *entity.BlockEventData {
+	const blockHeight = 840010
+	rune1 := runes.RuneId{BlockHeight: 840000, TxIndex: 1}
+	rune2 := runes.RuneId{BlockHeight: 840001, TxIndex: 1}
+	rune3 := runes.RuneId{BlockHeight: blockHeight, TxIndex: 0}
+	etchingTxHash := chainhash.DoubleHashH([]byte("etching"))
+	transferTxHash := chainhash.DoubleHashH([]byte("transfer"))
+	cenotaphTxHash := chainhash.DoubleHashH([]byte("cenotaph"))
+	prevTxHash := chainhash.DoubleHashH([]byte("prev"))
+	pkScript1 := []byte{0x51, 0x01}
+	pkScript2 := []byte{0x51, 0x02}
+	etchedAt := time.Unix(1713571767, 0)
+	newEntry := &runes.RuneEntry{
+		RuneId:        rune3,
+		Number:        3,
+		Divisibility:  2,
+		Premine:       uint128.From64(1000),
+		SpacedRune:    runes.NewSpacedRune(runes.NewRune(123456789), 0b101),
+		Symbol:        '$',
+		Terms:         &runes.Terms{Amount: lo.ToPtr(uint128.From64(10)), Cap: lo.ToPtr(uint128.From64(100))},
+		EtchingBlock:  blockHeight,
+		EtchingTxHash: etchingTxHash,
+		EtchedAt:      etchedAt,
+	}
+
+	return &entity.BlockEventData{
+		NewRuneEntries: []*runes.RuneEntry{newEntry},
+		NewRuneEntryStates: []*runes.RuneEntry{
+			newEntry,
+			{RuneId: rune1, Mints: uint128.From64(5), BurnedAmount: uint128.From64(7)},
+			{RuneId: rune2, Mints: uint128.From64(1), BurnedAmount: uint128.Zero},
+		},
+		NewOutPointBalances: []*entity.OutPointBalance{
+			{RuneId: rune3, PkScript: pkScript1, OutPoint: wire.OutPoint{Hash: etchingTxHash, Index: 1}, Amount: uint128.From64(1000), BlockHeight: blockHeight},
+			{RuneId: rune1, PkScript: pkScript2, OutPoint: wire.OutPoint{Hash: transferTxHash, Index: 0}, Amount: uint128.From64(4), BlockHeight: blockHeight},
+			{RuneId: rune2, PkScript: pkScript2, OutPoint: wire.OutPoint{Hash: transferTxHash, Index: 0}, Amount: uint128.From64(6), BlockHeight: blockHeight},
+		},
+		SpentOutPointBalances: []*entity.OutPointBalance{
+			{RuneId: rune1, PkScript: pkScript1, OutPoint: wire.OutPoint{Hash: prevTxHash, Index: 0}, Amount: uint128.From64(4), BlockHeight: 840005},
+			{RuneId: rune2, PkScript: pkScript1, OutPoint: wire.OutPoint{Hash: prevTxHash, Index: 0}, Amount: uint128.From64(6), BlockHeight: 840005},
+			{RuneId: rune1, PkScript: pkScript1, OutPoint: wire.OutPoint{Hash: prevTxHash, Index: 1}, Amount: uint128.From64(7), BlockHeight: 840005},
+		},
+		NewBalances: []*entity.Balance{
+			{PkScript: pkScript1, RuneId: rune1, Amount: uint128.Zero, BlockHeight: blockHeight},
+			{PkScript: pkScript1, RuneId: rune2, Amount: uint128.Zero, BlockHeight: blockHeight},
+			{PkScript: pkScript1, RuneId: rune3, Amount: uint128.From64(1000), BlockHeight: blockHeight},
+			{PkScript: pkScript2, RuneId: rune1, Amount: uint128.From64(4), BlockHeight: blockHeight},
+			{PkScript: pkScript2, RuneId: rune2, Amount: uint128.From64(6), BlockHeight: blockHeight},
+		},
+		RuneTransactions: []*entity.RuneTransaction{
+			{
+				Hash:        etchingTxHash,
+				BlockHeight: blockHeight,
+				Index:       0,
+				Timestamp:   etchedAt,
+				Outputs: []*entity.TxInputOutput{
+					{PkScript: pkScript1, RuneId: rune3, Amount: uint128.From64(1000), Index: 1, TxHash: etchingTxHash, TxOutIndex: 1},
+				},
+				Mints:      map[runes.RuneId]uint128.Uint128{},
+				Burns:      map[runes.RuneId]uint128.Uint128{},
+				Runestone:  &runes.Runestone{Etching: &runes.Etching{Premine: lo.ToPtr(uint128.From64(1000))}},
+				RuneEtched: true,
+			},
+			{
+				Hash:        transferTxHash,
+				BlockHeight: blockHeight,
+				Index:       1,
+				Timestamp:   etchedAt,
+				Inputs: []*entity.TxInputOutput{
+					{PkScript: pkScript1, RuneId: rune2, Amount: uint128.From64(6), Index: 0, TxHash: prevTxHash, TxOutIndex: 0},
+					{PkScript: pkScript1, RuneId: rune1, Amount: uint128.From64(4), Index: 0, TxHash: prevTxHash, TxOutIndex: 0},
+				},
+				Outputs: []*entity.TxInputOutput{
+					{PkScript: pkScript2, RuneId: rune2, Amount: uint128.From64(6), Index: 0, TxHash: transferTxHash, TxOutIndex: 0},
+					{PkScript: pkScript2, RuneId: rune1, Amount: uint128.From64(4), Index: 0, TxHash: transferTxHash, TxOutIndex: 0},
+				},
+				Mints: map[runes.RuneId]uint128.Uint128{},
+				Burns: map[runes.RuneId]uint128.Uint128{},
+			},
+			{
+				Hash:        cenotaphTxHash,
+				BlockHeight: blockHeight,
+				Index:       2,
+				Timestamp:   etchedAt,
+				Inputs: []*entity.TxInputOutput{
+					{PkScript: pkScript1, RuneId: rune1, Amount: uint128.From64(7), Index: 0, TxHash: prevTxHash, TxOutIndex: 1},
+				},
+				Mints: map[runes.RuneId]uint128.Uint128{},
+				Burns: map[runes.RuneId]uint128.Uint128{
+					rune1: uint128.From64(7),
+				},
+				Runestone: &runes.Runestone{Cenotaph: true, Flaws: 1},
+			},
+		},
+	}
+}
+
+// shuffleEventHashTestData shuffles every collection of the data that is sorted before it is serialized.
+// Outputs of transactions are serialized in the order they are stored.
+func shuffleEventHashTestData(r *rand.Rand, data *entity.BlockEventData) {
+	shuffle := func(n int, swap func(i, j int)) {
+		r.Shuffle(n, swap)
+	}
+	shuffle(len(data.NewRuneEntryStates), func(i, j int) {
+		data.NewRuneEntryStates[i], data.NewRuneEntryStates[j] = data.NewRuneEntryStates[j], data.NewRuneEntryStates[i]
+	})
+	shuffle(len(data.NewOutPointBalances), func(i, j int) {
+		data.NewOutPointBalances[i], data.NewOutPointBalances[j] = data.NewOutPointBalances[j], data.NewOutPointBalances[i]
+	})
+	shuffle(len(data.SpentOutPointBalances), func(i, j int) {
+		data.SpentOutPointBalances[i], data.SpentOutPointBalances[j] = data.SpentOutPointBalances[j], data.SpentOutPointBalances[i]
+	})
+	shuffle(len(data.RuneTransactions), func(i, j int) {
+		data.RuneTransactions[i], data.RuneTransactions[j] = data.RuneTransactions[j], data.RuneTransactions[i]
+	})
+	for _, tx := range data.RuneTransactions {
+		shuffle(len(tx.Inputs), func(i, j int) { tx.Inputs[i], tx.Inputs[j] = tx.Inputs[j], tx.Inputs[i] })
+	}
+}
+
+// TestEventHashIsStable ensures that the event hash is calculated the same way for the same version.
+// If this test fails, the serialization of an existing version has changed, and databases indexed with it can't be verified anymore.
+func TestEventHashIsStable(t *testing.T) {
+	eventHash, err := calculateEventHash(constants.EventHashVersion, eventHashTestBlockHash, newEventHashInputs(newEventHashTestData()))
+	assert.NoError(t, err)
+	assert.Equal(t, "bdf870809a1f2e638dc77643c89f9a2bcb42778a57adba870194a31f36c59613", eventHash.String())
+}
+
+func TestEventHashUnsupportedVersion(t *testing.T) {
+	_, err := calculateEventHash(0, eventHashTestBlockHash, newEventHashInputs(newEventHashTestData()))
+	assert.ErrorIs(t, err, errs.Unsupported)
+}
+
+func TestEventHashIsDeterministic(t *testing.T) {
+	expected, err := calculateEventHash(constants.EventHashVersion, eventHashTestBlockHash, newEventHashInputs(newEventHashTestData()))
+	assert.NoError(t, err)
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		data := newEventHashTestData()
+		shuffleEventHashTestData(r, data)
+		eventHash, err := calculateEventHash(constants.EventHashVersion, eventHashTestBlockHash, newEventHashInputs(data))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, eventHash, "event hash changed after shuffling, iteration %d", i)
+	}
+}
+
+func TestRecomputeEventHash(t *testing.T) {
+	// the processor stages the changes of a block in memory
+	data := newEventHashTestData()
+	p := &Processor{
+		newRuneEntries:      make(map[runes.RuneId]*runes.RuneEntry),
+		newRuneEntryStates:  make(map[runes.RuneId]*runes.RuneEntry),
+		newOutPointBalances: make(map[wire.OutPoint][]*entity.OutPointBalance),
+		newBalances:         make(map[string]map[runes.RuneId]uint128.Uint128),
+		newRuneTxs:          data.RuneTransactions,
+	}
+	for _, entry := range data.NewRuneEntries {
+		p.newRuneEntries[entry.RuneId] = entry
+	}
+	for _, entry := range data.NewRuneEntryStates {
+		p.newRuneEntryStates[entry.RuneId] = entry
+	}
+	for _, balance := range data.NewOutPointBalances {
+		p.newOutPointBalances[balance.OutPoint] = append(p.newOutPointBalances[balance.OutPoint], balance)
+	}
+	for _, balance := range data.SpentOutPointBalances {
+		p.newSpendOutPoints = append(p.newSpendOutPoints, &entity.SpentOutPoint{OutPoint: balance.OutPoint, BlockHeight: balance.BlockHeight})
+	}
+	for _, balance := range data.NewBalances {
+		pkScriptStr := hex.EncodeToString(balance.PkScript)
+		if _, ok := p.newBalances[pkScriptStr]; !ok {
+			p.newBalances[pkScriptStr] = make(map[runes.RuneId]uint128.Uint128)
+		}
+		p.newBalances[pkScriptStr][balance.RuneId] = balance.Amount
+	}
+	eventHash, err := p.calculateEventHash(types.BlockHeader{Hash: eventHashTestBlockHash})
+	assert.NoError(t, err)
+
+	// the verifier recomputes the event hash from the persisted data, which may be loaded in another order
+	persisted := newEventHashTestData()
+	shuffleEventHashTestData(rand.New(rand.NewSource(2)), persisted)
+	recomputed, err := calculateEventHash(constants.EventHashVersion, eventHashTestBlockHash, newEventHashInputs(persisted))
+	assert.NoError(t, err)
+	assert.Equal(t, eventHash, recomputed)
+}
@@ -0,0 +1,162 @@
+package runes
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/samber/lo"
+)
+
+// VerifyEventHashes recomputes the event hash of every indexed block in the given height range (inclusive) from the persisted data,
+// and verifies it and the cumulative event hash against the stored values.
+// Use toHeight = -1 to verify up to the latest indexed block height. Pruned heights cannot be verified.
+func (c *IntegrityChecker) VerifyEventHashes(ctx context.Context, fromHeight, toHeight int64) ([]IntegrityViolation, error) {
+	latestBlock, err := c.runesDg.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest block")
+	}
+	if toHeight == -1 {
+		toHeight = latestBlock.Height
+	}
+	if toHeight > latestBlock.Height {
+		return nil, errors.Wrapf(errs.InvalidArgument, "to height %d is higher than the latest indexed block height %d", toHeight, latestBlock.Height)
+	}
+	if fromHeight > toHeight {
+		return nil, errors.Wrapf(errs.InvalidArgument, "from height %d is higher than to height %d", fromHeight, toHeight)
+	}
+	startingBlockHeader := constants.StartingBlockHeader[c.network]
+	if fromHeight <= startingBlockHeader.Height {
+		return nil, errors.Wrapf(errs.InvalidArgument, "from height %d must be higher than the starting block height %d", fromHeight, startingBlockHeader.Height)
+	}
+	prunedHeight, err := c.runesDg.GetPrunedHeight(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pruned height")
+	}
+	if fromHeight < int64(prunedHeight) {
+		return nil, errors.Wrapf(errs.InvalidArgument, "from height %d is pruned, earliest verifiable block height is %d", fromHeight, prunedHeight)
+	}
+	// event hashes are recomputed with the serialization of the event hash version the data was indexed with
+	indexerState, err := c.integrityDg.GetLatestIndexerState(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest indexer state")
+	}
+	if _, ok := hashPayloadFuncs[indexerState.EventHashVersion]; !ok {
+		return nil, errors.Wrapf(errs.Unsupported, "event hashes of event hash version %d can't be verified", indexerState.EventHashVersion)
+	}
+
+	// the first indexed block has no previous indexed block, its cumulative event hash is chained from an empty hash
+	var prevCumulativeEventHash chainhash.Hash
+	if fromHeight-1 != startingBlockHeader.Height {
+		prevIndexedBlock, err := c.runesDg.GetIndexedBlockByHeight(ctx, fromHeight-1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get indexed block at height %d", fromHeight-1)
+		}
+		prevCumulativeEventHash = prevIndexedBlock.CumulativeEventHash
+	}
+
+	violations := make([]IntegrityViolation, 0)
+	expectedHeight := fromHeight
+	for pageFromHeight := fromHeight; pageFromHeight <= toHeight; pageFromHeight += indexedBlocksPageSize {
+		pageToHeight := min(pageFromHeight+indexedBlocksPageSize-1, toHeight)
+		indexedBlocks, err := c.integrityDg.GetIndexedBlocksInRange(ctx, pageFromHeight, pageToHeight)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get indexed blocks")
+		}
+		for _, indexedBlock := range indexedBlocks {
+			if indexedBlock.Height != expectedHeight {
+				violations = append(violations, IntegrityViolation{
+					Check:   IntegrityCheckIndexedBlocks,
+					Details: fmt.Sprintf("missing indexed blocks from height %d to %d", expectedHeight, indexedBlock.Height-1),
+				})
+			}
+			blockViolations, err := c.verifyEventHash(ctx, indexerState.EventHashVersion, indexedBlock, prevCumulativeEventHash, indexedBlock.Height == expectedHeight)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			violations = append(violations, blockViolations...)
+
+			// chain from the stored hash, so that a single corrupted block isn't reported for every following block
+			prevCumulativeEventHash = indexedBlock.CumulativeEventHash
+			expectedHeight = indexedBlock.Height + 1
+		}
+	}
+	if expectedHeight <= toHeight {
+		violations = append(violations, IntegrityViolation{
+			Check:   IntegrityCheckIndexedBlocks,
+			Details: fmt.Sprintf("missing indexed blocks from height %d to %d", expectedHeight, toHeight),
+		})
+	}
+	return violations, nil
+}
+
+// verifyEventHash recomputes the event hash of the indexed block from the persisted data and compares it with the stored hashes.
+// The cumulative event hash is verified only if prevCumulativeEventHash belongs to the previous block.
+func (c *IntegrityChecker) verifyEventHash(ctx context.Context, eventHashVersion int32, indexedBlock *entity.IndexedBlock, prevCumulativeEventHash chainhash.Hash, verifyCumulative bool) ([]IntegrityViolation, error) {
+	data, err := c.integrityDg.GetBlockEventData(ctx, uint64(indexedBlock.Height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get event data of block %d", indexedBlock.Height)
+	}
+	eventHash, err := calculateEventHash(eventHashVersion, indexedBlock.Hash, newEventHashInputs(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to calculate event hash of block %d", indexedBlock.Height)
+	}
+
+	violations := make([]IntegrityViolation, 0)
+	if indexedBlock.EventHash != eventHash {
+		details := fmt.Sprintf("block %d: event hash is %s, but recomputed event hash is %s", indexedBlock.Height, indexedBlock.EventHash, eventHash)
+		// burns are serialized in map iteration order, so the event hash of a transaction burning multiple runes can't be reproduced reliably
+		if tx, ok := lo.Find(data.RuneTransactions, func(tx *entity.RuneTransaction) bool { return len(tx.Burns) > 1 }); ok {
+			details += fmt.Sprintf(" (may be a false positive: transaction %s burns multiple runes)", tx.Hash)
+		}
+		violations = append(violations, IntegrityViolation{
+			Check:   IntegrityCheckEventHashes,
+			Details: details,
+		})
+	}
+	if verifyCumulative {
+		expectedCumulativeEventHash := chainhash.DoubleHashH(append(prevCumulativeEventHash[:], eventHash[:]...))
+		if indexedBlock.CumulativeEventHash != expectedCumulativeEventHash {
+			violations = append(violations, IntegrityViolation{
+				Check:   IntegrityCheckEventHashes,
+				Details: fmt.Sprintf("block %d: cumulative event hash is %s, but recomputed cumulative event hash is %s", indexedBlock.Height, indexedBlock.CumulativeEventHash, expectedCumulativeEventHash),
+			})
+		}
+	}
+	return violations, nil
+}
+
+// newEventHashInputs reconstructs the in-memory changes of a block, as staged by the Processor, from the persisted data.
+func newEventHashInputs(data *entity.BlockEventData) *eventHashInputs {
+	newOutPointBalances := make(map[wire.OutPoint][]*entity.OutPointBalance)
+	for _, balance := range data.NewOutPointBalances {
+		newOutPointBalances[balance.OutPoint] = append(newOutPointBalances[balance.OutPoint], balance)
+	}
+	newBalances := make(map[string]map[runes.RuneId]uint128.Uint128)
+	for _, balance := range data.NewBalances {
+		pkScriptStr := hex.EncodeToString(balance.PkScript)
+		if _, ok := newBalances[pkScriptStr]; !ok {
+			newBalances[pkScriptStr] = make(map[runes.RuneId]uint128.Uint128)
+		}
+		newBalances[pkScriptStr][balance.RuneId] = balance.Amount
+	}
+	return &eventHashInputs{
+		newRuneEntries:      data.NewRuneEntries,
+		newRuneEntryStates:  data.NewRuneEntryStates,
+		newOutPointBalances: newOutPointBalances,
+		// the processor stages a spent outpoint once for every rune it holds
+		spendOutPoints: lo.Map(data.SpentOutPointBalances, func(balance *entity.OutPointBalance, _ int) wire.OutPoint {
+			return balance.OutPoint
+		}),
+		newBalances: newBalances,
+		newRuneTxs:  data.RuneTransactions,
+	}
+}
@@ -0,0 +1,171 @@
+package runes
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
+)
+
+type IntegrityCheck string
+
+const (
+	IntegrityCheckBalances      IntegrityCheck = "balances"
+	IntegrityCheckSupply        IntegrityCheck = "supply"
+	IntegrityCheckIndexedBlocks IntegrityCheck = "indexed_blocks"
+	IntegrityCheckEventHashes   IntegrityCheck = "event_hashes"
+)
+
+// IntegrityViolation is a violated invariant found by the IntegrityChecker.
+type IntegrityViolation struct {
+	Check   IntegrityCheck
+	Details string
+}
+
+func (v IntegrityViolation) String() string {
+	return fmt.Sprintf("[%s] %s", v.Check, v.Details)
+}
+
+// indexedBlocksPageSize is the number of indexed blocks to load at a time when verifying indexed blocks.
+const indexedBlocksPageSize = 10000
+
+// IntegrityChecker verifies the internal consistency of the runes data at a given block height.
+type IntegrityChecker struct {
+	runesDg     datagateway.RunesReaderDataGateway
+	integrityDg datagateway.IntegrityDataGateway
+	network     common.Network
+}
+
+func NewIntegrityChecker(runesDg datagateway.RunesReaderDataGateway, integrityDg datagateway.IntegrityDataGateway, network common.Network) *IntegrityChecker {
+	return &IntegrityChecker{
+		runesDg:     runesDg,
+		integrityDg: integrityDg,
+		network:     network,
+	}
+}
+
+// Check verifies all invariants at the given block height and returns every violation found.
+// Use blockHeight = -1 to check at the latest indexed block height.
+func (c *IntegrityChecker) Check(ctx context.Context, blockHeight int64) ([]IntegrityViolation, error) {
+	latestBlock, err := c.runesDg.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest block")
+	}
+	if blockHeight == -1 {
+		blockHeight = latestBlock.Height
+	}
+	if blockHeight > latestBlock.Height {
+		return nil, errors.Wrapf(errs.InvalidArgument, "block height %d is higher than the latest indexed block height %d", blockHeight, latestBlock.Height)
+	}
+	prunedHeight, err := c.runesDg.GetPrunedHeight(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pruned height")
+	}
+	if blockHeight < int64(prunedHeight) {
+		return nil, errors.Wrapf(errs.InvalidArgument, "block height %d is pruned, earliest available block height is %d", blockHeight, prunedHeight)
+	}
+
+	violations := make([]IntegrityViolation, 0)
+	for _, check := range []func(context.Context, int64) ([]IntegrityViolation, error){
+		c.checkBalances,
+		c.checkSupply,
+		c.checkIndexedBlocks,
+	} {
+		checkViolations, err := check(ctx, blockHeight)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		violations = append(violations, checkViolations...)
+	}
+	return violations, nil
+}
+
+// checkBalances verifies that the sum of unspent outpoint balances of each pkscript and rune equals its balance.
+func (c *IntegrityChecker) checkBalances(ctx context.Context, blockHeight int64) ([]IntegrityViolation, error) {
+	mismatches, err := c.integrityDg.GetBalanceMismatches(ctx, uint64(blockHeight))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get balance mismatches")
+	}
+	violations := make([]IntegrityViolation, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		violations = append(violations, IntegrityViolation{
+			Check: IntegrityCheckBalances,
+			Details: fmt.Sprintf("pkscript %s rune %s: unspent outpoint balances sum to %s, but balance is %s",
+				hex.EncodeToString(mismatch.PkScript), mismatch.RuneId, mismatch.OutPointAmount, mismatch.BalanceAmount),
+		})
+	}
+	return violations, nil
+}
+
+// checkSupply verifies that the circulating supply of each rune equals premine + mints * terms amount - burned amount.
+func (c *IntegrityChecker) checkSupply(ctx context.Context, blockHeight int64) ([]IntegrityViolation, error) {
+	mismatches, err := c.integrityDg.GetSupplyMismatches(ctx, uint64(blockHeight))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get supply mismatches")
+	}
+	violations := make([]IntegrityViolation, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		violations = append(violations, IntegrityViolation{
+			Check: IntegrityCheckSupply,
+			Details: fmt.Sprintf("rune %s: circulating supply is %s, expected premine %s + mints %s * amount %s - burned %s",
+				mismatch.RuneId, mismatch.CirculatingSupply, mismatch.Premine, mismatch.Mints, mismatch.TermsAmount, mismatch.BurnedAmount),
+		})
+	}
+	return violations, nil
+}
+
+// checkIndexedBlocks verifies that indexed blocks are contiguous, linked by their hashes and that cumulative event hashes chain correctly.
+func (c *IntegrityChecker) checkIndexedBlocks(ctx context.Context, blockHeight int64) ([]IntegrityViolation, error) {
+	violations := make([]IntegrityViolation, 0)
+
+	startingBlockHeader := constants.StartingBlockHeader[c.network]
+	expectedHeight := startingBlockHeader.Height + 1
+	var prevHash, prevCumulativeEventHash chainhash.Hash
+	for fromHeight := expectedHeight; fromHeight <= blockHeight; fromHeight += indexedBlocksPageSize {
+		toHeight := min(fromHeight+indexedBlocksPageSize-1, blockHeight)
+		indexedBlocks, err := c.integrityDg.GetIndexedBlocksInRange(ctx, fromHeight, toHeight)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get indexed blocks")
+		}
+		for _, indexedBlock := range indexedBlocks {
+			if indexedBlock.Height != expectedHeight {
+				// hashes cannot be verified across the gap
+				violations = append(violations, IntegrityViolation{
+					Check:   IntegrityCheckIndexedBlocks,
+					Details: fmt.Sprintf("missing indexed blocks from height %d to %d", expectedHeight, indexedBlock.Height-1),
+				})
+			} else {
+				// the first indexed block has no previous indexed block, its cumulative event hash is chained from an empty hash
+				if indexedBlock.Height-1 != startingBlockHeader.Height && indexedBlock.PrevHash != prevHash {
+					violations = append(violations, IntegrityViolation{
+						Check:   IntegrityCheckIndexedBlocks,
+						Details: fmt.Sprintf("block %d: prev hash is %s, but hash of block %d is %s", indexedBlock.Height, indexedBlock.PrevHash, indexedBlock.Height-1, prevHash),
+					})
+				}
+				expectedCumulativeEventHash := chainhash.DoubleHashH(append(prevCumulativeEventHash[:], indexedBlock.EventHash[:]...))
+				if indexedBlock.CumulativeEventHash != expectedCumulativeEventHash {
+					violations = append(violations, IntegrityViolation{
+						Check:   IntegrityCheckEventHashes,
+						Details: fmt.Sprintf("block %d: cumulative event hash is %s, expected %s", indexedBlock.Height, indexedBlock.CumulativeEventHash, expectedCumulativeEventHash),
+					})
+				}
+			}
+			prevHash = indexedBlock.Hash
+			prevCumulativeEventHash = indexedBlock.CumulativeEventHash
+			expectedHeight = indexedBlock.Height + 1
+		}
+	}
+	if expectedHeight <= blockHeight {
+		violations = append(violations, IntegrityViolation{
+			Check:   IntegrityCheckIndexedBlocks,
+			Details: fmt.Sprintf("missing indexed blocks from height %d to %d", expectedHeight, blockHeight),
+		})
+	}
+	return violations, nil
+}
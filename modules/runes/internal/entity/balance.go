@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/gaze-network/indexer-network/modules/runes/runes"
 	"github.com/gaze-network/uint128"
 )
@@ -12,3 +14,50 @@ type Balance struct {
 	// BlockHeight last updated block height
 	BlockHeight uint64
 }
+
+// BalanceChange is the balance of a rune held by a pkscript after it changed at BlockHeight.
+type BalanceChange struct {
+	RuneId      runes.RuneId
+	BlockHeight uint64
+	Timestamp   time.Time // zero if the block time is unknown
+	Amount      uint128.Uint128
+	// PreviousAmount is the balance before the change. If changes are grouped into intervals, it is the balance before the first change of the interval.
+	PreviousAmount uint128.Uint128
+}
+
+type HolderChangeType string
+
+const (
+	HolderChangeTypeAppeared    HolderChangeType = "appeared"    // had no balance at the first height
+	HolderChangeTypeDisappeared HolderChangeType = "disappeared" // has no balance at the second height
+	HolderChangeTypeIncreased   HolderChangeType = "increased"
+	HolderChangeTypeDecreased   HolderChangeType = "decreased"
+)
+
+func (t HolderChangeType) IsValid() bool {
+	switch t {
+	case HolderChangeTypeAppeared, HolderChangeTypeDisappeared, HolderChangeTypeIncreased, HolderChangeTypeDecreased:
+		return true
+	}
+	return false
+}
+
+// HolderChange is the change of the balance of a rune held by a pkscript between two block heights.
+type HolderChange struct {
+	PkScript   []byte
+	FromAmount uint128.Uint128
+	ToAmount   uint128.Uint128
+}
+
+func (c HolderChange) Type() HolderChangeType {
+	switch {
+	case c.FromAmount.IsZero():
+		return HolderChangeTypeAppeared
+	case c.ToAmount.IsZero():
+		return HolderChangeTypeDisappeared
+	case c.ToAmount.Cmp(c.FromAmount) > 0:
+		return HolderChangeTypeIncreased
+	default:
+		return HolderChangeTypeDecreased
+	}
+}
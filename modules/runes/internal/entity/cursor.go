@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"github.com/gaze-network/uint128"
+)
+
+// Cursors are the sort keys of the last item of a page. The next page starts right after the cursor, so pages stay consistent while new blocks are indexed.
+
+// RuneTransactionCursor is the position of a rune transaction in a list sorted by block height and index in descending order.
+type RuneTransactionCursor struct {
+	BlockHeight uint64
+	Index       uint32
+}
+
+// BalanceCursor is the position of a balance in a list sorted by amount in descending order, then pkScript in ascending order.
+type BalanceCursor struct {
+	Amount   uint128.Uint128
+	PkScript []byte
+}
+
+// RuneEntryCursor is the position of a rune entry in a list sorted by rune number,
+// or in a list of ongoing rune entries sorted by mints in descending order, then rune number.
+type RuneEntryCursor struct {
+	Number uint64
+	Mints  uint128.Uint128
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+)
+
+// BalanceMismatch is a pkscript and rune pair whose sum of unspent outpoint balances doesn't match its balance.
+type BalanceMismatch struct {
+	PkScript       []byte
+	RuneId         runes.RuneId
+	OutPointAmount uint128.Uint128
+	BalanceAmount  uint128.Uint128
+}
+
+// SupplyMismatch is a rune whose circulating supply doesn't match premine + mints * terms amount - burned amount.
+type SupplyMismatch struct {
+	RuneId            runes.RuneId
+	Premine           uint128.Uint128
+	TermsAmount       uint128.Uint128
+	Mints             uint128.Uint128
+	BurnedAmount      uint128.Uint128
+	CirculatingSupply uint128.Uint128
+}
+
+// BlockEventData is the runes data persisted for a single block, from which the block's event hash is derived.
+type BlockEventData struct {
+	NewRuneEntries        []*runes.RuneEntry
+	NewRuneEntryStates    []*runes.RuneEntry
+	NewOutPointBalances   []*OutPointBalance
+	SpentOutPointBalances []*OutPointBalance
+	NewBalances           []*Balance
+	RuneTransactions      []*RuneTransaction
+}
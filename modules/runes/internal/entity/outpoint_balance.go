@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/gaze-network/indexer-network/modules/runes/runes"
 	"github.com/gaze-network/uint128"
@@ -13,4 +14,31 @@ type OutPointBalance struct {
 	Amount      uint128.Uint128
 	BlockHeight uint64
 	SpentHeight *uint64
+	// SpentTxHash is the hash of the transaction that spent this outpoint. Nil if unspent.
+	SpentTxHash *chainhash.Hash
+	// SpentTxInputIndex is the input index of the transaction that spent this outpoint. Nil if unspent.
+	SpentTxInputIndex *uint32
+	// Value is the BTC value of the output in sats. Nil for outputs indexed before values were recorded.
+	Value *int64
+}
+
+// SpentOutPoint is an outpoint spent by a transaction input.
+type SpentOutPoint struct {
+	OutPoint    wire.OutPoint
+	BlockHeight uint64         // block height when the outpoint was created
+	TxHash      chainhash.Hash // hash of the spending transaction
+	InputIndex  uint32         // input index of the spending transaction
+}
+
+// OutPointValue is the BTC value of an outpoint.
+type OutPointValue struct {
+	OutPoint    wire.OutPoint
+	BlockHeight uint64 // block height when the outpoint was created
+	Value       int64  // value of the output in sats
+}
+
+// TxAtHeight is a transaction and the block height it was confirmed at.
+type TxAtHeight struct {
+	TxHash      chainhash.Hash
+	BlockHeight uint64
 }
@@ -0,0 +1,15 @@
+package entity
+
+// RevertCounts is the number of rows per table that are deleted or updated when reverting the runes data since a block height.
+type RevertCounts struct {
+	IndexedBlocks         int64
+	RuneEntries           int64
+	RuneEntryStates       int64
+	RuneTransactions      int64
+	RuneTransactionIO     int64
+	Runestones            int64
+	OutPointBalances      int64
+	SpentOutPointBalances int64 // spent outpoint balances that are marked as unspent again
+	Balances              int64
+	CurrentBalances       int64 // current balances that are restored to their value before the height
+}
@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/gaze-network/uint128"
+)
+
+// RuneEntryState is the state of a rune entry at a block height.
+type RuneEntryState struct {
+	BlockHeight  uint64
+	Timestamp    time.Time // timestamp of the latest rune transaction at or before the block height
+	Mints        uint128.Uint128
+	BurnedAmount uint128.Uint128
+	HoldersCount int64
+}
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+)
+
+type ProvenanceOriginType string
+
+const (
+	ProvenanceOriginTypeMint    ProvenanceOriginType = "mint"
+	ProvenanceOriginTypePremine ProvenanceOriginType = "premine"
+)
+
+// ProvenanceOrigin is an amount of runes created in a transaction, by a mint or by the premine of an etching.
+type ProvenanceOrigin struct {
+	Type   ProvenanceOriginType
+	RuneId runes.RuneId
+	Amount uint128.Uint128
+}
+
+// ProvenanceNode is a rune transaction in the lineage of a rune outpoint.
+type ProvenanceNode struct {
+	TxHash      chainhash.Hash
+	BlockHeight uint64
+	Index       uint32
+	// Depth is the number of transfers between the traced outpoint and this transaction. The transaction that created the traced outpoint has depth 0.
+	// A transaction reached through several paths has the depth of the shortest one.
+	Depth int
+	// Inputs are the rune inputs of the traced runes that were followed to their previous transactions, in input order.
+	Inputs []*TxInputOutput
+	// Origins are the traced runes created in this transaction.
+	Origins []*ProvenanceOrigin
+}
+
+type RuneProvenance struct {
+	OutPoint wire.OutPoint
+	// Nodes are sorted by depth, then by block height and index DESC.
+	Nodes []*ProvenanceNode
+	// Truncated is true if the lineage was not fully walked because of the depth or node limit.
+	Truncated bool
+}
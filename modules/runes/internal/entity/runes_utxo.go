@@ -17,6 +17,12 @@ type RunesUTXO struct {
 	RuneBalances []RunesUTXOBalance
 }
 
+// RunesUTXOWithValue is a RunesUTXO with its recorded BTC value in sats. Value is nil for outputs indexed before values were recorded.
+type RunesUTXOWithValue struct {
+	RunesUTXO
+	Value *int64
+}
+
 type RunesUTXOWithSats struct {
 	RunesUTXO
 	Sats int64
@@ -10,6 +10,7 @@ import (
 	"github.com/gaze-network/indexer-network/common/errs"
 	"github.com/gaze-network/indexer-network/core/indexer"
 	"github.com/gaze-network/indexer-network/core/types"
+	"github.com/gaze-network/indexer-network/modules/runes/config"
 	"github.com/gaze-network/indexer-network/modules/runes/constants"
 	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
 	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
@@ -30,28 +31,33 @@ type Processor struct {
 	bitcoinClient   btcclient.Contract
 	network         common.Network
 	reportingClient *reportingclient.ReportingClient
+	pruningConfig   config.PruningConfig
 	cleanupFuncs    []func(context.Context) error
+	prunedHeight    int64 // lowest block height with full history, 0 if never pruned
+
+	partitionEndHeight int64 // exclusive upper bound height of the existing partitions, 0 if unknown
 
 	newRuneEntries      map[runes.RuneId]*runes.RuneEntry
 	newRuneEntryStates  map[runes.RuneId]*runes.RuneEntry
 	newOutPointBalances map[wire.OutPoint][]*entity.OutPointBalance
-	newSpendOutPoints   []wire.OutPoint
+	newSpendOutPoints   []*entity.SpentOutPoint
 	newBalances         map[string]map[runes.RuneId]uint128.Uint128 // pkScript(hex) -> runeId -> amount
 	newRuneTxs          []*entity.RuneTransaction
 }
 
-func NewProcessor(runesDg datagateway.RunesDataGateway, indexerInfoDg datagateway.IndexerInfoDataGateway, bitcoinClient btcclient.Contract, network common.Network, reportingClient *reportingclient.ReportingClient, cleanupFuncs []func(context.Context) error) *Processor {
+func NewProcessor(runesDg datagateway.RunesDataGateway, indexerInfoDg datagateway.IndexerInfoDataGateway, bitcoinClient btcclient.Contract, network common.Network, reportingClient *reportingclient.ReportingClient, pruningConfig config.PruningConfig, cleanupFuncs []func(context.Context) error) *Processor {
 	return &Processor{
 		runesDg:             runesDg,
 		indexerInfoDg:       indexerInfoDg,
 		bitcoinClient:       bitcoinClient,
 		network:             network,
 		reportingClient:     reportingClient,
+		pruningConfig:       pruningConfig,
 		cleanupFuncs:        cleanupFuncs,
 		newRuneEntries:      make(map[runes.RuneId]*runes.RuneEntry),
 		newRuneEntryStates:  make(map[runes.RuneId]*runes.RuneEntry),
 		newOutPointBalances: make(map[wire.OutPoint][]*entity.OutPointBalance),
-		newSpendOutPoints:   make([]wire.OutPoint, 0),
+		newSpendOutPoints:   make([]*entity.SpentOutPoint, 0),
 		newBalances:         make(map[string]map[runes.RuneId]uint128.Uint128),
 		newRuneTxs:          make([]*entity.RuneTransaction, 0),
 	}
@@ -63,10 +69,14 @@ var (
 )
 
 func (p *Processor) VerifyStates(ctx context.Context) error {
-	// TODO: ensure db is migrated
+	// the schema is migrated beforehand, either manually with `gaze migrate up` or on start with `auto_migrate`.
+	// ensureValidState rejects a database that isn't migrated to DBVersion.
 	if err := p.ensureValidState(ctx); err != nil {
 		return errors.Wrap(err, "error during ensureValidState")
 	}
+	if err := p.ensureValidPruningState(ctx); err != nil {
+		return errors.Wrap(err, "error during ensureValidPruningState")
+	}
 	if constants.NetworkHasGenesisRune(p.network) {
 		if err := p.ensureGenesisRune(ctx, p.network); err != nil {
 			return errors.Wrap(err, "error during ensureGenesisRune")
@@ -98,7 +108,7 @@ func (p *Processor) ensureValidState(ctx context.Context) error {
 			return errors.Wrapf(errs.ConflictSetting, "db version mismatch: current version is %d. Please upgrade to version %d", indexerState.DBVersion, constants.DBVersion)
 		}
 		if indexerState.EventHashVersion != constants.EventHashVersion {
-			return errors.Wrapf(errs.ConflictSetting, "event version mismatch: current version is %d. Please reset rune's db first.", indexerState.EventHashVersion, constants.EventHashVersion)
+			return errors.Wrapf(errs.ConflictSetting, "event hash version mismatch: current version is %d, expected %d. Please reset rune's db first.", indexerState.EventHashVersion, constants.EventHashVersion)
 		}
 	}
 
@@ -184,6 +194,14 @@ func (p *Processor) GetIndexedBlock(ctx context.Context, height int64) (types.Bl
 }
 
 func (p *Processor) RevertData(ctx context.Context, from int64) error {
+	prunedHeight, err := p.runesDg.GetPrunedHeight(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pruned height")
+	}
+	if from <= int64(prunedHeight) {
+		return errors.Wrapf(errs.InvalidState, "cannot revert data from height %d: history before height %d has been pruned", from, prunedHeight)
+	}
+
 	runesDgTx, err := p.runesDg.BeginRunesTx(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to begin transaction")
@@ -218,6 +236,9 @@ func (p *Processor) RevertData(ctx context.Context, from int64) error {
 	if err := runesDgTx.UnspendOutPointBalancesSinceHeight(ctx, uint64(from)); err != nil {
 		return errors.Wrap(err, "failed to unspend outpoint balances")
 	}
+	if err := runesDgTx.RevertCurrentBalancesSinceHeight(ctx, uint64(from)); err != nil {
+		return errors.Wrap(err, "failed to revert current balances")
+	}
 	if err := runesDgTx.DeleteRuneBalancesSinceHeight(ctx, uint64(from)); err != nil {
 		return errors.Wrap(err, "failed to delete rune balances")
 	}
@@ -228,6 +249,15 @@ func (p *Processor) RevertData(ctx context.Context, from int64) error {
 	return nil
 }
 
+// CountRevertData returns the number of rows per table that RevertData would delete or update, without modifying any data.
+func (p *Processor) CountRevertData(ctx context.Context, from int64) (*entity.RevertCounts, error) {
+	counts, err := p.runesDg.CountRevertRowsSinceHeight(ctx, uint64(from))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count revert rows")
+	}
+	return counts, nil
+}
+
 func (p *Processor) Shutdown(ctx context.Context) error {
 	var errs []error
 	for _, cleanup := range p.cleanupFuncs {
@@ -0,0 +1,28 @@
+package runes
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/pkg/logger"
+	"github.com/gaze-network/indexer-network/pkg/logger/slogx"
+)
+
+// ensurePartitions makes sure that the block height partitions of the partitioned tables cover the given block height.
+// Partitions are created outside of the flush transaction, since creating a partition locks the parent table.
+func (p *Processor) ensurePartitions(ctx context.Context, blockHeight int64) error {
+	if blockHeight < p.partitionEndHeight {
+		return nil
+	}
+	partitionEndHeight, err := p.runesDg.CreatePartitions(ctx, uint64(blockHeight), uint64(blockHeight))
+	if err != nil {
+		return errors.Wrap(err, "failed to create partitions")
+	}
+	p.partitionEndHeight = int64(partitionEndHeight)
+	logger.DebugContext(ctx, "Ensured partitions for block height",
+		slogx.String("event", "runes_processor_ensured_partitions"),
+		slog.Int64("partition_end_height", p.partitionEndHeight),
+	)
+	return nil
+}
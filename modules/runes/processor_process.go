@@ -43,9 +43,15 @@ func (p *Processor) Process(ctx context.Context, blocks []*types.Block) error {
 			slog.Duration("time_taken", timeTakenToProcess),
 		)
 
+		if err := p.ensurePartitions(ctx, block.Header.Height); err != nil {
+			return errors.Wrap(err, "failed to ensure partitions")
+		}
 		if err := p.flushBlock(ctx, block.Header); err != nil {
 			return errors.Wrap(err, "failed to flush block")
 		}
+		if err := p.pruneHistory(ctx, block.Header.Height); err != nil {
+			return errors.Wrap(err, "failed to prune history")
+		}
 	}
 	return nil
 }
@@ -72,10 +78,15 @@ func (p *Processor) processTx(ctx context.Context, tx *types.Transaction, blockH
 
 	unallocated := make(map[runes.RuneId]uint128.Uint128)
 	allocated := make(map[int]map[runes.RuneId]uint128.Uint128)
-	for _, balances := range inputBalances {
+	for inputIndex, balances := range inputBalances {
 		for runeId, balance := range balances {
 			unallocated[runeId] = unallocated[runeId].Add(balance.Amount)
-			p.newSpendOutPoints = append(p.newSpendOutPoints, balance.OutPoint)
+			p.newSpendOutPoints = append(p.newSpendOutPoints, &entity.SpentOutPoint{
+				OutPoint:    balance.OutPoint,
+				BlockHeight: balance.BlockHeight,
+				TxHash:      tx.TxHash,
+				InputIndex:  uint32(inputIndex),
+			})
 		}
 	}
 
@@ -256,6 +267,7 @@ func (p *Processor) processTx(ctx context.Context, tx *types.Transaction, blockH
 				Amount:      amount,
 				BlockHeight: uint64(tx.BlockHeight),
 				SpentHeight: nil,
+				Value:       lo.ToPtr(tx.TxOut[output].Value),
 			})
 		}
 	}
@@ -750,7 +762,7 @@ func (p *Processor) flushBlock(ctx context.Context, blockHeader types.BlockHeade
 	if err := runesDgTx.SpendOutPointBalancesBatch(ctx, newSpendOutPoints, uint64(blockHeader.Height)); err != nil {
 		return errors.Wrap(err, "failed to create spend outpoint")
 	}
-	p.newSpendOutPoints = make([]wire.OutPoint, 0)
+	p.newSpendOutPoints = make([]*entity.SpentOutPoint, 0)
 
 	// flush new newBalances
 	newBalances := make([]*entity.Balance, 0)
@@ -771,6 +783,9 @@ func (p *Processor) flushBlock(ctx context.Context, blockHeader types.BlockHeade
 	if err := runesDgTx.CreateRuneBalances(ctx, newBalances); err != nil {
 		return errors.Wrap(err, "failed to create balances at block")
 	}
+	if err := runesDgTx.UpdateCurrentBalances(ctx, newBalances); err != nil {
+		return errors.Wrap(err, "failed to update current balances")
+	}
 	p.newBalances = make(map[string]map[runes.RuneId]uint128.Uint128)
 
 	// flush new rune transactions
@@ -0,0 +1,86 @@
+package runes
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	"github.com/gaze-network/indexer-network/pkg/logger"
+	"github.com/gaze-network/indexer-network/pkg/logger/slogx"
+)
+
+// minPruningKeepBlocks is the minimum number of blocks to keep full history for. It must cover the maximum reorg depth handled by the indexer.
+const minPruningKeepBlocks = 1000
+
+func (p *Processor) ensureValidPruningState(ctx context.Context) error {
+	if p.pruningConfig.Enabled && p.pruningConfig.KeepBlocks < minPruningKeepBlocks {
+		return errors.Wrapf(errs.InvalidArgument, "pruning keep_blocks must be at least %d, got %d", minPruningKeepBlocks, p.pruningConfig.KeepBlocks)
+	}
+	prunedHeight, err := p.runesDg.GetPrunedHeight(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pruned height")
+	}
+	p.prunedHeight = int64(prunedHeight)
+	if !p.pruningConfig.Enabled && prunedHeight > 0 {
+		logger.WarnContext(ctx, "Pruning is disabled, but the database has already been pruned. History before the pruned height is not available",
+			slogx.String("event", "runes_processor_pruned_database"),
+			slog.Uint64("pruned_height", prunedHeight),
+		)
+	}
+	return nil
+}
+
+// pruneHistory compacts history older than the last `keep_blocks` blocks. Spent outpoint balances and superseded balances before the pruned height are deleted.
+// Queries at or after the pruned height are unaffected.
+func (p *Processor) pruneHistory(ctx context.Context, latestHeight int64) error {
+	if !p.pruningConfig.Enabled {
+		return nil
+	}
+	prunedHeight := latestHeight - p.pruningConfig.KeepBlocks
+	if prunedHeight <= 0 || prunedHeight-p.prunedHeight < constants.PruneInterval {
+		return nil
+	}
+
+	start := time.Now()
+	runesDgTx, err := p.runesDg.BeginRunesTx(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin runes tx")
+	}
+	defer func() {
+		if err := runesDgTx.Rollback(ctx); err != nil {
+			logger.WarnContext(ctx, "failed to rollback transaction",
+				slogx.Error(err),
+				slogx.String("event", "rollback_runes_prune"),
+			)
+		}
+	}()
+
+	prunedOutPoints, err := runesDgTx.PruneSpentOutPointBalances(ctx, uint64(prunedHeight))
+	if err != nil {
+		return errors.Wrap(err, "failed to prune spent outpoint balances")
+	}
+	prunedBalances, err := runesDgTx.PruneRuneBalances(ctx, uint64(prunedHeight))
+	if err != nil {
+		return errors.Wrap(err, "failed to prune rune balances")
+	}
+	if err := runesDgTx.SetPrunedHeight(ctx, uint64(prunedHeight)); err != nil {
+		return errors.Wrap(err, "failed to set pruned height")
+	}
+
+	if err := runesDgTx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "failed to commit runes tx")
+	}
+	p.prunedHeight = prunedHeight
+
+	logger.InfoContext(ctx, "Pruned history",
+		slogx.String("event", "runes_processor_pruned_history"),
+		slog.Int64("pruned_height", prunedHeight),
+		slog.Int64("pruned_outpoint_balances", prunedOutPoints),
+		slog.Int64("pruned_balances", prunedBalances),
+		slogx.Duration("time_taken", time.Since(start)),
+	)
+	return nil
+}
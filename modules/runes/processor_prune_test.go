@@ -0,0 +1,200 @@
+package runes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/config"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePruneDg records the pruning calls. Calling any other method panics.
+type fakePruneDg struct {
+	datagateway.RunesDataGatewayWithTx
+
+	prunedHeight         uint64
+	prunedOutPointHeight uint64
+	prunedBalanceHeight  uint64
+	committed            bool
+	pruneErr             error
+}
+
+func (d *fakePruneDg) GetPrunedHeight(ctx context.Context) (uint64, error) {
+	return d.prunedHeight, nil
+}
+
+func (d *fakePruneDg) BeginRunesTx(ctx context.Context) (datagateway.RunesDataGatewayWithTx, error) {
+	return d, nil
+}
+
+func (d *fakePruneDg) PruneSpentOutPointBalances(ctx context.Context, prunedHeight uint64) (int64, error) {
+	if d.pruneErr != nil {
+		return 0, d.pruneErr
+	}
+	d.prunedOutPointHeight = prunedHeight
+	return 1, nil
+}
+
+func (d *fakePruneDg) PruneRuneBalances(ctx context.Context, prunedHeight uint64) (int64, error) {
+	d.prunedBalanceHeight = prunedHeight
+	return 1, nil
+}
+
+func (d *fakePruneDg) SetPrunedHeight(ctx context.Context, prunedHeight uint64) error {
+	d.prunedHeight = prunedHeight
+	return nil
+}
+
+func (d *fakePruneDg) Commit(ctx context.Context) error {
+	d.committed = true
+	return nil
+}
+
+func (d *fakePruneDg) Rollback(ctx context.Context) error {
+	return nil
+}
+
+func TestEnsureValidPruningState(t *testing.T) {
+	type testcase struct {
+		name                 string
+		pruningConfig        config.PruningConfig
+		dbPrunedHeight       uint64
+		expectedErr          error
+		expectedPrunedHeight int64
+	}
+
+	testcases := []testcase{
+		{
+			name:                 "disabled",
+			pruningConfig:        config.PruningConfig{},
+			expectedPrunedHeight: 0,
+		},
+		{
+			name:                 "disabled on pruned database",
+			pruningConfig:        config.PruningConfig{},
+			dbPrunedHeight:       5000,
+			expectedPrunedHeight: 5000,
+		},
+		{
+			name:                 "enabled",
+			pruningConfig:        config.PruningConfig{Enabled: true, KeepBlocks: minPruningKeepBlocks},
+			dbPrunedHeight:       5000,
+			expectedPrunedHeight: 5000,
+		},
+		{
+			name:          "keep blocks too low",
+			pruningConfig: config.PruningConfig{Enabled: true, KeepBlocks: minPruningKeepBlocks - 1},
+			expectedErr:   errs.InvalidArgument,
+		},
+		{
+			name:                 "keep blocks too low but disabled",
+			pruningConfig:        config.PruningConfig{Enabled: false, KeepBlocks: 1},
+			expectedPrunedHeight: 0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &Processor{
+				runesDg:       &fakePruneDg{prunedHeight: tc.dbPrunedHeight},
+				pruningConfig: tc.pruningConfig,
+			}
+			err := p.ensureValidPruningState(context.Background())
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedPrunedHeight, p.prunedHeight)
+		})
+	}
+}
+
+func TestPruneHistory(t *testing.T) {
+	type testcase struct {
+		name                 string
+		pruningConfig        config.PruningConfig
+		prunedHeight         int64
+		latestHeight         int64
+		expectedPruned       bool
+		expectedPrunedHeight int64
+	}
+
+	enabled := config.PruningConfig{Enabled: true, KeepBlocks: 1000}
+	testcases := []testcase{
+		{
+			name:                 "disabled",
+			pruningConfig:        config.PruningConfig{KeepBlocks: 1000},
+			latestHeight:         10000,
+			expectedPruned:       false,
+			expectedPrunedHeight: 0,
+		},
+		{
+			name:                 "not enough blocks",
+			pruningConfig:        enabled,
+			latestHeight:         1000,
+			expectedPruned:       false,
+			expectedPrunedHeight: 0,
+		},
+		{
+			name:                 "first prune",
+			pruningConfig:        enabled,
+			latestHeight:         10000,
+			expectedPruned:       true,
+			expectedPrunedHeight: 9000,
+		},
+		{
+			name:                 "before prune interval",
+			pruningConfig:        enabled,
+			prunedHeight:         9000,
+			latestHeight:         10000 + constants.PruneInterval - 1,
+			expectedPruned:       false,
+			expectedPrunedHeight: 9000,
+		},
+		{
+			name:                 "at prune interval",
+			pruningConfig:        enabled,
+			prunedHeight:         9000,
+			latestHeight:         10000 + constants.PruneInterval,
+			expectedPruned:       true,
+			expectedPrunedHeight: 9000 + constants.PruneInterval,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dg := &fakePruneDg{prunedHeight: uint64(tc.prunedHeight)}
+			p := &Processor{
+				runesDg:       dg,
+				pruningConfig: tc.pruningConfig,
+				prunedHeight:  tc.prunedHeight,
+			}
+			err := p.pruneHistory(context.Background(), tc.latestHeight)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedPruned, dg.committed)
+			assert.Equal(t, tc.expectedPrunedHeight, p.prunedHeight)
+			assert.Equal(t, uint64(tc.expectedPrunedHeight), dg.prunedHeight)
+			if tc.expectedPruned {
+				assert.Equal(t, uint64(tc.expectedPrunedHeight), dg.prunedOutPointHeight)
+				assert.Equal(t, uint64(tc.expectedPrunedHeight), dg.prunedBalanceHeight)
+			}
+		})
+	}
+
+	t.Run("failed prune is not committed", func(t *testing.T) {
+		dg := &fakePruneDg{pruneErr: errors.New("prune failed")}
+		p := &Processor{
+			runesDg:       dg,
+			pruningConfig: enabled,
+		}
+		err := p.pruneHistory(context.Background(), 10000)
+		assert.Error(t, err)
+		assert.False(t, dg.committed)
+		assert.Equal(t, int64(0), p.prunedHeight)
+		assert.Equal(t, uint64(0), dg.prunedHeight)
+	})
+}
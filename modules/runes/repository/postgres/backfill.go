@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"math"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/repository/postgres/gen"
+)
+
+var _ datagateway.BackfillDataGateway = (*Repository)(nil)
+
+func (r *Repository) GetOutPointTxsWithoutValue(ctx context.Context, after chainhash.Hash, limit int32) ([]*entity.TxAtHeight, error) {
+	rows, err := r.queries.GetOutPointTxsWithoutValue(ctx, gen.GetOutPointTxsWithoutValueParams{
+		CursorTxHash: after.String(),
+		Limit:        limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	result := make([]*entity.TxAtHeight, 0, len(rows))
+	for _, row := range rows {
+		txHash, err := chainhash.NewHashFromStr(row.TxHash)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse tx hash")
+		}
+		result = append(result, &entity.TxAtHeight{
+			TxHash:      *txHash,
+			BlockHeight: uint64(row.BlockHeight),
+		})
+	}
+	return result, nil
+}
+
+func (r *Repository) SetOutPointValues(ctx context.Context, values []*entity.OutPointValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	params := gen.BatchSetOutPointValuesParams{
+		TxHashArr:      make([]string, 0, len(values)),
+		TxIdxArr:       make([]int32, 0, len(values)),
+		BlockHeightArr: make([]int32, 0, len(values)),
+		ValueArr:       make([]int64, 0, len(values)),
+		MinBlockHeight: math.MaxInt32,
+	}
+	for _, value := range values {
+		params.TxHashArr = append(params.TxHashArr, value.OutPoint.Hash.String())
+		params.TxIdxArr = append(params.TxIdxArr, int32(value.OutPoint.Index))
+		params.BlockHeightArr = append(params.BlockHeightArr, int32(value.BlockHeight))
+		params.ValueArr = append(params.ValueArr, value.Value)
+		params.MinBlockHeight = min(params.MinBlockHeight, int32(value.BlockHeight))
+		params.MaxBlockHeight = max(params.MaxBlockHeight, int32(value.BlockHeight))
+	}
+
+	if err := r.queries.BatchSetOutPointValues(ctx, params); err != nil {
+		return errors.Wrap(err, "error during exec BatchSetOutPointValues")
+	}
+	return nil
+}
@@ -11,6 +11,33 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const batchCreateOutPointSpends = `-- name: BatchCreateOutPointSpends :exec
+INSERT INTO runes_outpoint_spends ("tx_hash", "tx_idx", "block_height", "spent_height")
+VALUES(
+  unnest($1::TEXT[]),
+  unnest($2::INT[]),
+  unnest($3::INT[]),
+  unnest($4::INT[])
+)
+`
+
+type BatchCreateOutPointSpendsParams struct {
+	TxHashArr      []string
+	TxIdxArr       []int32
+	BlockHeightArr []int32
+	SpentHeightArr []int32
+}
+
+func (q *Queries) BatchCreateOutPointSpends(ctx context.Context, arg BatchCreateOutPointSpendsParams) error {
+	_, err := q.db.Exec(ctx, batchCreateOutPointSpends,
+		arg.TxHashArr,
+		arg.TxIdxArr,
+		arg.BlockHeightArr,
+		arg.SpentHeightArr,
+	)
+	return err
+}
+
 const batchCreateRuneEntries = `-- name: BatchCreateRuneEntries :exec
 INSERT INTO runes_entries ("rune_id", "rune", "number", "spacers", "premine", "symbol", "divisibility", "terms", "terms_amount", "terms_cap", "terms_height_start", "terms_height_end", "terms_offset_start", "terms_offset_end", "turbo", "etching_block", "etching_tx_hash", "etched_at")
 VALUES(
@@ -113,6 +140,63 @@ func (q *Queries) BatchCreateRuneEntryStates(ctx context.Context, arg BatchCreat
 	return err
 }
 
+const batchCreateRuneTransactionHeights = `-- name: BatchCreateRuneTransactionHeights :exec
+INSERT INTO runes_transaction_heights ("hash", "block_height")
+VALUES(
+  unnest($1::TEXT[]),
+  unnest($2::INT[])
+)
+`
+
+type BatchCreateRuneTransactionHeightsParams struct {
+	HashArr        []string
+	BlockHeightArr []int32
+}
+
+func (q *Queries) BatchCreateRuneTransactionHeights(ctx context.Context, arg BatchCreateRuneTransactionHeightsParams) error {
+	_, err := q.db.Exec(ctx, batchCreateRuneTransactionHeights, arg.HashArr, arg.BlockHeightArr)
+	return err
+}
+
+const batchCreateRuneTransactionIO = `-- name: BatchCreateRuneTransactionIO :exec
+INSERT INTO runes_transaction_io ("tx_hash", "block_height", "tx_index", "io_type", "io_index", "pkscript", "rune_id", "amount")
+VALUES (
+  unnest($1::TEXT[]),
+  unnest($2::INT[]),
+  unnest($3::INT[]),
+  unnest($4::TEXT[]),
+  unnest($5::INT[]),
+  unnest($6::TEXT[]), -- nullable (need patch)
+  unnest($7::TEXT[]),
+  unnest($8::DECIMAL[])
+)
+`
+
+type BatchCreateRuneTransactionIOParams struct {
+	TxHashArr      []string
+	BlockHeightArr []int32
+	TxIndexArr     []int32
+	IoTypeArr      []string
+	IoIndexArr     []int32
+	PkscriptArr    []string
+	RuneIDArr      []string
+	AmountArr      []pgtype.Numeric
+}
+
+func (q *Queries) BatchCreateRuneTransactionIO(ctx context.Context, arg BatchCreateRuneTransactionIOParams) error {
+	_, err := q.db.Exec(ctx, batchCreateRuneTransactionIO,
+		arg.TxHashArr,
+		arg.BlockHeightArr,
+		arg.TxIndexArr,
+		arg.IoTypeArr,
+		arg.IoIndexArr,
+		arg.PkscriptArr,
+		arg.RuneIDArr,
+		arg.AmountArr,
+	)
+	return err
+}
+
 const batchCreateRuneTransactions = `-- name: BatchCreateRuneTransactions :exec
 INSERT INTO runes_transactions ("hash", "block_height", "index", "timestamp", "inputs", "outputs", "mints", "burns", "rune_etched")
 VALUES (
@@ -183,7 +267,7 @@ func (q *Queries) BatchCreateRunesBalances(ctx context.Context, arg BatchCreateR
 }
 
 const batchCreateRunesOutpointBalances = `-- name: BatchCreateRunesOutpointBalances :exec
-INSERT INTO runes_outpoint_balances ("rune_id", "pkscript", "tx_hash", "tx_idx", "amount", "block_height", "spent_height")
+INSERT INTO runes_outpoint_balances ("rune_id", "pkscript", "tx_hash", "tx_idx", "amount", "block_height", "spent_height", "value")
 VALUES(
   unnest($1::TEXT[]),
   unnest($2::TEXT[]),
@@ -191,7 +275,8 @@ VALUES(
   unnest($4::INT[]),
   unnest($5::DECIMAL[]),
   unnest($6::INT[]),
-  unnest($7::INT[]) -- nullable (need patch)
+  unnest($7::INT[]), -- nullable (need patch)
+  unnest($8::BIGINT[]) -- nullable (need patch)
 )
 `
 
@@ -203,6 +288,7 @@ type BatchCreateRunesOutpointBalancesParams struct {
 	AmountArr      []pgtype.Numeric
 	BlockHeightArr []int32
 	SpentHeightArr []int32
+	ValueArr       []int64
 }
 
 func (q *Queries) BatchCreateRunesOutpointBalances(ctx context.Context, arg BatchCreateRunesOutpointBalancesParams) error {
@@ -214,6 +300,7 @@ func (q *Queries) BatchCreateRunesOutpointBalances(ctx context.Context, arg Batc
 		arg.AmountArr,
 		arg.BlockHeightArr,
 		arg.SpentHeightArr,
+		arg.ValueArr,
 	)
 	return err
 }
@@ -296,24 +383,128 @@ func (q *Queries) BatchCreateRunestones(ctx context.Context, arg BatchCreateRune
 	return err
 }
 
+const batchDeleteCurrentBalances = `-- name: BatchDeleteCurrentBalances :exec
+DELETE FROM runes_current_balances
+	USING (
+    SELECT
+      unnest($1::TEXT[]) AS pkscript,
+      unnest($2::TEXT[]) AS rune_id
+    ) AS input
+	WHERE "runes_current_balances"."pkscript" = "input"."pkscript" AND "runes_current_balances"."rune_id" = "input"."rune_id"
+`
+
+type BatchDeleteCurrentBalancesParams struct {
+	PkscriptArr []string
+	RuneIDArr   []string
+}
+
+func (q *Queries) BatchDeleteCurrentBalances(ctx context.Context, arg BatchDeleteCurrentBalancesParams) error {
+	_, err := q.db.Exec(ctx, batchDeleteCurrentBalances, arg.PkscriptArr, arg.RuneIDArr)
+	return err
+}
+
+const batchSetOutPointValues = `-- name: BatchSetOutPointValues :exec
+UPDATE runes_outpoint_balances
+	SET "value" = "input"."value"
+	FROM (
+    SELECT 
+      unnest($1::TEXT[]) AS tx_hash, 
+      unnest($2::INT[]) AS tx_idx,
+      unnest($3::INT[]) AS block_height,
+      unnest($4::BIGINT[]) AS value
+    ) AS input
+	WHERE "runes_outpoint_balances"."tx_hash" = "input"."tx_hash" AND "runes_outpoint_balances"."tx_idx" = "input"."tx_idx" AND "runes_outpoint_balances"."block_height" = "input"."block_height"
+		AND "runes_outpoint_balances"."value" IS NULL
+		AND "runes_outpoint_balances"."block_height" BETWEEN $5::INT AND $6::INT
+`
+
+type BatchSetOutPointValuesParams struct {
+	TxHashArr      []string
+	TxIdxArr       []int32
+	BlockHeightArr []int32
+	ValueArr       []int64
+	MinBlockHeight int32
+	MaxBlockHeight int32
+}
+
+// only sets the value of outpoint balances without a recorded value
+func (q *Queries) BatchSetOutPointValues(ctx context.Context, arg BatchSetOutPointValuesParams) error {
+	_, err := q.db.Exec(ctx, batchSetOutPointValues,
+		arg.TxHashArr,
+		arg.TxIdxArr,
+		arg.BlockHeightArr,
+		arg.ValueArr,
+		arg.MinBlockHeight,
+		arg.MaxBlockHeight,
+	)
+	return err
+}
+
 const batchSpendOutpointBalances = `-- name: BatchSpendOutpointBalances :exec
 UPDATE runes_outpoint_balances
-	SET "spent_height" = $1::INT
+	SET "spent_height" = $1::INT, "spent_tx_hash" = "input"."spent_tx_hash", "spent_tx_input_idx" = "input"."spent_tx_input_idx"
 	FROM (
     SELECT 
       unnest($2::TEXT[]) AS tx_hash, 
-      unnest($3::INT[]) AS tx_idx
+      unnest($3::INT[]) AS tx_idx,
+      unnest($4::INT[]) AS block_height,
+      unnest($5::TEXT[]) AS spent_tx_hash,
+      unnest($6::INT[]) AS spent_tx_input_idx
     ) AS input
-	WHERE "runes_outpoint_balances"."tx_hash" = "input"."tx_hash" AND "runes_outpoint_balances"."tx_idx" = "input"."tx_idx"
+	WHERE "runes_outpoint_balances"."tx_hash" = "input"."tx_hash" AND "runes_outpoint_balances"."tx_idx" = "input"."tx_idx" AND "runes_outpoint_balances"."block_height" = "input"."block_height"
+		AND "runes_outpoint_balances"."block_height" BETWEEN $7::INT AND $8::INT
 `
 
 type BatchSpendOutpointBalancesParams struct {
-	SpentHeight int32
-	TxHashArr   []string
-	TxIdxArr    []int32
+	SpentHeight        int32
+	TxHashArr          []string
+	TxIdxArr           []int32
+	BlockHeightArr     []int32
+	SpentTxHashArr     []string
+	SpentTxInputIdxArr []int32
+	MinBlockHeight     int32
+	MaxBlockHeight     int32
 }
 
+// the block height range of the outpoints bounds the partitions that are scanned
 func (q *Queries) BatchSpendOutpointBalances(ctx context.Context, arg BatchSpendOutpointBalancesParams) error {
-	_, err := q.db.Exec(ctx, batchSpendOutpointBalances, arg.SpentHeight, arg.TxHashArr, arg.TxIdxArr)
+	_, err := q.db.Exec(ctx, batchSpendOutpointBalances,
+		arg.SpentHeight,
+		arg.TxHashArr,
+		arg.TxIdxArr,
+		arg.BlockHeightArr,
+		arg.SpentTxHashArr,
+		arg.SpentTxInputIdxArr,
+		arg.MinBlockHeight,
+		arg.MaxBlockHeight,
+	)
+	return err
+}
+
+const batchUpsertCurrentBalances = `-- name: BatchUpsertCurrentBalances :exec
+INSERT INTO runes_current_balances ("pkscript", "rune_id", "amount", "block_height")
+VALUES(
+  unnest($1::TEXT[]),
+  unnest($2::TEXT[]),
+  unnest($3::DECIMAL[]),
+  unnest($4::INT[])
+)
+ON CONFLICT ("pkscript", "rune_id") DO UPDATE SET "amount" = EXCLUDED."amount", "block_height" = EXCLUDED."block_height"
+`
+
+type BatchUpsertCurrentBalancesParams struct {
+	PkscriptArr    []string
+	RuneIDArr      []string
+	AmountArr      []pgtype.Numeric
+	BlockHeightArr []int32
+}
+
+func (q *Queries) BatchUpsertCurrentBalances(ctx context.Context, arg BatchUpsertCurrentBalancesParams) error {
+	_, err := q.db.Exec(ctx, batchUpsertCurrentBalances,
+		arg.PkscriptArr,
+		arg.RuneIDArr,
+		arg.AmountArr,
+		arg.BlockHeightArr,
+	)
 	return err
 }
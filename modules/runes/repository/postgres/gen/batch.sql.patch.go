@@ -59,6 +59,7 @@ func (q *Queries) BatchCreateRuneEntryStatesPatched(ctx context.Context, arg Bat
 type BatchCreateRunesOutpointBalancesPatchedParams struct {
 	BatchCreateRunesOutpointBalancesParams
 	SpentHeightArr []pgtype.Int4
+	ValueArr       []pgtype.Int8
 }
 
 func (q *Queries) BatchCreateRunesOutpointBalancesPatched(ctx context.Context, arg BatchCreateRunesOutpointBalancesPatchedParams) error {
@@ -70,6 +71,7 @@ func (q *Queries) BatchCreateRunesOutpointBalancesPatched(ctx context.Context, a
 		arg.AmountArr,
 		arg.BlockHeightArr,
 		arg.SpentHeightArr,
+		arg.ValueArr,
 	)
 	return errors.WithStack(err)
 }
@@ -116,3 +118,22 @@ func (q *Queries) BatchCreateRunestonesPatched(ctx context.Context, arg BatchCre
 	)
 	return errors.WithStack(err)
 }
+
+type BatchCreateRuneTransactionIOPatchedParams struct {
+	BatchCreateRuneTransactionIOParams
+	PkscriptArr []pgtype.Text
+}
+
+func (q *Queries) BatchCreateRuneTransactionIOPatched(ctx context.Context, arg BatchCreateRuneTransactionIOPatchedParams) error {
+	_, err := q.db.Exec(ctx, batchCreateRuneTransactionIO,
+		arg.TxHashArr,
+		arg.BlockHeightArr,
+		arg.TxIndexArr,
+		arg.IoTypeArr,
+		arg.IoIndexArr,
+		arg.PkscriptArr,
+		arg.RuneIDArr,
+		arg.AmountArr,
+	)
+	return errors.WithStack(err)
+}
@@ -11,6 +11,53 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countRevertRowsSinceHeight = `-- name: CountRevertRowsSinceHeight :one
+SELECT
+  (SELECT COUNT(*) FROM runes_indexed_blocks WHERE height >= $1) AS indexed_blocks,
+  (SELECT COUNT(*) FROM runes_entries WHERE etching_block >= $1) AS rune_entries,
+  (SELECT COUNT(*) FROM runes_entry_states WHERE block_height >= $1) AS rune_entry_states,
+  (SELECT COUNT(*) FROM runes_transactions WHERE block_height >= $1) AS rune_transactions,
+  (SELECT COUNT(*) FROM runes_transaction_io WHERE block_height >= $1) AS rune_transaction_io,
+  (SELECT COUNT(*) FROM runes_runestones WHERE block_height >= $1) AS runestones,
+  (SELECT COUNT(*) FROM runes_outpoint_balances WHERE block_height >= $1) AS outpoint_balances,
+  (SELECT COUNT(*) FROM runes_outpoint_spends AS spent JOIN runes_outpoint_balances ON runes_outpoint_balances.tx_hash = spent.tx_hash AND runes_outpoint_balances.tx_idx = spent.tx_idx AND runes_outpoint_balances.block_height = spent.block_height
+    WHERE spent.spent_height >= $1 AND spent.block_height < $1) AS spent_outpoint_balances,
+  (SELECT COUNT(*) FROM runes_balances WHERE block_height >= $1) AS balances,
+  (SELECT COUNT(*) FROM (SELECT DISTINCT pkscript, rune_id FROM runes_balances WHERE block_height >= $1) AS changed_balances) AS current_balances
+`
+
+type CountRevertRowsSinceHeightRow struct {
+	IndexedBlocks         int64
+	RuneEntries           int64
+	RuneEntryStates       int64
+	RuneTransactions      int64
+	RuneTransactionIo     int64
+	Runestones            int64
+	OutpointBalances      int64
+	SpentOutpointBalances int64
+	Balances              int64
+	CurrentBalances       int64
+}
+
+// count the rows that are deleted or updated when reverting data since the given height
+func (q *Queries) CountRevertRowsSinceHeight(ctx context.Context, fromHeight int32) (CountRevertRowsSinceHeightRow, error) {
+	row := q.db.QueryRow(ctx, countRevertRowsSinceHeight, fromHeight)
+	var i CountRevertRowsSinceHeightRow
+	err := row.Scan(
+		&i.IndexedBlocks,
+		&i.RuneEntries,
+		&i.RuneEntryStates,
+		&i.RuneTransactions,
+		&i.RuneTransactionIo,
+		&i.Runestones,
+		&i.OutpointBalances,
+		&i.SpentOutpointBalances,
+		&i.Balances,
+		&i.CurrentBalances,
+	)
+	return i, err
+}
+
 const countRuneEntries = `-- name: CountRuneEntries :one
 SELECT COUNT(*) FROM runes_entries
 `
@@ -46,7 +93,7 @@ func (q *Queries) CreateIndexedBlock(ctx context.Context, arg CreateIndexedBlock
 }
 
 const createOutPointBalance = `-- name: CreateOutPointBalance :exec
-INSERT INTO runes_outpoint_balances (rune_id, pkscript, tx_hash, tx_idx, amount, block_height, spent_height) VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO runes_outpoint_balances (rune_id, pkscript, tx_hash, tx_idx, amount, block_height, spent_height, value) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 `
 
 type CreateOutPointBalanceParams struct {
@@ -57,6 +104,7 @@ type CreateOutPointBalanceParams struct {
 	Amount      pgtype.Numeric
 	BlockHeight int32
 	SpentHeight pgtype.Int4
+	Value       pgtype.Int8
 }
 
 func (q *Queries) CreateOutPointBalance(ctx context.Context, arg CreateOutPointBalanceParams) error {
@@ -68,10 +116,37 @@ func (q *Queries) CreateOutPointBalance(ctx context.Context, arg CreateOutPointB
 		arg.Amount,
 		arg.BlockHeight,
 		arg.SpentHeight,
+		arg.Value,
 	)
 	return err
 }
 
+const createPartitions = `-- name: CreatePartitions :one
+SELECT runes_create_partitions($1::INT, $2::INT)::INT AS partition_end_height
+`
+
+type CreatePartitionsParams struct {
+	FromHeight int32
+	ToHeight   int32
+}
+
+// create partitions of partitioned tables to cover the given block height range, returns the exclusive upper bound of the created partitions
+func (q *Queries) CreatePartitions(ctx context.Context, arg CreatePartitionsParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createPartitions, arg.FromHeight, arg.ToHeight)
+	var partition_end_height int32
+	err := row.Scan(&partition_end_height)
+	return partition_end_height, err
+}
+
+const createPruneState = `-- name: CreatePruneState :exec
+INSERT INTO runes_prune_state (pruned_height) VALUES ($1)
+`
+
+func (q *Queries) CreatePruneState(ctx context.Context, prunedHeight int32) error {
+	_, err := q.db.Exec(ctx, createPruneState, prunedHeight)
+	return err
+}
+
 const createRuneBalance = `-- name: CreateRuneBalance :exec
 INSERT INTO runes_balances (pkscript, block_height, rune_id, amount) VALUES ($1, $2, $3, $4)
 `
@@ -255,6 +330,18 @@ func (q *Queries) CreateRunestone(ctx context.Context, arg CreateRunestoneParams
 	return err
 }
 
+const deleteCurrentBalancesSinceHeight = `-- name: DeleteCurrentBalancesSinceHeight :exec
+DELETE FROM runes_current_balances WHERE (pkscript, rune_id) IN (
+  SELECT DISTINCT pkscript, rune_id FROM runes_balances WHERE block_height >= $1
+)
+`
+
+// delete current balances of all pkscript and rune pairs that changed since the given height
+func (q *Queries) DeleteCurrentBalancesSinceHeight(ctx context.Context, blockHeight int32) error {
+	_, err := q.db.Exec(ctx, deleteCurrentBalancesSinceHeight, blockHeight)
+	return err
+}
+
 const deleteIndexedBlockSinceHeight = `-- name: DeleteIndexedBlockSinceHeight :exec
 DELETE FROM runes_indexed_blocks WHERE height >= $1
 `
@@ -273,6 +360,15 @@ func (q *Queries) DeleteOutPointBalancesSinceHeight(ctx context.Context, blockHe
 	return err
 }
 
+const deleteOutPointSpendsSinceHeight = `-- name: DeleteOutPointSpendsSinceHeight :exec
+DELETE FROM runes_outpoint_spends WHERE spent_height >= $1
+`
+
+func (q *Queries) DeleteOutPointSpendsSinceHeight(ctx context.Context, spentHeight int32) error {
+	_, err := q.db.Exec(ctx, deleteOutPointSpendsSinceHeight, spentHeight)
+	return err
+}
+
 const deleteRuneBalancesSinceHeight = `-- name: DeleteRuneBalancesSinceHeight :exec
 DELETE FROM runes_balances WHERE block_height >= $1
 `
@@ -300,6 +396,24 @@ func (q *Queries) DeleteRuneEntryStatesSinceHeight(ctx context.Context, blockHei
 	return err
 }
 
+const deleteRuneTransactionHeightsSinceHeight = `-- name: DeleteRuneTransactionHeightsSinceHeight :exec
+DELETE FROM runes_transaction_heights WHERE block_height >= $1
+`
+
+func (q *Queries) DeleteRuneTransactionHeightsSinceHeight(ctx context.Context, blockHeight int32) error {
+	_, err := q.db.Exec(ctx, deleteRuneTransactionHeightsSinceHeight, blockHeight)
+	return err
+}
+
+const deleteRuneTransactionIOSinceHeight = `-- name: DeleteRuneTransactionIOSinceHeight :exec
+DELETE FROM runes_transaction_io WHERE block_height >= $1
+`
+
+func (q *Queries) DeleteRuneTransactionIOSinceHeight(ctx context.Context, blockHeight int32) error {
+	_, err := q.db.Exec(ctx, deleteRuneTransactionIOSinceHeight, blockHeight)
+	return err
+}
+
 const deleteRuneTransactionsSinceHeight = `-- name: DeleteRuneTransactionsSinceHeight :exec
 DELETE FROM runes_transactions WHERE block_height >= $1
 `
@@ -340,6 +454,80 @@ func (q *Queries) GetBalanceByPkScriptAndRuneId(ctx context.Context, arg GetBala
 	return i, err
 }
 
+const getBalanceHistoryByPkScript = `-- name: GetBalanceHistoryByPkScript :many
+WITH changes AS (
+  SELECT rune_id, block_height, amount, LAG(amount, 1, 0) OVER (PARTITION BY rune_id ORDER BY block_height) AS previous_amount FROM runes_balances
+    WHERE pkscript = $3 AND (
+      $4::BOOLEAN = FALSE -- if @filter_rune_id is TRUE, apply rune_id filter
+      OR rune_id = $5::TEXT
+    ) AND block_height <= $6
+), buckets AS (
+  -- group changes into buckets of @interval blocks, starting from @from_block
+  SELECT rune_id, block_height, amount,
+    FIRST_VALUE(previous_amount) OVER (PARTITION BY rune_id, (block_height - $7) / $8::INTEGER ORDER BY block_height) AS bucket_previous_amount,
+    ROW_NUMBER() OVER (PARTITION BY rune_id, (block_height - $7) / $8::INTEGER ORDER BY block_height DESC) AS bucket_row
+  FROM changes WHERE block_height >= $7
+)
+SELECT rune_id, block_height, amount, bucket_previous_amount::DECIMAL AS previous_amount,
+  -- every balance change comes from a rune transaction in the same block
+  (SELECT "timestamp" FROM runes_transactions WHERE runes_transactions.block_height = buckets.block_height LIMIT 1)::TIMESTAMP AS "timestamp"
+  FROM buckets WHERE bucket_row = 1 ORDER BY block_height, rune_id LIMIT $1 OFFSET $2
+`
+
+type GetBalanceHistoryByPkScriptParams struct {
+	Limit        int32
+	Offset       int32
+	Pkscript     string
+	FilterRuneID bool
+	RuneID       string
+	ToBlock      int32
+	FromBlock    int32
+	Interval     int32
+}
+
+type GetBalanceHistoryByPkScriptRow struct {
+	RuneID         string
+	BlockHeight    int32
+	Amount         pgtype.Numeric
+	PreviousAmount pgtype.Numeric
+	Timestamp      pgtype.Timestamp
+}
+
+func (q *Queries) GetBalanceHistoryByPkScript(ctx context.Context, arg GetBalanceHistoryByPkScriptParams) ([]GetBalanceHistoryByPkScriptRow, error) {
+	rows, err := q.db.Query(ctx, getBalanceHistoryByPkScript,
+		arg.Limit,
+		arg.Offset,
+		arg.Pkscript,
+		arg.FilterRuneID,
+		arg.RuneID,
+		arg.ToBlock,
+		arg.FromBlock,
+		arg.Interval,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBalanceHistoryByPkScriptRow
+	for rows.Next() {
+		var i GetBalanceHistoryByPkScriptRow
+		if err := rows.Scan(
+			&i.RuneID,
+			&i.BlockHeight,
+			&i.Amount,
+			&i.PreviousAmount,
+			&i.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getBalancesByPkScript = `-- name: GetBalancesByPkScript :many
 WITH balances AS (
   SELECT DISTINCT ON (rune_id) pkscript, block_height, rune_id, amount FROM runes_balances WHERE pkscript = $1 AND block_height <= $2 ORDER BY rune_id, block_height DESC
@@ -393,16 +581,25 @@ func (q *Queries) GetBalancesByPkScript(ctx context.Context, arg GetBalancesByPk
 
 const getBalancesByRuneId = `-- name: GetBalancesByRuneId :many
 WITH balances AS (
-  SELECT DISTINCT ON (pkscript) pkscript, block_height, rune_id, amount FROM runes_balances WHERE rune_id = $1 AND block_height <= $2 ORDER BY pkscript, block_height DESC
+  SELECT pkscript, block_height, rune_id, amount FROM runes_balances WHERE rune_id = $1 AND block_height <= $2 AND amount > 0 AND (
+      $5::BOOLEAN = FALSE -- if @use_cursor is TRUE, only return balances after the cursor
+      OR amount < $6::DECIMAL
+      OR (amount = $6::DECIMAL AND pkscript > $7::TEXT)
+    ) AND NOT EXISTS (
+      SELECT 1 FROM runes_balances AS newer WHERE newer.pkscript = runes_balances.pkscript AND newer.rune_id = runes_balances.rune_id AND newer.block_height > runes_balances.block_height AND newer.block_height <= $2
+    )
 )
-SELECT pkscript, block_height, rune_id, amount FROM balances WHERE amount > 0 ORDER BY amount DESC, pkscript LIMIT $3 OFFSET $4
+SELECT pkscript, block_height, rune_id, amount FROM balances ORDER BY amount DESC, pkscript LIMIT $3 OFFSET $4
 `
 
 type GetBalancesByRuneIdParams struct {
-	RuneID      string
-	BlockHeight int32
-	Limit       int32
-	Offset      int32
+	RuneID         string
+	BlockHeight    int32
+	Limit          int32
+	Offset         int32
+	UseCursor      bool
+	CursorAmount   pgtype.Numeric
+	CursorPkscript string
 }
 
 type GetBalancesByRuneIdRow struct {
@@ -412,12 +609,16 @@ type GetBalancesByRuneIdRow struct {
 	Amount      pgtype.Numeric
 }
 
+// The latest balance of each pkscript is selected with an anti-join instead of DISTINCT ON, so the cursor filters the scanned balances instead of the deduplicated result.
 func (q *Queries) GetBalancesByRuneId(ctx context.Context, arg GetBalancesByRuneIdParams) ([]GetBalancesByRuneIdRow, error) {
 	rows, err := q.db.Query(ctx, getBalancesByRuneId,
 		arg.RuneID,
 		arg.BlockHeight,
 		arg.Limit,
 		arg.Offset,
+		arg.UseCursor,
+		arg.CursorAmount,
+		arg.CursorPkscript,
 	)
 	if err != nil {
 		return nil, err
@@ -442,6 +643,133 @@ func (q *Queries) GetBalancesByRuneId(ctx context.Context, arg GetBalancesByRune
 	return items, nil
 }
 
+const getBlockHeightAtTimestamp = `-- name: GetBlockHeightAtTimestamp :one
+SELECT block_height FROM runes_transactions WHERE "timestamp" <= $1 ORDER BY "timestamp" DESC LIMIT 1
+`
+
+func (q *Queries) GetBlockHeightAtTimestamp(ctx context.Context, timestamp pgtype.Timestamp) (int32, error) {
+	row := q.db.QueryRow(ctx, getBlockHeightAtTimestamp, timestamp)
+	var block_height int32
+	err := row.Scan(&block_height)
+	return block_height, err
+}
+
+const getCurrentBalancesByPkScript = `-- name: GetCurrentBalancesByPkScript :many
+SELECT pkscript, rune_id, amount, block_height FROM runes_current_balances WHERE pkscript = $1 ORDER BY amount DESC, rune_id LIMIT $2 OFFSET $3
+`
+
+type GetCurrentBalancesByPkScriptParams struct {
+	Pkscript string
+	Limit    int32
+	Offset   int32
+}
+
+func (q *Queries) GetCurrentBalancesByPkScript(ctx context.Context, arg GetCurrentBalancesByPkScriptParams) ([]RunesCurrentBalance, error) {
+	rows, err := q.db.Query(ctx, getCurrentBalancesByPkScript, arg.Pkscript, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RunesCurrentBalance
+	for rows.Next() {
+		var i RunesCurrentBalance
+		if err := rows.Scan(
+			&i.Pkscript,
+			&i.RuneID,
+			&i.Amount,
+			&i.BlockHeight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHolderChangesByRuneId = `-- name: GetHolderChangesByRuneId :many
+WITH changed AS (
+  SELECT DISTINCT pkscript FROM runes_balances WHERE rune_id = $3 AND block_height > $4 AND block_height <= $5
+), diffs AS (
+  SELECT changed.pkscript,
+    COALESCE((SELECT amount FROM runes_balances WHERE runes_balances.pkscript = changed.pkscript AND rune_id = $3 AND block_height <= $4 ORDER BY block_height DESC LIMIT 1), 0)::DECIMAL AS from_amount,
+    COALESCE((SELECT amount FROM runes_balances WHERE runes_balances.pkscript = changed.pkscript AND rune_id = $3 AND block_height <= $5 ORDER BY block_height DESC LIMIT 1), 0)::DECIMAL AS to_amount
+  FROM changed
+)
+SELECT pkscript, from_amount, to_amount FROM diffs WHERE from_amount <> to_amount AND (
+    $6::TEXT = '' -- if @change_type is not empty, only return changes of that type
+    OR ($6::TEXT = 'appeared' AND from_amount = 0)
+    OR ($6::TEXT = 'disappeared' AND to_amount = 0)
+    OR ($6::TEXT = 'increased' AND from_amount > 0 AND to_amount > from_amount)
+    OR ($6::TEXT = 'decreased' AND to_amount > 0 AND to_amount < from_amount)
+  ) ORDER BY ABS(to_amount - from_amount) DESC, pkscript LIMIT $1 OFFSET $2
+`
+
+type GetHolderChangesByRuneIdParams struct {
+	Limit      int32
+	Offset     int32
+	RuneID     string
+	FromBlock  int32
+	ToBlock    int32
+	ChangeType string
+}
+
+type GetHolderChangesByRuneIdRow struct {
+	Pkscript   string
+	FromAmount pgtype.Numeric
+	ToAmount   pgtype.Numeric
+}
+
+func (q *Queries) GetHolderChangesByRuneId(ctx context.Context, arg GetHolderChangesByRuneIdParams) ([]GetHolderChangesByRuneIdRow, error) {
+	rows, err := q.db.Query(ctx, getHolderChangesByRuneId,
+		arg.Limit,
+		arg.Offset,
+		arg.RuneID,
+		arg.FromBlock,
+		arg.ToBlock,
+		arg.ChangeType,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetHolderChangesByRuneIdRow
+	for rows.Next() {
+		var i GetHolderChangesByRuneIdRow
+		if err := rows.Scan(
+			&i.Pkscript,
+			&i.FromAmount,
+			&i.ToAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getIndexedBlockByHash = `-- name: GetIndexedBlockByHash :one
+SELECT height, hash, prev_hash, event_hash, cumulative_event_hash FROM runes_indexed_blocks WHERE hash = $1
+`
+
+func (q *Queries) GetIndexedBlockByHash(ctx context.Context, hash string) (RunesIndexedBlock, error) {
+	row := q.db.QueryRow(ctx, getIndexedBlockByHash, hash)
+	var i RunesIndexedBlock
+	err := row.Scan(
+		&i.Height,
+		&i.Hash,
+		&i.PrevHash,
+		&i.EventHash,
+		&i.CumulativeEventHash,
+	)
+	return i, err
+}
+
 const getIndexedBlockByHeight = `-- name: GetIndexedBlockByHeight :one
 SELECT height, hash, prev_hash, event_hash, cumulative_event_hash FROM runes_indexed_blocks WHERE height = $1
 `
@@ -476,6 +804,17 @@ func (q *Queries) GetLatestIndexedBlock(ctx context.Context) (RunesIndexedBlock,
 	return i, err
 }
 
+const getLatestPrunedHeight = `-- name: GetLatestPrunedHeight :one
+SELECT pruned_height FROM runes_prune_state ORDER BY created_at DESC LIMIT 1
+`
+
+func (q *Queries) GetLatestPrunedHeight(ctx context.Context) (int32, error) {
+	row := q.db.QueryRow(ctx, getLatestPrunedHeight)
+	var pruned_height int32
+	err := row.Scan(&pruned_height)
+	return pruned_height, err
+}
+
 const getOngoingRuneEntries = `-- name: GetOngoingRuneEntries :many
 WITH states AS (
   -- select latest state
@@ -501,16 +840,23 @@ SELECT runes_entries.rune_id, number, rune, spacers, premine, symbol, divisibili
   ) AND (
     $2::text = '' OR
     runes_entries.rune ILIKE '%' || $2::text || '%'
+  ) AND (
+    $3::BOOLEAN = FALSE -- if @use_cursor is TRUE, only return entries after the cursor
+    OR states.mints < $4::DECIMAL
+    OR (states.mints = $4::DECIMAL AND runes_entries.number > $5::BIGINT)
   )
-  ORDER BY states.mints DESC
-  LIMIT $4 OFFSET $3
+  ORDER BY states.mints DESC, runes_entries.number
+  LIMIT $7 OFFSET $6
 `
 
 type GetOngoingRuneEntriesParams struct {
-	Height int32
-	Search string
-	Offset int32
-	Limit  int32
+	Height       int32
+	Search       string
+	UseCursor    bool
+	CursorMints  pgtype.Numeric
+	CursorNumber int64
+	Offset       int32
+	Limit        int32
 }
 
 type GetOngoingRuneEntriesRow struct {
@@ -544,6 +890,9 @@ func (q *Queries) GetOngoingRuneEntries(ctx context.Context, arg GetOngoingRuneE
 	rows, err := q.db.Query(ctx, getOngoingRuneEntries,
 		arg.Height,
 		arg.Search,
+		arg.UseCursor,
+		arg.CursorMints,
+		arg.CursorNumber,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -591,7 +940,7 @@ func (q *Queries) GetOngoingRuneEntries(ctx context.Context, arg GetOngoingRuneE
 }
 
 const getOutPointBalancesAtOutPoint = `-- name: GetOutPointBalancesAtOutPoint :many
-SELECT rune_id, pkscript, tx_hash, tx_idx, amount, block_height, spent_height FROM runes_outpoint_balances WHERE tx_hash = $1 AND tx_idx = $2
+SELECT rune_id, pkscript, tx_hash, tx_idx, amount, block_height, spent_height, spent_tx_hash, spent_tx_input_idx, value FROM runes_outpoint_balances WHERE tx_hash = $1 AND tx_idx = $2
 `
 
 type GetOutPointBalancesAtOutPointParams struct {
@@ -616,6 +965,9 @@ func (q *Queries) GetOutPointBalancesAtOutPoint(ctx context.Context, arg GetOutP
 			&i.Amount,
 			&i.BlockHeight,
 			&i.SpentHeight,
+			&i.SpentTxHash,
+			&i.SpentTxInputIdx,
+			&i.Value,
 		); err != nil {
 			return nil, err
 		}
@@ -627,6 +979,41 @@ func (q *Queries) GetOutPointBalancesAtOutPoint(ctx context.Context, arg GetOutP
 	return items, nil
 }
 
+const getOutPointTxsWithoutValue = `-- name: GetOutPointTxsWithoutValue :many
+SELECT DISTINCT tx_hash, block_height FROM runes_outpoint_balances WHERE value IS NULL AND tx_hash > $1 ORDER BY tx_hash LIMIT $2
+`
+
+type GetOutPointTxsWithoutValueParams struct {
+	CursorTxHash string
+	Limit        int32
+}
+
+type GetOutPointTxsWithoutValueRow struct {
+	TxHash      string
+	BlockHeight int32
+}
+
+// returns the transactions after the cursor that created outpoint balances without a recorded value
+func (q *Queries) GetOutPointTxsWithoutValue(ctx context.Context, arg GetOutPointTxsWithoutValueParams) ([]GetOutPointTxsWithoutValueRow, error) {
+	rows, err := q.db.Query(ctx, getOutPointTxsWithoutValue, arg.CursorTxHash, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOutPointTxsWithoutValueRow
+	for rows.Next() {
+		var i GetOutPointTxsWithoutValueRow
+		if err := rows.Scan(&i.TxHash, &i.BlockHeight); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRuneEntries = `-- name: GetRuneEntries :many
 WITH states AS (
   -- select latest state
@@ -637,16 +1024,20 @@ SELECT runes_entries.rune_id, number, rune, spacers, premine, symbol, divisibili
   WHERE (
     $1 = '' OR
     runes_entries.rune ILIKE $1 || '%'
+  ) AND (
+    $5::BOOLEAN = FALSE OR runes_entries.number > $6::BIGINT -- if @use_cursor is TRUE, only return entries after the cursor
   )
   ORDER BY runes_entries.number 
   LIMIT $3 OFFSET $2
 `
 
 type GetRuneEntriesParams struct {
-	Search interface{}
-	Offset int32
-	Limit  int32
-	Height int32
+	Search       interface{}
+	Offset       int32
+	Limit        int32
+	Height       int32
+	UseCursor    bool
+	CursorNumber int64
 }
 
 type GetRuneEntriesRow struct {
@@ -682,6 +1073,8 @@ func (q *Queries) GetRuneEntries(ctx context.Context, arg GetRuneEntriesParams)
 		arg.Offset,
 		arg.Limit,
 		arg.Height,
+		arg.UseCursor,
+		arg.CursorNumber,
 	)
 	if err != nil {
 		return nil, err
@@ -895,6 +1288,84 @@ func (q *Queries) GetRuneEntriesByRuneIdsAndHeight(ctx context.Context, arg GetR
 	return items, nil
 }
 
+const getRuneEntryStateHistory = `-- name: GetRuneEntryStateHistory :many
+WITH bounds AS (
+  SELECT $1::BIGINT + $2::BIGINT * $3::BIGINT AS first_point,
+    LEAST($4::BIGINT, $1::BIGINT + ($2::BIGINT + $5::BIGINT - 1) * $3::BIGINT) AS last_point
+), points AS (
+  SELECT generate_series(bounds.first_point, bounds.last_point, $3::BIGINT) AS block_height FROM bounds
+), holder_changes AS (
+  SELECT GREATEST(bounds.first_point, $1::BIGINT + CEIL((runes_balances.block_height - $1::BIGINT)::NUMERIC / $3::BIGINT)::BIGINT * $3::BIGINT) AS point,
+    (amount > 0)::INTEGER - (COALESCE(LAG(amount) OVER (PARTITION BY pkscript ORDER BY runes_balances.block_height), 0) > 0)::INTEGER AS delta
+    FROM runes_balances, bounds WHERE rune_id = $6 AND runes_balances.block_height <= bounds.last_point
+), holders AS (
+  SELECT points.block_height, SUM(COALESCE(SUM(holder_changes.delta), 0)) OVER (ORDER BY points.block_height) AS holders_count
+    FROM points LEFT JOIN holder_changes ON holder_changes.point = points.block_height
+    GROUP BY points.block_height
+)
+SELECT holders.block_height::INTEGER AS block_height, states.mints, states.burned_amount, holders.holders_count::BIGINT AS holders_count,
+  -- blocks are not timestamped, so use the timestamp of the latest rune transaction at or before the point
+  (SELECT "timestamp" FROM runes_transactions WHERE runes_transactions.block_height <= holders.block_height ORDER BY runes_transactions.block_height DESC LIMIT 1)::TIMESTAMP AS "timestamp"
+  FROM holders
+  JOIN LATERAL (
+    -- select latest state at the point, skipping points before the etching
+    SELECT mints, burned_amount FROM runes_entry_states WHERE runes_entry_states.rune_id = $6 AND runes_entry_states.block_height <= holders.block_height ORDER BY runes_entry_states.block_height DESC LIMIT 1
+  ) AS states ON TRUE
+  ORDER BY holders.block_height
+`
+
+type GetRuneEntryStateHistoryParams struct {
+	FromBlock int32
+	Offset    int32
+	Interval  int32
+	ToBlock   int32
+	Limit     int32
+	RuneID    string
+}
+
+type GetRuneEntryStateHistoryRow struct {
+	BlockHeight  int32
+	Mints        pgtype.Numeric
+	BurnedAmount pgtype.Numeric
+	HoldersCount int64
+	Timestamp    pgtype.Timestamp
+}
+
+// Only the points of the requested page are generated. Holders counts are computed in a single pass over the balances of the rune:
+// each balance change adds 1 when a pkscript starts holding the rune and subtracts 1 when it stops, and is counted at the first point at or after the change.
+func (q *Queries) GetRuneEntryStateHistory(ctx context.Context, arg GetRuneEntryStateHistoryParams) ([]GetRuneEntryStateHistoryRow, error) {
+	rows, err := q.db.Query(ctx, getRuneEntryStateHistory,
+		arg.FromBlock,
+		arg.Offset,
+		arg.Interval,
+		arg.ToBlock,
+		arg.Limit,
+		arg.RuneID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRuneEntryStateHistoryRow
+	for rows.Next() {
+		var i GetRuneEntryStateHistoryRow
+		if err := rows.Scan(
+			&i.BlockHeight,
+			&i.Mints,
+			&i.BurnedAmount,
+			&i.HoldersCount,
+			&i.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRuneIdFromRune = `-- name: GetRuneIdFromRune :one
 SELECT rune_id FROM runes_entries WHERE rune = $1
 `
@@ -908,8 +1379,8 @@ func (q *Queries) GetRuneIdFromRune(ctx context.Context, rune string) (string, e
 
 const getRuneTransaction = `-- name: GetRuneTransaction :one
 SELECT hash, runes_transactions.block_height, index, timestamp, inputs, outputs, mints, burns, rune_etched, tx_hash, runes_runestones.block_height, etching, etching_divisibility, etching_premine, etching_rune, etching_spacers, etching_symbol, etching_terms, etching_terms_amount, etching_terms_cap, etching_terms_height_start, etching_terms_height_end, etching_terms_offset_start, etching_terms_offset_end, etching_turbo, edicts, mint, pointer, cenotaph, flaws FROM runes_transactions
-  LEFT JOIN runes_runestones ON runes_transactions.hash = runes_runestones.tx_hash
-  WHERE hash = $1 LIMIT 1
+  LEFT JOIN runes_runestones ON runes_transactions.hash = runes_runestones.tx_hash AND runes_transactions.block_height = runes_runestones.block_height
+  WHERE hash = $1 AND runes_transactions.block_height = (SELECT runes_transaction_heights.block_height FROM runes_transaction_heights WHERE runes_transaction_heights.hash = $1) LIMIT 1
 `
 
 type GetRuneTransactionRow struct {
@@ -945,6 +1416,7 @@ type GetRuneTransactionRow struct {
 	Flaws                   pgtype.Int4
 }
 
+// the block height is looked up first, so that only the partition of the transaction is scanned
 func (q *Queries) GetRuneTransaction(ctx context.Context, hash string) (GetRuneTransactionRow, error) {
 	row := q.db.QueryRow(ctx, getRuneTransaction, hash)
 	var i GetRuneTransactionRow
@@ -988,17 +1460,22 @@ SELECT hash, runes_transactions.block_height, index, timestamp, inputs, outputs,
 	LEFT JOIN runes_runestones ON runes_transactions.hash = runes_runestones.tx_hash
 	WHERE (
     $3::BOOLEAN = FALSE -- if @filter_pk_script is TRUE, apply pk_script filter
-    OR runes_transactions.outputs @> $4::JSONB 
-    OR runes_transactions.inputs @> $4::JSONB
+    OR runes_transactions.hash IN (
+      SELECT runes_transaction_io.tx_hash FROM runes_transaction_io
+        WHERE runes_transaction_io.pkscript = $4::TEXT AND $5 <= runes_transaction_io.block_height AND runes_transaction_io.block_height <= $6
+    )
+  ) AND (
+    $7::BOOLEAN = FALSE -- if @filter_rune_id is TRUE, apply rune_id filter
+    OR runes_transactions.hash IN (
+      SELECT runes_transaction_io.tx_hash FROM runes_transaction_io
+        WHERE runes_transaction_io.rune_id = $8::TEXT AND $5 <= runes_transaction_io.block_height AND runes_transaction_io.block_height <= $6
+    )
+    OR (runes_transactions.rune_etched = TRUE AND runes_transactions.block_height = $9 AND runes_transactions.index = $10)
   ) AND (
-    $5::BOOLEAN = FALSE -- if @filter_rune_id is TRUE, apply rune_id filter
-    OR runes_transactions.outputs @> $6::JSONB 
-    OR runes_transactions.inputs @> $6::JSONB 
-    OR runes_transactions.mints ? $7 
-    OR runes_transactions.burns ? $7
-    OR (runes_transactions.rune_etched = TRUE AND runes_transactions.block_height = $8 AND runes_transactions.index = $9)
+    $5 <= runes_transactions.block_height AND runes_transactions.block_height <= $6
   ) AND (
-    $10 <= runes_transactions.block_height AND runes_transactions.block_height <= $11
+    $11::BOOLEAN = FALSE -- if @use_cursor is TRUE, only return transactions before the cursor
+    OR (runes_transactions.block_height, runes_transactions.index) < ($12::INTEGER, $13::INTEGER)
   )
 ORDER BY runes_transactions.block_height DESC, runes_transactions.index DESC LIMIT $1 OFFSET $2
 `
@@ -1007,14 +1484,16 @@ type GetRuneTransactionsParams struct {
 	Limit             int32
 	Offset            int32
 	FilterPkScript    bool
-	PkScriptParam     []byte
+	PkScript          string
+	FromBlock         int32
+	ToBlock           int32
 	FilterRuneID      bool
-	RuneIDParam       []byte
-	RuneID            []byte
+	RuneID            string
 	RuneIDBlockHeight int32
 	RuneIDTxIndex     int32
-	FromBlock         int32
-	ToBlock           int32
+	UseCursor         bool
+	CursorBlockHeight int32
+	CursorIndex       int32
 }
 
 type GetRuneTransactionsRow struct {
@@ -1055,14 +1534,16 @@ func (q *Queries) GetRuneTransactions(ctx context.Context, arg GetRuneTransactio
 		arg.Limit,
 		arg.Offset,
 		arg.FilterPkScript,
-		arg.PkScriptParam,
+		arg.PkScript,
+		arg.FromBlock,
+		arg.ToBlock,
 		arg.FilterRuneID,
-		arg.RuneIDParam,
 		arg.RuneID,
 		arg.RuneIDBlockHeight,
 		arg.RuneIDTxIndex,
-		arg.FromBlock,
-		arg.ToBlock,
+		arg.UseCursor,
+		arg.CursorBlockHeight,
+		arg.CursorIndex,
 	)
 	if err != nil {
 		return nil, err
@@ -1113,23 +1594,120 @@ func (q *Queries) GetRuneTransactions(ctx context.Context, arg GetRuneTransactio
 	return items, nil
 }
 
+const getRuneTransactionsByHashes = `-- name: GetRuneTransactionsByHashes :many
+SELECT hash, runes_transactions.block_height, index, timestamp, inputs, outputs, mints, burns, rune_etched, tx_hash, runes_runestones.block_height, etching, etching_divisibility, etching_premine, etching_rune, etching_spacers, etching_symbol, etching_terms, etching_terms_amount, etching_terms_cap, etching_terms_height_start, etching_terms_height_end, etching_terms_offset_start, etching_terms_offset_end, etching_turbo, edicts, mint, pointer, cenotaph, flaws FROM runes_transactions
+  LEFT JOIN runes_runestones ON runes_transactions.hash = runes_runestones.tx_hash AND runes_transactions.block_height = runes_runestones.block_height
+  WHERE (runes_transactions.hash, runes_transactions.block_height) IN (
+    SELECT runes_transaction_heights.hash, runes_transaction_heights.block_height FROM runes_transaction_heights WHERE runes_transaction_heights.hash = ANY($1::TEXT[])
+  )
+`
+
+type GetRuneTransactionsByHashesRow struct {
+	Hash                    string
+	BlockHeight             int32
+	Index                   int32
+	Timestamp               pgtype.Timestamp
+	Inputs                  []byte
+	Outputs                 []byte
+	Mints                   []byte
+	Burns                   []byte
+	RuneEtched              bool
+	TxHash                  pgtype.Text
+	BlockHeight_2           pgtype.Int4
+	Etching                 pgtype.Bool
+	EtchingDivisibility     pgtype.Int2
+	EtchingPremine          pgtype.Numeric
+	EtchingRune             pgtype.Text
+	EtchingSpacers          pgtype.Int4
+	EtchingSymbol           pgtype.Int4
+	EtchingTerms            pgtype.Bool
+	EtchingTermsAmount      pgtype.Numeric
+	EtchingTermsCap         pgtype.Numeric
+	EtchingTermsHeightStart pgtype.Int4
+	EtchingTermsHeightEnd   pgtype.Int4
+	EtchingTermsOffsetStart pgtype.Int4
+	EtchingTermsOffsetEnd   pgtype.Int4
+	EtchingTurbo            pgtype.Bool
+	Edicts                  []byte
+	Mint                    pgtype.Text
+	Pointer                 pgtype.Int4
+	Cenotaph                pgtype.Bool
+	Flaws                   pgtype.Int4
+}
+
+// the block heights are looked up first, so that only the partitions of the transactions are scanned
+func (q *Queries) GetRuneTransactionsByHashes(ctx context.Context, hashes []string) ([]GetRuneTransactionsByHashesRow, error) {
+	rows, err := q.db.Query(ctx, getRuneTransactionsByHashes, hashes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRuneTransactionsByHashesRow
+	for rows.Next() {
+		var i GetRuneTransactionsByHashesRow
+		if err := rows.Scan(
+			&i.Hash,
+			&i.BlockHeight,
+			&i.Index,
+			&i.Timestamp,
+			&i.Inputs,
+			&i.Outputs,
+			&i.Mints,
+			&i.Burns,
+			&i.RuneEtched,
+			&i.TxHash,
+			&i.BlockHeight_2,
+			&i.Etching,
+			&i.EtchingDivisibility,
+			&i.EtchingPremine,
+			&i.EtchingRune,
+			&i.EtchingSpacers,
+			&i.EtchingSymbol,
+			&i.EtchingTerms,
+			&i.EtchingTermsAmount,
+			&i.EtchingTermsCap,
+			&i.EtchingTermsHeightStart,
+			&i.EtchingTermsHeightEnd,
+			&i.EtchingTermsOffsetStart,
+			&i.EtchingTermsOffsetEnd,
+			&i.EtchingTurbo,
+			&i.Edicts,
+			&i.Mint,
+			&i.Pointer,
+			&i.Cenotaph,
+			&i.Flaws,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRunesUTXOsByPkScript = `-- name: GetRunesUTXOsByPkScript :many
-SELECT tx_hash, tx_idx, max("pkscript") as pkscript, array_agg("rune_id") as rune_ids, array_agg("amount") as amounts 
+SELECT tx_hash, tx_idx, max("pkscript") as pkscript, array_agg("rune_id") as rune_ids, array_agg("amount") as amounts, max("value") as value 
   FROM runes_outpoint_balances 
   WHERE
     pkscript = $3 AND
     block_height <= $4 AND
-    (spent_height IS NULL OR spent_height > $4)
+    (spent_height IS NULL OR spent_height > $4) AND
+    ($5::BOOLEAN = FALSE OR (tx_hash, tx_idx) > ($6::TEXT, $7::INTEGER)) -- if @use_cursor is TRUE, only return outpoints after the cursor
   GROUP BY tx_hash, tx_idx
   ORDER BY tx_hash, tx_idx 
   LIMIT $1 OFFSET $2
 `
 
 type GetRunesUTXOsByPkScriptParams struct {
-	Limit       int32
-	Offset      int32
-	Pkscript    string
-	BlockHeight int32
+	Limit        int32
+	Offset       int32
+	Pkscript     string
+	BlockHeight  int32
+	UseCursor    bool
+	CursorTxHash string
+	CursorTxIdx  int32
 }
 
 type GetRunesUTXOsByPkScriptRow struct {
@@ -1138,6 +1716,7 @@ type GetRunesUTXOsByPkScriptRow struct {
 	Pkscript interface{}
 	RuneIds  interface{}
 	Amounts  interface{}
+	Value    interface{}
 }
 
 func (q *Queries) GetRunesUTXOsByPkScript(ctx context.Context, arg GetRunesUTXOsByPkScriptParams) ([]GetRunesUTXOsByPkScriptRow, error) {
@@ -1146,6 +1725,9 @@ func (q *Queries) GetRunesUTXOsByPkScript(ctx context.Context, arg GetRunesUTXOs
 		arg.Offset,
 		arg.Pkscript,
 		arg.BlockHeight,
+		arg.UseCursor,
+		arg.CursorTxHash,
+		arg.CursorTxIdx,
 	)
 	if err != nil {
 		return nil, err
@@ -1160,6 +1742,7 @@ func (q *Queries) GetRunesUTXOsByPkScript(ctx context.Context, arg GetRunesUTXOs
 			&i.Pkscript,
 			&i.RuneIds,
 			&i.Amounts,
+			&i.Value,
 		); err != nil {
 			return nil, err
 		}
@@ -1172,24 +1755,28 @@ func (q *Queries) GetRunesUTXOsByPkScript(ctx context.Context, arg GetRunesUTXOs
 }
 
 const getRunesUTXOsByRuneIdAndPkScript = `-- name: GetRunesUTXOsByRuneIdAndPkScript :many
-SELECT tx_hash, tx_idx, max("pkscript") as pkscript, array_agg("rune_id") as rune_ids, array_agg("amount") as amounts 
+SELECT tx_hash, tx_idx, max("pkscript") as pkscript, array_agg("rune_id") as rune_ids, array_agg("amount") as amounts, max("value") as value 
   FROM runes_outpoint_balances 
   WHERE
     pkscript = $3 AND 
     block_height <= $4 AND 
-    (spent_height IS NULL OR spent_height > $4)
+    (spent_height IS NULL OR spent_height > $4) AND
+    ($5::BOOLEAN = FALSE OR (tx_hash, tx_idx) > ($6::TEXT, $7::INTEGER)) -- if @use_cursor is TRUE, only return outpoints after the cursor
   GROUP BY tx_hash, tx_idx
-  HAVING array_agg("rune_id") @> $5::text[] 
+  HAVING array_agg("rune_id") @> $8::text[] 
   ORDER BY tx_hash, tx_idx 
   LIMIT $1 OFFSET $2
 `
 
 type GetRunesUTXOsByRuneIdAndPkScriptParams struct {
-	Limit       int32
-	Offset      int32
-	Pkscript    string
-	BlockHeight int32
-	RuneIds     []string
+	Limit        int32
+	Offset       int32
+	Pkscript     string
+	BlockHeight  int32
+	UseCursor    bool
+	CursorTxHash string
+	CursorTxIdx  int32
+	RuneIds      []string
 }
 
 type GetRunesUTXOsByRuneIdAndPkScriptRow struct {
@@ -1198,6 +1785,7 @@ type GetRunesUTXOsByRuneIdAndPkScriptRow struct {
 	Pkscript interface{}
 	RuneIds  interface{}
 	Amounts  interface{}
+	Value    interface{}
 }
 
 func (q *Queries) GetRunesUTXOsByRuneIdAndPkScript(ctx context.Context, arg GetRunesUTXOsByRuneIdAndPkScriptParams) ([]GetRunesUTXOsByRuneIdAndPkScriptRow, error) {
@@ -1206,6 +1794,9 @@ func (q *Queries) GetRunesUTXOsByRuneIdAndPkScript(ctx context.Context, arg GetR
 		arg.Offset,
 		arg.Pkscript,
 		arg.BlockHeight,
+		arg.UseCursor,
+		arg.CursorTxHash,
+		arg.CursorTxIdx,
 		arg.RuneIds,
 	)
 	if err != nil {
@@ -1221,6 +1812,7 @@ func (q *Queries) GetRunesUTXOsByRuneIdAndPkScript(ctx context.Context, arg GetR
 			&i.Pkscript,
 			&i.RuneIds,
 			&i.Amounts,
+			&i.Value,
 		); err != nil {
 			return nil, err
 		}
@@ -1269,6 +1861,58 @@ func (q *Queries) GetTotalHoldersByRuneIds(ctx context.Context, arg GetTotalHold
 	return items, nil
 }
 
+const pruneOutPointSpends = `-- name: PruneOutPointSpends :exec
+DELETE FROM runes_outpoint_spends WHERE spent_height < $1
+`
+
+func (q *Queries) PruneOutPointSpends(ctx context.Context, prunedHeight int32) error {
+	_, err := q.db.Exec(ctx, pruneOutPointSpends, prunedHeight)
+	return err
+}
+
+const pruneRuneBalances = `-- name: PruneRuneBalances :execrows
+DELETE FROM runes_balances AS b WHERE b.block_height < $1 AND EXISTS (
+  SELECT 1 FROM runes_balances AS newer WHERE newer.pkscript = b.pkscript AND newer.rune_id = b.rune_id AND newer.block_height > b.block_height AND newer.block_height <= $1
+)
+`
+
+func (q *Queries) PruneRuneBalances(ctx context.Context, prunedHeight int32) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneRuneBalances, prunedHeight)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const pruneSpentOutPointBalances = `-- name: PruneSpentOutPointBalances :execrows
+DELETE FROM runes_outpoint_balances WHERE spent_height < $1
+`
+
+func (q *Queries) PruneSpentOutPointBalances(ctx context.Context, prunedHeight pgtype.Int4) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneSpentOutPointBalances, prunedHeight)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreCurrentBalancesBeforeHeight = `-- name: RestoreCurrentBalancesBeforeHeight :exec
+INSERT INTO runes_current_balances (pkscript, rune_id, amount, block_height)
+SELECT latest.pkscript, latest.rune_id, latest.amount, latest.block_height FROM (
+  SELECT DISTINCT ON (runes_balances.pkscript, runes_balances.rune_id) runes_balances.pkscript, runes_balances.rune_id, runes_balances.amount, runes_balances.block_height FROM runes_balances
+    WHERE runes_balances.block_height < $1 AND (runes_balances.pkscript, runes_balances.rune_id) IN (
+      SELECT DISTINCT changed.pkscript, changed.rune_id FROM runes_balances AS changed WHERE changed.block_height >= $1
+    )
+    ORDER BY runes_balances.pkscript, runes_balances.rune_id, runes_balances.block_height DESC
+) AS latest WHERE latest.amount > 0
+`
+
+// restore current balances of all pkscript and rune pairs that changed since the given height from the latest balance before that height
+func (q *Queries) RestoreCurrentBalancesBeforeHeight(ctx context.Context, blockHeight int32) error {
+	_, err := q.db.Exec(ctx, restoreCurrentBalancesBeforeHeight, blockHeight)
+	return err
+}
+
 const spendOutPointBalance = `-- name: SpendOutPointBalance :exec
 UPDATE runes_outpoint_balances SET spent_height = $1 WHERE tx_hash = $2 AND tx_idx = $3
 `
@@ -1285,10 +1929,15 @@ func (q *Queries) SpendOutPointBalance(ctx context.Context, arg SpendOutPointBal
 }
 
 const unspendOutPointBalancesSinceHeight = `-- name: UnspendOutPointBalancesSinceHeight :exec
-UPDATE runes_outpoint_balances SET spent_height = NULL WHERE spent_height >= $1
+UPDATE runes_outpoint_balances SET spent_height = NULL, spent_tx_hash = NULL, spent_tx_input_idx = NULL
+  FROM runes_outpoint_spends AS spent
+  WHERE spent.spent_height >= $1
+    AND runes_outpoint_balances.tx_hash = spent.tx_hash AND runes_outpoint_balances.tx_idx = spent.tx_idx AND runes_outpoint_balances.block_height = spent.block_height
+    AND runes_outpoint_balances.block_height >= (SELECT COALESCE(MIN(block_height), $1) FROM runes_outpoint_spends WHERE runes_outpoint_spends.spent_height >= $1)
 `
 
-func (q *Queries) UnspendOutPointBalancesSinceHeight(ctx context.Context, spentHeight pgtype.Int4) error {
+// the outpoints and their creation heights are looked up from the spends, so that only the partitions of the spent outpoints are scanned
+func (q *Queries) UnspendOutPointBalancesSinceHeight(ctx context.Context, spentHeight int32) error {
 	_, err := q.db.Exec(ctx, unspendOutPointBalancesSinceHeight, spentHeight)
 	return err
 }
@@ -15,6 +15,13 @@ type RunesBalance struct {
 	Amount      pgtype.Numeric
 }
 
+type RunesCurrentBalance struct {
+	Pkscript    string
+	RuneID      string
+	Amount      pgtype.Numeric
+	BlockHeight int32
+}
+
 type RunesEntry struct {
 	RuneID           string
 	Number           int64
@@ -68,13 +75,29 @@ type RunesIndexerState struct {
 }
 
 type RunesOutpointBalance struct {
-	RuneID      string
-	Pkscript    string
+	RuneID          string
+	Pkscript        string
+	TxHash          string
+	TxIdx           int32
+	Amount          pgtype.Numeric
+	BlockHeight     int32
+	SpentHeight     pgtype.Int4
+	SpentTxHash     pgtype.Text
+	SpentTxInputIdx pgtype.Int4
+	Value           pgtype.Int8
+}
+
+type RunesOutpointSpend struct {
 	TxHash      string
 	TxIdx       int32
-	Amount      pgtype.Numeric
 	BlockHeight int32
-	SpentHeight pgtype.Int4
+	SpentHeight int32
+}
+
+type RunesPruneState struct {
+	Id           int64
+	PrunedHeight int32
+	CreatedAt    pgtype.Timestamptz
 }
 
 type RunesRunestone struct {
@@ -112,3 +135,19 @@ type RunesTransaction struct {
 	Burns       []byte
 	RuneEtched  bool
 }
+
+type RunesTransactionHeight struct {
+	Hash        string
+	BlockHeight int32
+}
+
+type RunesTransactionIo struct {
+	TxHash      string
+	BlockHeight int32
+	TxIndex     int32
+	IoType      string
+	IoIndex     int32
+	Pkscript    pgtype.Text
+	RuneID      string
+	Amount      pgtype.Numeric
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/repository/postgres/gen"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ datagateway.IntegrityDataGateway = (*Repository)(nil)
+
+func (r *Repository) GetBalanceMismatches(ctx context.Context, blockHeight uint64) ([]*entity.BalanceMismatch, error) {
+	rows, err := r.queries.GetBalanceMismatches(ctx, int32(blockHeight))
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	mismatches := make([]*entity.BalanceMismatch, 0, len(rows))
+	for _, row := range rows {
+		mismatch, err := mapBalanceMismatchModelToType(row)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse balance mismatch model")
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	return mismatches, nil
+}
+
+func (r *Repository) GetSupplyMismatches(ctx context.Context, blockHeight uint64) ([]*entity.SupplyMismatch, error) {
+	rows, err := r.queries.GetSupplyMismatches(ctx, int32(blockHeight))
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	mismatches := make([]*entity.SupplyMismatch, 0, len(rows))
+	for _, row := range rows {
+		mismatch, err := mapSupplyMismatchModelToType(row)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse supply mismatch model")
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	return mismatches, nil
+}
+
+func (r *Repository) GetIndexedBlocksInRange(ctx context.Context, fromHeight, toHeight int64) ([]*entity.IndexedBlock, error) {
+	rows, err := r.queries.GetIndexedBlocksInRange(ctx, gen.GetIndexedBlocksInRangeParams{
+		FromHeight: int32(fromHeight),
+		ToHeight:   int32(toHeight),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	indexedBlocks := make([]*entity.IndexedBlock, 0, len(rows))
+	for _, row := range rows {
+		indexedBlock, err := mapIndexedBlockModelToType(row)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse indexed block model")
+		}
+		indexedBlocks = append(indexedBlocks, indexedBlock)
+	}
+	return indexedBlocks, nil
+}
+
+func (r *Repository) GetBlockEventData(ctx context.Context, blockHeight uint64) (*entity.BlockEventData, error) {
+	newRuneEntries, err := r.getRuneEntriesEtchedAtHeight(ctx, blockHeight)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	newRuneEntryStates, err := r.getRuneEntryStatesAtHeight(ctx, blockHeight)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	newOutPointBalanceModels, err := r.queries.GetOutPointBalancesCreatedAtHeight(ctx, int32(blockHeight))
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+	newOutPointBalances, err := mapOutPointBalanceModelsToTypes(newOutPointBalanceModels)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	spentOutPointBalanceModels, err := r.queries.GetOutPointBalancesSpentAtHeight(ctx, pgtype.Int4{Int32: int32(blockHeight), Valid: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+	spentOutPointBalances, err := mapOutPointBalanceModelsToTypes(spentOutPointBalanceModels)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	balanceModels, err := r.queries.GetRuneBalancesAtHeight(ctx, int32(blockHeight))
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+	newBalances := make([]*entity.Balance, 0, len(balanceModels))
+	for _, balanceModel := range balanceModels {
+		balance, err := mapBalanceModelToType(balanceModel)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse balance model")
+		}
+		newBalances = append(newBalances, balance)
+	}
+
+	runeTxRows, err := r.queries.GetRuneTransactionsAtHeight(ctx, int32(blockHeight))
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+	runeTxs := make([]*entity.RuneTransaction, 0, len(runeTxRows))
+	for _, row := range runeTxRows {
+		runeTxModel, runestoneModel, err := extractModelRuneTxAndRunestone(gen.GetRuneTransactionsRow(row))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to extract rune transaction and runestone from row")
+		}
+
+		runeTx, err := mapRuneTransactionModelToType(runeTxModel)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse rune transaction model")
+		}
+		if runestoneModel != nil {
+			runestone, err := mapRunestoneModelToType(*runestoneModel)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse runestone model")
+			}
+			runeTx.Runestone = &runestone
+		}
+		runeTxs = append(runeTxs, &runeTx)
+	}
+
+	return &entity.BlockEventData{
+		NewRuneEntries:        newRuneEntries,
+		NewRuneEntryStates:    newRuneEntryStates,
+		NewOutPointBalances:   newOutPointBalances,
+		SpentOutPointBalances: spentOutPointBalances,
+		NewBalances:           newBalances,
+		RuneTransactions:      runeTxs,
+	}, nil
+}
+
+func (r *Repository) getRuneEntriesEtchedAtHeight(ctx context.Context, blockHeight uint64) ([]*runes.RuneEntry, error) {
+	rows, err := r.queries.GetRuneEntriesEtchedAtHeight(ctx, int32(blockHeight))
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	runeEntries := make([]*runes.RuneEntry, 0, len(rows))
+	for _, row := range rows {
+		runeEntry, err := mapRuneEntryModelToType(gen.GetRuneEntriesRow(row))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse rune entry model")
+		}
+		runeEntries = append(runeEntries, &runeEntry)
+	}
+	return runeEntries, nil
+}
+
+func (r *Repository) getRuneEntryStatesAtHeight(ctx context.Context, blockHeight uint64) ([]*runes.RuneEntry, error) {
+	rows, err := r.queries.GetRuneEntryStatesAtHeight(ctx, int32(blockHeight))
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	runeEntries := make([]*runes.RuneEntry, 0, len(rows))
+	for _, row := range rows {
+		runeEntry, err := mapRuneEntryModelToType(gen.GetRuneEntriesRow(row))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse rune entry model")
+		}
+		runeEntries = append(runeEntries, &runeEntry)
+	}
+	return runeEntries, nil
+}
@@ -418,6 +418,58 @@ func mapRuneTransactionTypeToParamsBatch(srcs []*entity.RuneTransaction) (gen.Ba
 	return batchParams, nil
 }
 
+const (
+	txIOTypeInput  = "input"
+	txIOTypeOutput = "output"
+	txIOTypeMint   = "mint"
+	txIOTypeBurn   = "burn"
+)
+
+// mapRuneTransactionIOTypeToParamsBatch flattens inputs, outputs, mints and burns of the given transactions into rows of runes_transaction_io.
+func mapRuneTransactionIOTypeToParamsBatch(srcs []*entity.RuneTransaction) (gen.BatchCreateRuneTransactionIOPatchedParams, error) {
+	var batchParams gen.BatchCreateRuneTransactionIOPatchedParams
+	appendRow := func(src *entity.RuneTransaction, ioType string, ioIndex uint32, pkScript pgtype.Text, runeId runes.RuneId, amount uint128.Uint128) error {
+		amountNumeric, err := numericFromUint128(&amount)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse amount")
+		}
+		batchParams.TxHashArr = append(batchParams.TxHashArr, src.Hash.String())
+		batchParams.BlockHeightArr = append(batchParams.BlockHeightArr, int32(src.BlockHeight))
+		batchParams.TxIndexArr = append(batchParams.TxIndexArr, int32(src.Index))
+		batchParams.IoTypeArr = append(batchParams.IoTypeArr, ioType)
+		batchParams.IoIndexArr = append(batchParams.IoIndexArr, int32(ioIndex))
+		batchParams.PkscriptArr = append(batchParams.PkscriptArr, pkScript)
+		batchParams.RuneIDArr = append(batchParams.RuneIDArr, runeId.String())
+		batchParams.AmountArr = append(batchParams.AmountArr, amountNumeric)
+		return nil
+	}
+
+	for i, src := range srcs {
+		for _, input := range src.Inputs {
+			if err := appendRow(src, txIOTypeInput, input.Index, pgtype.Text{String: hex.EncodeToString(input.PkScript), Valid: true}, input.RuneId, input.Amount); err != nil {
+				return gen.BatchCreateRuneTransactionIOPatchedParams{}, errors.Wrapf(err, "failed to map input to params batch at index %d", i)
+			}
+		}
+		for _, output := range src.Outputs {
+			if err := appendRow(src, txIOTypeOutput, output.Index, pgtype.Text{String: hex.EncodeToString(output.PkScript), Valid: true}, output.RuneId, output.Amount); err != nil {
+				return gen.BatchCreateRuneTransactionIOPatchedParams{}, errors.Wrapf(err, "failed to map output to params batch at index %d", i)
+			}
+		}
+		for runeId, amount := range src.Mints {
+			if err := appendRow(src, txIOTypeMint, 0, pgtype.Text{}, runeId, amount); err != nil {
+				return gen.BatchCreateRuneTransactionIOPatchedParams{}, errors.Wrapf(err, "failed to map mint to params batch at index %d", i)
+			}
+		}
+		for runeId, amount := range src.Burns {
+			if err := appendRow(src, txIOTypeBurn, 0, pgtype.Text{}, runeId, amount); err != nil {
+				return gen.BatchCreateRuneTransactionIOPatchedParams{}, errors.Wrapf(err, "failed to map burn to params batch at index %d", i)
+			}
+		}
+	}
+
+	return batchParams, nil
+}
+
 func extractModelRuneTxAndRunestone(src gen.GetRuneTransactionsRow) (gen.RunesTransaction, *gen.RunesRunestone, error) {
 	var runestone *gen.RunesRunestone
 	if src.TxHash.Valid {
@@ -773,6 +825,74 @@ func mapBalanceModelToType(src gen.RunesBalance) (*entity.Balance, error) {
 	}, nil
 }
 
+func mapBalanceHistoryRowToType(src gen.GetBalanceHistoryByPkScriptRow) (*entity.BalanceChange, error) {
+	runeId, err := runes.NewRuneIdFromString(src.RuneID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse rune id")
+	}
+	amount, err := uint128FromNumeric(src.Amount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse balance")
+	}
+	previousAmount, err := uint128FromNumeric(src.PreviousAmount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse previous balance")
+	}
+	var timestamp time.Time
+	if src.Timestamp.Valid {
+		timestamp = src.Timestamp.Time.UTC()
+	}
+	return &entity.BalanceChange{
+		RuneId:         runeId,
+		BlockHeight:    uint64(src.BlockHeight),
+		Timestamp:      timestamp,
+		Amount:         lo.FromPtr(amount),
+		PreviousAmount: lo.FromPtr(previousAmount),
+	}, nil
+}
+
+func mapRuneEntryStateHistoryRowToType(src gen.GetRuneEntryStateHistoryRow) (*entity.RuneEntryState, error) {
+	mints, err := uint128FromNumeric(src.Mints)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse mints")
+	}
+	burnedAmount, err := uint128FromNumeric(src.BurnedAmount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse burned amount")
+	}
+	var timestamp time.Time
+	if src.Timestamp.Valid {
+		timestamp = src.Timestamp.Time.UTC()
+	}
+	return &entity.RuneEntryState{
+		BlockHeight:  uint64(src.BlockHeight),
+		Timestamp:    timestamp,
+		Mints:        lo.FromPtr(mints),
+		BurnedAmount: lo.FromPtr(burnedAmount),
+		HoldersCount: src.HoldersCount,
+	}, nil
+}
+
+func mapHolderChangeRowToType(src gen.GetHolderChangesByRuneIdRow) (*entity.HolderChange, error) {
+	pkScript, err := hex.DecodeString(src.Pkscript)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse pkscript")
+	}
+	fromAmount, err := uint128FromNumeric(src.FromAmount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse from amount")
+	}
+	toAmount, err := uint128FromNumeric(src.ToAmount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse to amount")
+	}
+	return &entity.HolderChange{
+		PkScript:   pkScript,
+		FromAmount: lo.FromPtr(fromAmount),
+		ToAmount:   lo.FromPtr(toAmount),
+	}, nil
+}
+
 func mapBalanceTypeToParams(src entity.Balance) (gen.CreateRuneBalanceParams, error) {
 	amount, err := numericFromUint128(&src.Amount)
 	if err != nil {
@@ -809,6 +929,40 @@ func mapBalanceTypeToParamsBatch(srcs []*entity.Balance) (gen.BatchCreateRunesBa
 	return batchParams, nil
 }
 
+func mapCurrentBalanceModelToType(src gen.RunesCurrentBalance) (*entity.Balance, error) {
+	return mapBalanceModelToType(gen.RunesBalance{
+		Pkscript:    src.Pkscript,
+		BlockHeight: src.BlockHeight,
+		RuneID:      src.RuneID,
+		Amount:      src.Amount,
+	})
+}
+
+// mapCurrentBalanceTypeToParamsBatch splits the given balances into non-zero balances to upsert and zero balances to delete.
+func mapCurrentBalanceTypeToParamsBatch(srcs []*entity.Balance) (gen.BatchUpsertCurrentBalancesParams, gen.BatchDeleteCurrentBalancesParams, error) {
+	var upsertParams gen.BatchUpsertCurrentBalancesParams
+	var deleteParams gen.BatchDeleteCurrentBalancesParams
+
+	for i, src := range srcs {
+		param, err := mapBalanceTypeToParams(*src)
+		if err != nil {
+			return gen.BatchUpsertCurrentBalancesParams{}, gen.BatchDeleteCurrentBalancesParams{}, errors.Wrapf(err, "failed to map current balance to params batch at index %d", i)
+		}
+
+		if src.Amount.IsZero() {
+			deleteParams.PkscriptArr = append(deleteParams.PkscriptArr, param.Pkscript)
+			deleteParams.RuneIDArr = append(deleteParams.RuneIDArr, param.RuneID)
+			continue
+		}
+		upsertParams.PkscriptArr = append(upsertParams.PkscriptArr, param.Pkscript)
+		upsertParams.RuneIDArr = append(upsertParams.RuneIDArr, param.RuneID)
+		upsertParams.AmountArr = append(upsertParams.AmountArr, param.Amount)
+		upsertParams.BlockHeightArr = append(upsertParams.BlockHeightArr, param.BlockHeight)
+	}
+
+	return upsertParams, deleteParams, nil
+}
+
 func mapIndexedBlockModelToType(src gen.RunesIndexedBlock) (*entity.IndexedBlock, error) {
 	hash, err := chainhash.NewHashFromStr(src.Hash)
 	if err != nil {
@@ -845,69 +999,80 @@ func mapIndexedBlockTypeToParams(src entity.IndexedBlock) (gen.CreateIndexedBloc
 	}, nil
 }
 
-func mapRunesUTXOModelToType(src gen.GetRunesUTXOsByPkScriptRow) (entity.RunesUTXO, error) {
+func mapRunesUTXOModelToType(src gen.GetRunesUTXOsByPkScriptRow) (entity.RunesUTXOWithValue, error) {
 	pkScriptRaw, ok := src.Pkscript.(string)
 	if !ok {
-		return entity.RunesUTXO{}, errors.New("pkscript from database is not string")
+		return entity.RunesUTXOWithValue{}, errors.New("pkscript from database is not string")
 	}
 	pkScript, err := hex.DecodeString(pkScriptRaw)
 	if err != nil {
-		return entity.RunesUTXO{}, errors.Wrap(err, "failed to parse pkscript")
+		return entity.RunesUTXOWithValue{}, errors.Wrap(err, "failed to parse pkscript")
 	}
 	txHash, err := chainhash.NewHashFromStr(src.TxHash)
 	if err != nil {
-		return entity.RunesUTXO{}, errors.Wrap(err, "failed to parse tx hash")
+		return entity.RunesUTXOWithValue{}, errors.Wrap(err, "failed to parse tx hash")
 	}
 	runeIdsRaw, ok := src.RuneIds.([]interface{})
 	if !ok {
-		return entity.RunesUTXO{}, errors.New("src.RuneIds is not a slice")
+		return entity.RunesUTXOWithValue{}, errors.New("src.RuneIds is not a slice")
 	}
 	runeIds := make([]string, 0, len(runeIdsRaw))
 	for i, raw := range runeIdsRaw {
 		runeId, ok := raw.(string)
 		if !ok {
-			return entity.RunesUTXO{}, errors.Errorf("src.RuneIds[%d] is not a string", i)
+			return entity.RunesUTXOWithValue{}, errors.Errorf("src.RuneIds[%d] is not a string", i)
 		}
 		runeIds = append(runeIds, runeId)
 	}
 	amountsRaw, ok := src.Amounts.([]interface{})
 	if !ok {
-		return entity.RunesUTXO{}, errors.New("amounts from database is not a slice")
+		return entity.RunesUTXOWithValue{}, errors.New("amounts from database is not a slice")
 	}
 	amounts := make([]pgtype.Numeric, 0, len(amountsRaw))
 	for i, raw := range amountsRaw {
 		amount, ok := raw.(pgtype.Numeric)
 		if !ok {
-			return entity.RunesUTXO{}, errors.Errorf("src.Amounts[%d] is not pgtype.Numeric", i)
+			return entity.RunesUTXOWithValue{}, errors.Errorf("src.Amounts[%d] is not pgtype.Numeric", i)
 		}
 		amounts = append(amounts, amount)
 	}
 	if len(runeIds) != len(amounts) {
-		return entity.RunesUTXO{}, errors.New("rune ids and amounts have different lengths")
+		return entity.RunesUTXOWithValue{}, errors.New("rune ids and amounts have different lengths")
 	}
 
 	runesBalances := make([]entity.RunesUTXOBalance, 0, len(runeIds))
 	for i := range runeIds {
 		runeId, err := runes.NewRuneIdFromString(runeIds[i])
 		if err != nil {
-			return entity.RunesUTXO{}, errors.Wrap(err, "failed to parse rune id")
+			return entity.RunesUTXOWithValue{}, errors.Wrap(err, "failed to parse rune id")
 		}
 		amount, err := uint128FromNumeric(amounts[i])
 		if err != nil {
-			return entity.RunesUTXO{}, errors.Wrap(err, "failed to parse amount")
+			return entity.RunesUTXOWithValue{}, errors.Wrap(err, "failed to parse amount")
 		}
 		runesBalances = append(runesBalances, entity.RunesUTXOBalance{
 			RuneId: runeId,
 			Amount: lo.FromPtr(amount),
 		})
 	}
-	return entity.RunesUTXO{
-		PkScript: pkScript,
-		OutPoint: wire.OutPoint{
-			Hash:  *txHash,
-			Index: uint32(src.TxIdx),
+	var value *int64
+	if src.Value != nil {
+		valueRaw, ok := src.Value.(int64)
+		if !ok {
+			return entity.RunesUTXOWithValue{}, errors.New("value from database is not int64")
+		}
+		value = &valueRaw
+	}
+	return entity.RunesUTXOWithValue{
+		RunesUTXO: entity.RunesUTXO{
+			PkScript: pkScript,
+			OutPoint: wire.OutPoint{
+				Hash:  *txHash,
+				Index: uint32(src.TxIdx),
+			},
+			RuneBalances: runesBalances,
 		},
-		RuneBalances: runesBalances,
+		Value: value,
 	}, nil
 }
 
@@ -932,6 +1097,21 @@ func mapOutPointBalanceModelToType(src gen.RunesOutpointBalance) (entity.OutPoin
 	if src.SpentHeight.Valid {
 		spentHeight = lo.ToPtr(uint64(src.SpentHeight.Int32))
 	}
+	var spentTxHash *chainhash.Hash
+	if src.SpentTxHash.Valid {
+		spentTxHash, err = chainhash.NewHashFromStr(src.SpentTxHash.String)
+		if err != nil {
+			return entity.OutPointBalance{}, errors.Wrap(err, "failed to parse spent tx hash")
+		}
+	}
+	var spentTxInputIndex *uint32
+	if src.SpentTxInputIdx.Valid {
+		spentTxInputIndex = lo.ToPtr(uint32(src.SpentTxInputIdx.Int32))
+	}
+	var value *int64
+	if src.Value.Valid {
+		value = lo.ToPtr(src.Value.Int64)
+	}
 	return entity.OutPointBalance{
 		PkScript: pkScript,
 		RuneId:   runeId,
@@ -940,11 +1120,26 @@ func mapOutPointBalanceModelToType(src gen.RunesOutpointBalance) (entity.OutPoin
 			Hash:  *txHash,
 			Index: uint32(src.TxIdx),
 		},
-		BlockHeight: uint64(src.BlockHeight),
-		SpentHeight: spentHeight,
+		BlockHeight:       uint64(src.BlockHeight),
+		SpentHeight:       spentHeight,
+		SpentTxHash:       spentTxHash,
+		SpentTxInputIndex: spentTxInputIndex,
+		Value:             value,
 	}, nil
 }
 
+func mapOutPointBalanceModelsToTypes(models []gen.RunesOutpointBalance) ([]*entity.OutPointBalance, error) {
+	outPointBalances := make([]*entity.OutPointBalance, 0, len(models))
+	for _, model := range models {
+		outPointBalance, err := mapOutPointBalanceModelToType(model)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse outpoint balance model")
+		}
+		outPointBalances = append(outPointBalances, &outPointBalance)
+	}
+	return outPointBalances, nil
+}
+
 func mapOutPointBalanceTypeToParams(src entity.OutPointBalance) (gen.CreateOutPointBalanceParams, error) {
 	amount, err := numericFromUint128(&src.Amount)
 	if err != nil {
@@ -954,6 +1149,10 @@ func mapOutPointBalanceTypeToParams(src entity.OutPointBalance) (gen.CreateOutPo
 	if src.SpentHeight != nil {
 		spentHeight = pgtype.Int4{Int32: int32(*src.SpentHeight), Valid: true}
 	}
+	var value pgtype.Int8
+	if src.Value != nil {
+		value = pgtype.Int8{Int64: *src.Value, Valid: true}
+	}
 	return gen.CreateOutPointBalanceParams{
 		TxHash:      src.OutPoint.Hash.String(),
 		TxIdx:       int32(src.OutPoint.Index),
@@ -962,6 +1161,7 @@ func mapOutPointBalanceTypeToParams(src entity.OutPointBalance) (gen.CreateOutPo
 		Amount:      amount,
 		BlockHeight: int32(src.BlockHeight),
 		SpentHeight: spentHeight,
+		Value:       value,
 	}, nil
 }
 
@@ -974,6 +1174,7 @@ func mapOutPointBalanceTypeToParamsBatch(srcs []*entity.OutPointBalance) (gen.Ba
 	batchParams.AmountArr = make([]pgtype.Numeric, 0, len(srcs))
 	batchParams.BlockHeightArr = make([]int32, 0, len(srcs))
 	batchParams.SpentHeightArr = make([]pgtype.Int4, 0, len(srcs))
+	batchParams.ValueArr = make([]pgtype.Int8, 0, len(srcs))
 
 	for i, src := range srcs {
 		param, err := mapOutPointBalanceTypeToParams(*src)
@@ -988,7 +1189,68 @@ func mapOutPointBalanceTypeToParamsBatch(srcs []*entity.OutPointBalance) (gen.Ba
 		batchParams.AmountArr = append(batchParams.AmountArr, param.Amount)
 		batchParams.BlockHeightArr = append(batchParams.BlockHeightArr, param.BlockHeight)
 		batchParams.SpentHeightArr = append(batchParams.SpentHeightArr, param.SpentHeight)
+		batchParams.ValueArr = append(batchParams.ValueArr, param.Value)
 	}
 
 	return batchParams, nil
 }
+
+func mapBalanceMismatchModelToType(src gen.GetBalanceMismatchesRow) (*entity.BalanceMismatch, error) {
+	pkScript, err := hex.DecodeString(src.Pkscript)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse pkscript")
+	}
+	runeId, err := runes.NewRuneIdFromString(src.RuneID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse rune id")
+	}
+	outPointAmount, err := uint128FromNumeric(src.OutpointAmount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse outpoint amount")
+	}
+	balanceAmount, err := uint128FromNumeric(src.BalanceAmount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse balance amount")
+	}
+	return &entity.BalanceMismatch{
+		PkScript:       pkScript,
+		RuneId:         runeId,
+		OutPointAmount: lo.FromPtr(outPointAmount),
+		BalanceAmount:  lo.FromPtr(balanceAmount),
+	}, nil
+}
+
+func mapSupplyMismatchModelToType(src gen.GetSupplyMismatchesRow) (*entity.SupplyMismatch, error) {
+	runeId, err := runes.NewRuneIdFromString(src.RuneID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse rune id")
+	}
+	premine, err := uint128FromNumeric(src.Premine)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse premine")
+	}
+	termsAmount, err := uint128FromNumeric(src.TermsAmount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse terms amount")
+	}
+	mints, err := uint128FromNumeric(src.Mints)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse mints")
+	}
+	burnedAmount, err := uint128FromNumeric(src.BurnedAmount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse burned amount")
+	}
+	circulatingSupply, err := uint128FromNumeric(src.CirculatingSupply)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse circulating supply")
+	}
+	return &entity.SupplyMismatch{
+		RuneId:            runeId,
+		Premine:           lo.FromPtr(premine),
+		TermsAmount:       lo.FromPtr(termsAmount),
+		Mints:             lo.FromPtr(mints),
+		BurnedAmount:      lo.FromPtr(burnedAmount),
+		CirculatingSupply: lo.FromPtr(circulatingSupply),
+	}, nil
+}
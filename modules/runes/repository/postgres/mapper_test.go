@@ -3,6 +3,9 @@ package postgres
 import (
 	"testing"
 
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
 	"github.com/gaze-network/uint128"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
@@ -59,3 +62,35 @@ func TestNumericFromUint128(t *testing.T) {
 		assert.Equal(t, expected, result)
 	})
 }
+
+func TestMapRuneTransactionIOTypeToParamsBatch(t *testing.T) {
+	runeId := runes.RuneId{BlockHeight: 840000, TxIndex: 1}
+	tx := &entity.RuneTransaction{
+		Hash:        chainhash.Hash{1},
+		BlockHeight: 840001,
+		Index:       5,
+		Inputs: []*entity.TxInputOutput{
+			{PkScript: []byte{0x51}, RuneId: runeId, Amount: uint128.From64(100), Index: 0},
+		},
+		Outputs: []*entity.TxInputOutput{
+			{PkScript: []byte{0x52}, RuneId: runeId, Amount: uint128.From64(90), Index: 1},
+		},
+		Mints: map[runes.RuneId]uint128.Uint128{runeId: uint128.From64(10)},
+		Burns: map[runes.RuneId]uint128.Uint128{runeId: uint128.From64(20)},
+	}
+
+	params, err := mapRuneTransactionIOTypeToParamsBatch([]*entity.RuneTransaction{tx})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{txIOTypeInput, txIOTypeOutput, txIOTypeMint, txIOTypeBurn}, params.IoTypeArr)
+	assert.Equal(t, []int32{0, 1, 0, 0}, params.IoIndexArr)
+	assert.Equal(t, []pgtype.Text{{String: "51", Valid: true}, {String: "52", Valid: true}, {}, {}}, params.PkscriptArr)
+	assert.Equal(t, []int32{840001, 840001, 840001, 840001}, params.BlockHeightArr)
+	assert.Equal(t, []int32{5, 5, 5, 5}, params.TxIndexArr)
+	for _, txHash := range params.TxHashArr {
+		assert.Equal(t, tx.Hash.String(), txHash)
+	}
+	for _, id := range params.RuneIDArr {
+		assert.Equal(t, runeId.String(), id)
+	}
+	assert.Len(t, params.AmountArr, 4)
+}
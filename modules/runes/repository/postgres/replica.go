@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/core/types"
+	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/indexer-network/pkg/logger"
+	"github.com/gaze-network/indexer-network/pkg/logger/slogx"
+)
+
+// replicaHeightTTL is how long the latest indexed block height of a database is cached before it is checked again.
+const replicaHeightTTL = 2 * time.Second
+
+var _ datagateway.RunesReaderDataGateway = (*ReplicaRouter)(nil)
+
+// ReplicaRouter routes read queries to read replicas that have indexed the requested block height, and falls back to the primary database otherwise.
+// Queries without a block height are routed to replicas that have caught up with the latest block of the primary.
+// Since heights are cached for replicaHeightTTL, these queries may not see a block indexed by the primary within that duration.
+type ReplicaRouter struct {
+	primary       *Repository
+	primaryHeight *heightTracker
+	replicas      []*heightTracker
+	next          atomic.Uint64
+}
+
+func NewReplicaRouter(primary *Repository, replicas []*Repository) *ReplicaRouter {
+	trackers := make([]*heightTracker, 0, len(replicas))
+	for _, replica := range replicas {
+		trackers = append(trackers, newHeightTracker(replica))
+	}
+	return &ReplicaRouter{
+		primary:       primary,
+		primaryHeight: newHeightTracker(primary),
+		replicas:      trackers,
+	}
+}
+
+// heightTracker caches the latest indexed block height of a database.
+type heightTracker struct {
+	repo           *Repository
+	getLatestBlock func(ctx context.Context) (types.BlockHeader, error)
+
+	mu         sync.Mutex
+	height     int64
+	checkedAt  time.Time
+	refreshing bool
+}
+
+func newHeightTracker(repo *Repository) *heightTracker {
+	return &heightTracker{
+		repo:           repo,
+		getLatestBlock: repo.GetLatestBlock,
+	}
+}
+
+// latestHeight returns the cached latest indexed block height of the database. Returns -1 if the database has no indexed block or is unavailable.
+// While the height is being refreshed, other callers get the stale height instead of waiting for the database.
+func (t *heightTracker) latestHeight(ctx context.Context) int64 {
+	t.mu.Lock()
+	if !t.checkedAt.IsZero() && (t.refreshing || time.Since(t.checkedAt) < replicaHeightTTL) {
+		height := t.height
+		t.mu.Unlock()
+		return height
+	}
+	t.refreshing = true
+	t.mu.Unlock()
+
+	height := int64(-1)
+	checked := true
+	block, err := t.getLatestBlock(ctx)
+	switch {
+	case err == nil:
+		height = block.Height
+	case ctx.Err() != nil:
+		// the caller gave up, so the error says nothing about the database. Don't cache it.
+		checked = false
+	case !errors.Is(err, errs.NotFound):
+		logger.WarnContext(ctx, "Failed to get latest block of database, skipping it", slogx.Error(err))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refreshing = false
+	if checked {
+		t.height = height
+		t.checkedAt = time.Now()
+	}
+	return height
+}
+
+// readerAtHeight returns a replica that has indexed the given block height, or the primary if there is none.
+// Replicas are picked in round-robin order.
+func (r *ReplicaRouter) readerAtHeight(ctx context.Context, height int64) *Repository {
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		replica := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if replica.latestHeight(ctx) >= height {
+			return replica.repo
+		}
+	}
+	return r.primary
+}
+
+// readerAtLatest returns a replica that has caught up with the latest indexed block of the primary, or the primary if there is none.
+func (r *ReplicaRouter) readerAtLatest(ctx context.Context) *Repository {
+	height := r.primaryHeight.latestHeight(ctx)
+	if height < 0 {
+		return r.primary
+	}
+	return r.readerAtHeight(ctx, height)
+}
+
+// GetLatestBlock always queries the primary, as it determines the block height of the following queries.
+func (r *ReplicaRouter) GetLatestBlock(ctx context.Context) (types.BlockHeader, error) {
+	return r.primary.GetLatestBlock(ctx)
+}
+
+func (r *ReplicaRouter) GetIndexedBlockByHeight(ctx context.Context, height int64) (*entity.IndexedBlock, error) {
+	return r.readerAtHeight(ctx, height).GetIndexedBlockByHeight(ctx, height)
+}
+
+// GetIndexedBlockByHash always queries the primary, as the height of the block is unknown.
+func (r *ReplicaRouter) GetIndexedBlockByHash(ctx context.Context, hash chainhash.Hash) (*entity.IndexedBlock, error) {
+	return r.primary.GetIndexedBlockByHash(ctx, hash)
+}
+
+func (r *ReplicaRouter) GetBlockHeightAtTimestamp(ctx context.Context, timestamp time.Time) (uint64, error) {
+	return r.readerAtLatest(ctx).GetBlockHeightAtTimestamp(ctx, timestamp)
+}
+
+func (r *ReplicaRouter) GetRuneTransactions(ctx context.Context, pkScript []byte, runeId runes.RuneId, fromBlock, toBlock uint64, cursor *entity.RuneTransactionCursor, limit int32, offset int32) ([]*entity.RuneTransaction, error) {
+	// toBlock = 0 queries up to the latest block
+	if toBlock == 0 {
+		return r.readerAtLatest(ctx).GetRuneTransactions(ctx, pkScript, runeId, fromBlock, toBlock, cursor, limit, offset)
+	}
+	return r.readerAtHeight(ctx, int64(toBlock)).GetRuneTransactions(ctx, pkScript, runeId, fromBlock, toBlock, cursor, limit, offset)
+}
+
+func (r *ReplicaRouter) GetRuneTransaction(ctx context.Context, txHash chainhash.Hash) (*entity.RuneTransaction, error) {
+	return r.readerAtLatest(ctx).GetRuneTransaction(ctx, txHash)
+}
+
+func (r *ReplicaRouter) GetRuneTransactionsByHashes(ctx context.Context, txHashes []chainhash.Hash) (map[chainhash.Hash]*entity.RuneTransaction, error) {
+	return r.readerAtLatest(ctx).GetRuneTransactionsByHashes(ctx, txHashes)
+}
+
+func (r *ReplicaRouter) GetRunesBalancesAtOutPoint(ctx context.Context, outPoint wire.OutPoint) (map[runes.RuneId]*entity.OutPointBalance, error) {
+	return r.readerAtLatest(ctx).GetRunesBalancesAtOutPoint(ctx, outPoint)
+}
+
+func (r *ReplicaRouter) GetRunesUTXOsByRuneIdAndPkScript(ctx context.Context, runeId runes.RuneId, pkScript []byte, blockHeight uint64, cursor *wire.OutPoint, limit int32, offset int32) ([]*entity.RunesUTXOWithValue, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetRunesUTXOsByRuneIdAndPkScript(ctx, runeId, pkScript, blockHeight, cursor, limit, offset)
+}
+
+func (r *ReplicaRouter) GetRunesUTXOsByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, cursor *wire.OutPoint, limit int32, offset int32) ([]*entity.RunesUTXOWithValue, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetRunesUTXOsByPkScript(ctx, pkScript, blockHeight, cursor, limit, offset)
+}
+
+func (r *ReplicaRouter) GetRuneIdFromRune(ctx context.Context, rune runes.Rune) (runes.RuneId, error) {
+	return r.readerAtLatest(ctx).GetRuneIdFromRune(ctx, rune)
+}
+
+func (r *ReplicaRouter) GetRuneEntryByRuneId(ctx context.Context, runeId runes.RuneId) (*runes.RuneEntry, error) {
+	return r.readerAtLatest(ctx).GetRuneEntryByRuneId(ctx, runeId)
+}
+
+func (r *ReplicaRouter) GetRuneEntryByRuneIdBatch(ctx context.Context, runeIds []runes.RuneId) (map[runes.RuneId]*runes.RuneEntry, error) {
+	return r.readerAtLatest(ctx).GetRuneEntryByRuneIdBatch(ctx, runeIds)
+}
+
+func (r *ReplicaRouter) GetRuneEntryByRuneIdAndHeight(ctx context.Context, runeId runes.RuneId, blockHeight uint64) (*runes.RuneEntry, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetRuneEntryByRuneIdAndHeight(ctx, runeId, blockHeight)
+}
+
+func (r *ReplicaRouter) GetRuneEntryByRuneIdAndHeightBatch(ctx context.Context, runeIds []runes.RuneId, blockHeight uint64) (map[runes.RuneId]*runes.RuneEntry, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetRuneEntryByRuneIdAndHeightBatch(ctx, runeIds, blockHeight)
+}
+
+func (r *ReplicaRouter) GetRuneEntryStateHistory(ctx context.Context, runeId runes.RuneId, fromBlock, toBlock uint64, interval uint64, limit int32, offset int32) ([]*entity.RuneEntryState, error) {
+	return r.readerAtHeight(ctx, int64(toBlock)).GetRuneEntryStateHistory(ctx, runeId, fromBlock, toBlock, interval, limit, offset)
+}
+
+func (r *ReplicaRouter) GetRuneEntries(ctx context.Context, search string, blockHeight uint64, cursor *entity.RuneEntryCursor, limit int32, offset int32) ([]*runes.RuneEntry, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetRuneEntries(ctx, search, blockHeight, cursor, limit, offset)
+}
+
+func (r *ReplicaRouter) GetOngoingRuneEntries(ctx context.Context, search string, blockHeight uint64, cursor *entity.RuneEntryCursor, limit int32, offset int32) ([]*runes.RuneEntry, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetOngoingRuneEntries(ctx, search, blockHeight, cursor, limit, offset)
+}
+
+func (r *ReplicaRouter) CountRuneEntries(ctx context.Context) (uint64, error) {
+	return r.readerAtLatest(ctx).CountRuneEntries(ctx)
+}
+
+func (r *ReplicaRouter) GetBalancesByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetBalancesByPkScript(ctx, pkScript, blockHeight, limit, offset)
+}
+
+func (r *ReplicaRouter) GetBalanceHistoryByPkScript(ctx context.Context, pkScript []byte, runeId runes.RuneId, fromBlock, toBlock uint64, interval uint64, limit int32, offset int32) ([]*entity.BalanceChange, error) {
+	return r.readerAtHeight(ctx, int64(toBlock)).GetBalanceHistoryByPkScript(ctx, pkScript, runeId, fromBlock, toBlock, interval, limit, offset)
+}
+
+func (r *ReplicaRouter) GetCurrentBalancesByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetCurrentBalancesByPkScript(ctx, pkScript, blockHeight, limit, offset)
+}
+
+func (r *ReplicaRouter) GetBalancesByRuneId(ctx context.Context, runeId runes.RuneId, blockHeight uint64, cursor *entity.BalanceCursor, limit int32, offset int32) ([]*entity.Balance, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetBalancesByRuneId(ctx, runeId, blockHeight, cursor, limit, offset)
+}
+
+func (r *ReplicaRouter) GetHolderChangesByRuneId(ctx context.Context, runeId runes.RuneId, fromBlock, toBlock uint64, changeType entity.HolderChangeType, limit int32, offset int32) ([]*entity.HolderChange, error) {
+	return r.readerAtHeight(ctx, int64(toBlock)).GetHolderChangesByRuneId(ctx, runeId, fromBlock, toBlock, changeType, limit, offset)
+}
+
+func (r *ReplicaRouter) GetBalanceByPkScriptAndRuneId(ctx context.Context, pkScript []byte, runeId runes.RuneId, blockHeight uint64) (*entity.Balance, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetBalanceByPkScriptAndRuneId(ctx, pkScript, runeId, blockHeight)
+}
+
+func (r *ReplicaRouter) GetTotalHoldersByRuneIds(ctx context.Context, runeIds []runes.RuneId, blockHeight uint64) (map[runes.RuneId]int64, error) {
+	return r.readerAtHeight(ctx, int64(blockHeight)).GetTotalHoldersByRuneIds(ctx, runeIds, blockHeight)
+}
+
+// GetPrunedHeight always queries the primary, as replicas are never pruned further than the primary.
+func (r *ReplicaRouter) GetPrunedHeight(ctx context.Context) (uint64, error) {
+	return r.primary.GetPrunedHeight(ctx)
+}
+
+// CountRevertRowsSinceHeight always queries the primary, as it is used to plan changes to the primary.
+func (r *ReplicaRouter) CountRevertRowsSinceHeight(ctx context.Context, height uint64) (*entity.RevertCounts, error) {
+	return r.primary.CountRevertRowsSinceHeight(ctx, height)
+}
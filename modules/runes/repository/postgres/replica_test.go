@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHeight is the latest block height of a fake database, -1 if it has no indexed block.
+type fakeHeight struct {
+	height int64
+	err    error
+	calls  int
+}
+
+func (h *fakeHeight) getLatestBlock(ctx context.Context) (types.BlockHeader, error) {
+	h.calls++
+	if h.err != nil {
+		return types.BlockHeader{}, h.err
+	}
+	if err := ctx.Err(); err != nil {
+		return types.BlockHeader{}, errors.WithStack(err)
+	}
+	if h.height < 0 {
+		return types.BlockHeader{}, errors.WithStack(errs.NotFound)
+	}
+	return types.BlockHeader{Height: h.height}, nil
+}
+
+func newFakeReplicaRouter(primaryHeight int64, replicaHeights ...int64) (*ReplicaRouter, []*fakeHeight) {
+	newTracker := func(height int64) (*heightTracker, *fakeHeight) {
+		fake := &fakeHeight{height: height}
+		return &heightTracker{repo: &Repository{}, getLatestBlock: fake.getLatestBlock}, fake
+	}
+	primaryTracker, primaryFake := newTracker(primaryHeight)
+	fakes := []*fakeHeight{primaryFake}
+	router := &ReplicaRouter{
+		primary:       primaryTracker.repo,
+		primaryHeight: primaryTracker,
+	}
+	for _, height := range replicaHeights {
+		tracker, fake := newTracker(height)
+		router.replicas = append(router.replicas, tracker)
+		fakes = append(fakes, fake)
+	}
+	return router, fakes
+}
+
+func TestReplicaRouterReaderAtHeight(t *testing.T) {
+	type testcase struct {
+		name           string
+		replicaHeights []int64
+		height         int64
+		expected       int // index of the expected replica, -1 for the primary
+	}
+
+	testcases := []testcase{
+		{
+			name:           "replica caught up",
+			replicaHeights: []int64{100},
+			height:         100,
+			expected:       0,
+		},
+		{
+			name:           "replica ahead",
+			replicaHeights: []int64{101},
+			height:         100,
+			expected:       0,
+		},
+		{
+			name:           "replica behind",
+			replicaHeights: []int64{99},
+			height:         100,
+			expected:       -1,
+		},
+		{
+			name:           "replica without indexed block",
+			replicaHeights: []int64{-1},
+			height:         0,
+			expected:       -1,
+		},
+		{
+			name:           "skip replica behind",
+			replicaHeights: []int64{99, 100},
+			height:         100,
+			expected:       1,
+		},
+		{
+			name:     "no replica",
+			height:   100,
+			expected: -1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, _ := newFakeReplicaRouter(100, tc.replicaHeights...)
+			expected := router.primary
+			if tc.expected >= 0 {
+				expected = router.replicas[tc.expected].repo
+			}
+			assert.Same(t, expected, router.readerAtHeight(context.Background(), tc.height))
+		})
+	}
+
+	t.Run("round robin", func(t *testing.T) {
+		router, _ := newFakeReplicaRouter(100, 100, 100)
+		ctx := context.Background()
+		first := router.readerAtHeight(ctx, 100)
+		second := router.readerAtHeight(ctx, 100)
+		assert.NotSame(t, first, second)
+		assert.NotSame(t, router.primary, first)
+		assert.NotSame(t, router.primary, second)
+		assert.Same(t, first, router.readerAtHeight(ctx, 100))
+	})
+}
+
+func TestReplicaRouterReaderAtLatest(t *testing.T) {
+	t.Run("replica caught up with primary", func(t *testing.T) {
+		router, _ := newFakeReplicaRouter(100, 99, 100)
+		assert.Same(t, router.replicas[1].repo, router.readerAtLatest(context.Background()))
+	})
+	t.Run("replicas behind primary", func(t *testing.T) {
+		router, _ := newFakeReplicaRouter(100, 99, 98)
+		assert.Same(t, router.primary, router.readerAtLatest(context.Background()))
+	})
+	t.Run("primary without indexed block", func(t *testing.T) {
+		router, _ := newFakeReplicaRouter(-1, 100)
+		assert.Same(t, router.primary, router.readerAtLatest(context.Background()))
+	})
+}
+
+func TestHeightTrackerLatestHeight(t *testing.T) {
+	t.Run("cached", func(t *testing.T) {
+		router, fakes := newFakeReplicaRouter(100)
+		tracker, fake := router.primaryHeight, fakes[0]
+		ctx := context.Background()
+		assert.Equal(t, int64(100), tracker.latestHeight(ctx))
+
+		fake.height = 101
+		assert.Equal(t, int64(100), tracker.latestHeight(ctx))
+		assert.Equal(t, 1, fake.calls)
+
+		// the height is checked again after the TTL
+		tracker.checkedAt = time.Now().Add(-replicaHeightTTL)
+		assert.Equal(t, int64(101), tracker.latestHeight(ctx))
+		assert.Equal(t, 2, fake.calls)
+	})
+	t.Run("stale height while refreshing", func(t *testing.T) {
+		router, fakes := newFakeReplicaRouter(100)
+		tracker, fake := router.primaryHeight, fakes[0]
+		ctx := context.Background()
+		assert.Equal(t, int64(100), tracker.latestHeight(ctx))
+
+		fake.height = 101
+		tracker.checkedAt = time.Now().Add(-replicaHeightTTL)
+		tracker.refreshing = true
+		assert.Equal(t, int64(100), tracker.latestHeight(ctx))
+		assert.Equal(t, 1, fake.calls)
+	})
+	t.Run("unavailable database", func(t *testing.T) {
+		router, fakes := newFakeReplicaRouter(100)
+		tracker, fake := router.primaryHeight, fakes[0]
+		fake.err = errors.New("connection refused")
+		ctx := context.Background()
+		assert.Equal(t, int64(-1), tracker.latestHeight(ctx))
+
+		// the failure is cached
+		fake.err = nil
+		assert.Equal(t, int64(-1), tracker.latestHeight(ctx))
+		assert.Equal(t, 1, fake.calls)
+	})
+	t.Run("cancelled context is not cached", func(t *testing.T) {
+		router, fakes := newFakeReplicaRouter(100)
+		tracker, fake := router.primaryHeight, fakes[0]
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Equal(t, int64(-1), tracker.latestHeight(ctx))
+		assert.False(t, tracker.refreshing)
+
+		assert.Equal(t, int64(100), tracker.latestHeight(context.Background()))
+		assert.Equal(t, 2, fake.calls)
+	})
+}
@@ -3,8 +3,8 @@ package postgres
 import (
 	"context"
 	"encoding/hex"
-	"fmt"
 	"math"
+	"time"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -15,6 +15,8 @@ import (
 	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
 	"github.com/gaze-network/indexer-network/modules/runes/repository/postgres/gen"
 	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/indexer-network/pkg/logger"
+	"github.com/gaze-network/indexer-network/pkg/logger/slogx"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/samber/lo"
@@ -63,9 +65,36 @@ func (r *Repository) GetIndexedBlockByHeight(ctx context.Context, height int64)
 	return indexedBlock, nil
 }
 
+func (r *Repository) GetBlockHeightAtTimestamp(ctx context.Context, timestamp time.Time) (uint64, error) {
+	height, err := r.queries.GetBlockHeightAtTimestamp(ctx, pgtype.Timestamp{Time: timestamp.UTC(), Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errors.WithStack(errs.NotFound)
+		}
+		return 0, errors.Wrap(err, "error during query")
+	}
+	return uint64(height), nil
+}
+
+func (r *Repository) GetIndexedBlockByHash(ctx context.Context, hash chainhash.Hash) (*entity.IndexedBlock, error) {
+	indexedBlockModel, err := r.queries.GetIndexedBlockByHash(ctx, hash.String())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.WithStack(errs.NotFound)
+		}
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	indexedBlock, err := mapIndexedBlockModelToType(indexedBlockModel)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse indexed block model")
+	}
+	return indexedBlock, nil
+}
+
 const maxRuneTransactionsLimit = 10000 // temporary limit to prevent large queries from overwhelming the database
 
-func (r *Repository) GetRuneTransactions(ctx context.Context, pkScript []byte, runeId runes.RuneId, fromBlock, toBlock uint64, limit int32, offset int32) ([]*entity.RuneTransaction, error) {
+func (r *Repository) GetRuneTransactions(ctx context.Context, pkScript []byte, runeId runes.RuneId, fromBlock, toBlock uint64, cursor *entity.RuneTransactionCursor, limit int32, offset int32) ([]*entity.RuneTransaction, error) {
 	if limit == -1 {
 		limit = maxRuneTransactionsLimit
 	}
@@ -75,15 +104,12 @@ func (r *Repository) GetRuneTransactions(ctx context.Context, pkScript []byte, r
 	if limit > maxRuneTransactionsLimit {
 		return nil, errors.Wrapf(errs.InvalidArgument, "limit cannot exceed %d", maxRuneTransactionsLimit)
 	}
-	pkScriptParam := []byte(fmt.Sprintf(`[{"pkScript":"%s"}]`, hex.EncodeToString(pkScript)))
-	runeIdParam := []byte(fmt.Sprintf(`[{"runeId":"%s"}]`, runeId.String()))
-	rows, err := r.queries.GetRuneTransactions(ctx, gen.GetRuneTransactionsParams{
+	params := gen.GetRuneTransactionsParams{
 		FilterPkScript: pkScript != nil,
-		PkScriptParam:  pkScriptParam,
+		PkScript:       hex.EncodeToString(pkScript),
 
 		FilterRuneID:      runeId != runes.RuneId{},
-		RuneIDParam:       runeIdParam,
-		RuneID:            []byte(runeId.String()),
+		RuneID:            runeId.String(),
 		RuneIDBlockHeight: int32(runeId.BlockHeight),
 		RuneIDTxIndex:     int32(runeId.TxIndex),
 
@@ -92,7 +118,13 @@ func (r *Repository) GetRuneTransactions(ctx context.Context, pkScript []byte, r
 
 		Limit:  limit,
 		Offset: offset,
-	})
+	}
+	if cursor != nil {
+		params.UseCursor = true
+		params.CursorBlockHeight = int32(cursor.BlockHeight)
+		params.CursorIndex = int32(cursor.Index)
+	}
+	rows, err := r.queries.GetRuneTransactions(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "error during query")
 	}
@@ -150,6 +182,40 @@ func (r *Repository) GetRuneTransaction(ctx context.Context, txHash chainhash.Ha
 	return &runeTx, nil
 }
 
+func (r *Repository) GetRuneTransactionsByHashes(ctx context.Context, txHashes []chainhash.Hash) (map[chainhash.Hash]*entity.RuneTransaction, error) {
+	if len(txHashes) == 0 {
+		return make(map[chainhash.Hash]*entity.RuneTransaction), nil
+	}
+	rows, err := r.queries.GetRuneTransactionsByHashes(ctx, lo.Map(txHashes, func(txHash chainhash.Hash, _ int) string {
+		return txHash.String()
+	}))
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	runeTxs := make(map[chainhash.Hash]*entity.RuneTransaction, len(rows))
+	for _, row := range rows {
+		runeTxModel, runestoneModel, err := extractModelRuneTxAndRunestone(gen.GetRuneTransactionsRow(row))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to extract rune transaction and runestone from row")
+		}
+
+		runeTx, err := mapRuneTransactionModelToType(runeTxModel)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse rune transaction model")
+		}
+		if runestoneModel != nil {
+			runestone, err := mapRunestoneModelToType(*runestoneModel)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse runestone model")
+			}
+			runeTx.Runestone = &runestone
+		}
+		runeTxs[runeTx.Hash] = &runeTx
+	}
+	return runeTxs, nil
+}
+
 func (r *Repository) GetRunesBalancesAtOutPoint(ctx context.Context, outPoint wire.OutPoint) (map[runes.RuneId]*entity.OutPointBalance, error) {
 	balances, err := r.queries.GetOutPointBalancesAtOutPoint(ctx, gen.GetOutPointBalancesAtOutPointParams{
 		TxHash: outPoint.Hash.String(),
@@ -170,24 +236,30 @@ func (r *Repository) GetRunesBalancesAtOutPoint(ctx context.Context, outPoint wi
 	return result, nil
 }
 
-func (r *Repository) GetRunesUTXOsByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.RunesUTXO, error) {
+func (r *Repository) GetRunesUTXOsByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, cursor *wire.OutPoint, limit int32, offset int32) ([]*entity.RunesUTXOWithValue, error) {
 	if limit == -1 {
 		limit = math.MaxInt32
 	}
 	if limit < 0 {
 		return nil, errors.Wrap(errs.InvalidArgument, "limit must be -1 or non-negative")
 	}
-	rows, err := r.queries.GetRunesUTXOsByPkScript(ctx, gen.GetRunesUTXOsByPkScriptParams{
+	params := gen.GetRunesUTXOsByPkScriptParams{
 		Pkscript:    hex.EncodeToString(pkScript),
 		BlockHeight: int32(blockHeight),
 		Limit:       limit,
 		Offset:      offset,
-	})
+	}
+	if cursor != nil {
+		params.UseCursor = true
+		params.CursorTxHash = cursor.Hash.String()
+		params.CursorTxIdx = int32(cursor.Index)
+	}
+	rows, err := r.queries.GetRunesUTXOsByPkScript(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "error during query")
 	}
 
-	result := make([]*entity.RunesUTXO, 0, len(rows))
+	result := make([]*entity.RunesUTXOWithValue, 0, len(rows))
 	for _, row := range rows {
 		utxo, err := mapRunesUTXOModelToType(row)
 		if err != nil {
@@ -198,25 +270,31 @@ func (r *Repository) GetRunesUTXOsByPkScript(ctx context.Context, pkScript []byt
 	return result, nil
 }
 
-func (r *Repository) GetRunesUTXOsByRuneIdAndPkScript(ctx context.Context, runeId runes.RuneId, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.RunesUTXO, error) {
+func (r *Repository) GetRunesUTXOsByRuneIdAndPkScript(ctx context.Context, runeId runes.RuneId, pkScript []byte, blockHeight uint64, cursor *wire.OutPoint, limit int32, offset int32) ([]*entity.RunesUTXOWithValue, error) {
 	if limit == -1 {
 		limit = math.MaxInt32
 	}
 	if limit < 0 {
 		return nil, errors.Wrap(errs.InvalidArgument, "limit must be -1 or non-negative")
 	}
-	rows, err := r.queries.GetRunesUTXOsByRuneIdAndPkScript(ctx, gen.GetRunesUTXOsByRuneIdAndPkScriptParams{
+	params := gen.GetRunesUTXOsByRuneIdAndPkScriptParams{
 		Pkscript:    hex.EncodeToString(pkScript),
 		BlockHeight: int32(blockHeight),
 		RuneIds:     []string{runeId.String()},
 		Limit:       limit,
 		Offset:      offset,
-	})
+	}
+	if cursor != nil {
+		params.UseCursor = true
+		params.CursorTxHash = cursor.Hash.String()
+		params.CursorTxIdx = int32(cursor.Index)
+	}
+	rows, err := r.queries.GetRunesUTXOsByRuneIdAndPkScript(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "error during query")
 	}
 
-	result := make([]*entity.RunesUTXO, 0, len(rows))
+	result := make([]*entity.RunesUTXOWithValue, 0, len(rows))
 	for _, row := range rows {
 		utxo, err := mapRunesUTXOModelToType(gen.GetRunesUTXOsByPkScriptRow(row))
 		if err != nil {
@@ -280,7 +358,7 @@ func (r *Repository) GetRuneEntryByRuneIdBatch(ctx context.Context, runeIds []ru
 }
 
 func (r *Repository) GetRuneEntryByRuneIdAndHeight(ctx context.Context, runeId runes.RuneId, blockHeight uint64) (*runes.RuneEntry, error) {
-	runeEntries, err := r.GetRuneEntryByRuneIdBatch(ctx, []runes.RuneId{runeId})
+	runeEntries, err := r.GetRuneEntryByRuneIdAndHeightBatch(ctx, []runes.RuneId{runeId}, blockHeight)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get rune entries by rune id")
 	}
@@ -319,13 +397,51 @@ func (r *Repository) GetRuneEntryByRuneIdAndHeightBatch(ctx context.Context, run
 	return runeEntries, nil
 }
 
-func (r *Repository) GetRuneEntries(ctx context.Context, search string, blockHeight uint64, limit int32, offset int32) ([]*runes.RuneEntry, error) {
-	rows, err := r.queries.GetRuneEntries(ctx, gen.GetRuneEntriesParams{
+func (r *Repository) GetRuneEntryStateHistory(ctx context.Context, runeId runes.RuneId, fromBlock, toBlock uint64, interval uint64, limit int32, offset int32) ([]*entity.RuneEntryState, error) {
+	if limit == -1 {
+		limit = math.MaxInt32
+	}
+	if limit < 0 {
+		return nil, errors.Wrap(errs.InvalidArgument, "limit must be -1 or non-negative")
+	}
+	if interval == 0 {
+		return nil, errors.Wrap(errs.InvalidArgument, "interval must be positive")
+	}
+	rows, err := r.queries.GetRuneEntryStateHistory(ctx, gen.GetRuneEntryStateHistoryParams{
+		RuneID:    runeId.String(),
+		FromBlock: int32(fromBlock),
+		ToBlock:   int32(toBlock),
+		Interval:  int32(interval),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	result := make([]*entity.RuneEntryState, 0, len(rows))
+	for _, row := range rows {
+		state, err := mapRuneEntryStateHistoryRowToType(row)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse rune entry state row")
+		}
+		result = append(result, state)
+	}
+	return result, nil
+}
+
+func (r *Repository) GetRuneEntries(ctx context.Context, search string, blockHeight uint64, cursor *entity.RuneEntryCursor, limit int32, offset int32) ([]*runes.RuneEntry, error) {
+	params := gen.GetRuneEntriesParams{
 		Search: search,
 		Height: int32(blockHeight),
 		Limit:  limit,
 		Offset: offset,
-	})
+	}
+	if cursor != nil {
+		params.UseCursor = true
+		params.CursorNumber = int64(cursor.Number)
+	}
+	rows, err := r.queries.GetRuneEntries(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "error during query")
 	}
@@ -347,13 +463,23 @@ func (r *Repository) GetRuneEntries(ctx context.Context, search string, blockHei
 	return runeEntries, nil
 }
 
-func (r *Repository) GetOngoingRuneEntries(ctx context.Context, search string, blockHeight uint64, limit int32, offset int32) ([]*runes.RuneEntry, error) {
-	rows, err := r.queries.GetOngoingRuneEntries(ctx, gen.GetOngoingRuneEntriesParams{
+func (r *Repository) GetOngoingRuneEntries(ctx context.Context, search string, blockHeight uint64, cursor *entity.RuneEntryCursor, limit int32, offset int32) ([]*runes.RuneEntry, error) {
+	params := gen.GetOngoingRuneEntriesParams{
 		Search: search,
 		Height: int32(blockHeight),
 		Limit:  limit,
 		Offset: offset,
-	})
+	}
+	if cursor != nil {
+		cursorMints, err := numericFromUint128(&cursor.Mints)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse cursor mints")
+		}
+		params.UseCursor = true
+		params.CursorMints = cursorMints
+		params.CursorNumber = int64(cursor.Number)
+	}
+	rows, err := r.queries.GetOngoingRuneEntries(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "error during query")
 	}
@@ -411,19 +537,114 @@ func (r *Repository) GetBalancesByPkScript(ctx context.Context, pkScript []byte,
 	return result, nil
 }
 
-func (r *Repository) GetBalancesByRuneId(ctx context.Context, runeId runes.RuneId, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error) {
+func (r *Repository) GetBalanceHistoryByPkScript(ctx context.Context, pkScript []byte, runeId runes.RuneId, fromBlock, toBlock uint64, interval uint64, limit int32, offset int32) ([]*entity.BalanceChange, error) {
 	if limit == -1 {
 		limit = math.MaxInt32
 	}
 	if limit < 0 {
 		return nil, errors.Wrap(errs.InvalidArgument, "limit must be -1 or non-negative")
 	}
-	balances, err := r.queries.GetBalancesByRuneId(ctx, gen.GetBalancesByRuneIdParams{
+	if interval == 0 {
+		return nil, errors.Wrap(errs.InvalidArgument, "interval must be positive")
+	}
+	rows, err := r.queries.GetBalanceHistoryByPkScript(ctx, gen.GetBalanceHistoryByPkScriptParams{
+		Pkscript:     hex.EncodeToString(pkScript),
+		FilterRuneID: runeId != runes.RuneId{},
+		RuneID:       runeId.String(),
+		FromBlock:    int32(fromBlock),
+		ToBlock:      int32(toBlock),
+		Interval:     int32(interval),
+		Limit:        limit,
+		Offset:       offset,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	result := make([]*entity.BalanceChange, 0, len(rows))
+	for _, row := range rows {
+		change, err := mapBalanceHistoryRowToType(row)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse balance history row")
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+func (r *Repository) GetCurrentBalancesByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error) {
+	if limit == -1 {
+		limit = math.MaxInt32
+	}
+	if limit < 0 {
+		return nil, errors.Wrap(errs.InvalidArgument, "limit must be -1 or non-negative")
+	}
+
+	// the latest block height and the current balances must be read from the same snapshot, or a block flushed in between would be missed
+	repo := r
+	if r.tx == nil {
+		var err error
+		repo, err = r.beginReadSnapshot(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer func() {
+			if err := repo.Rollback(ctx); err != nil {
+				logger.WarnContext(ctx, "failed to rollback transaction", slogx.Error(err))
+			}
+		}()
+	}
+	latestBlock, err := repo.queries.GetLatestIndexedBlock(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.Wrap(err, "error during query")
+	}
+	if err != nil || uint64(latestBlock.Height) != blockHeight {
+		return nil, errors.Wrapf(errs.InvalidState, "block height %d is not the latest indexed block height", blockHeight)
+	}
+
+	balances, err := repo.queries.GetCurrentBalancesByPkScript(ctx, gen.GetCurrentBalancesByPkScriptParams{
+		Pkscript: hex.EncodeToString(pkScript),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	result := make([]*entity.Balance, 0, len(balances))
+	for _, balanceModel := range balances {
+		balance, err := mapCurrentBalanceModelToType(balanceModel)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse balance model")
+		}
+		result = append(result, balance)
+	}
+	return result, nil
+}
+
+func (r *Repository) GetBalancesByRuneId(ctx context.Context, runeId runes.RuneId, blockHeight uint64, cursor *entity.BalanceCursor, limit int32, offset int32) ([]*entity.Balance, error) {
+	if limit == -1 {
+		limit = math.MaxInt32
+	}
+	if limit < 0 {
+		return nil, errors.Wrap(errs.InvalidArgument, "limit must be -1 or non-negative")
+	}
+	params := gen.GetBalancesByRuneIdParams{
 		RuneID:      runeId.String(),
 		BlockHeight: int32(blockHeight),
 		Limit:       limit,
 		Offset:      offset,
-	})
+	}
+	if cursor != nil {
+		cursorAmount, err := numericFromUint128(&cursor.Amount)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse cursor amount")
+		}
+		params.UseCursor = true
+		params.CursorAmount = cursorAmount
+		params.CursorPkscript = hex.EncodeToString(cursor.PkScript)
+	}
+	balances, err := r.queries.GetBalancesByRuneId(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "error during query")
 	}
@@ -439,6 +660,36 @@ func (r *Repository) GetBalancesByRuneId(ctx context.Context, runeId runes.RuneI
 	return result, nil
 }
 
+func (r *Repository) GetHolderChangesByRuneId(ctx context.Context, runeId runes.RuneId, fromBlock, toBlock uint64, changeType entity.HolderChangeType, limit int32, offset int32) ([]*entity.HolderChange, error) {
+	if limit == -1 {
+		limit = math.MaxInt32
+	}
+	if limit < 0 {
+		return nil, errors.Wrap(errs.InvalidArgument, "limit must be -1 or non-negative")
+	}
+	rows, err := r.queries.GetHolderChangesByRuneId(ctx, gen.GetHolderChangesByRuneIdParams{
+		RuneID:     runeId.String(),
+		FromBlock:  int32(fromBlock),
+		ToBlock:    int32(toBlock),
+		ChangeType: string(changeType),
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	result := make([]*entity.HolderChange, 0, len(rows))
+	for _, row := range rows {
+		change, err := mapHolderChangeRowToType(row)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse holder change row")
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
 func (r *Repository) GetBalanceByPkScriptAndRuneId(ctx context.Context, pkScript []byte, runeId runes.RuneId, blockHeight uint64) (*entity.Balance, error) {
 	balance, err := r.queries.GetBalanceByPkScriptAndRuneId(ctx, gen.GetBalanceByPkScriptAndRuneIdParams{
 		Pkscript:    hex.EncodeToString(pkScript),
@@ -478,6 +729,36 @@ func (r *Repository) GetTotalHoldersByRuneIds(ctx context.Context, runeIds []run
 	return holders, nil
 }
 
+func (r *Repository) GetPrunedHeight(ctx context.Context) (uint64, error) {
+	prunedHeight, err := r.queries.GetLatestPrunedHeight(ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, errors.Wrap(err, "error during query")
+	}
+	return uint64(prunedHeight), nil
+}
+
+func (r *Repository) CountRevertRowsSinceHeight(ctx context.Context, height uint64) (*entity.RevertCounts, error) {
+	row, err := r.queries.CountRevertRowsSinceHeight(ctx, int32(height))
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+	return &entity.RevertCounts{
+		IndexedBlocks:         row.IndexedBlocks,
+		RuneEntries:           row.RuneEntries,
+		RuneEntryStates:       row.RuneEntryStates,
+		RuneTransactions:      row.RuneTransactions,
+		RuneTransactionIO:     row.RuneTransactionIo,
+		Runestones:            row.Runestones,
+		OutPointBalances:      row.OutpointBalances,
+		SpentOutPointBalances: row.SpentOutpointBalances,
+		Balances:              row.Balances,
+		CurrentBalances:       row.CurrentBalances,
+	}, nil
+}
+
 func (r *Repository) CreateRuneTransactions(ctx context.Context, txs []*entity.RuneTransaction) error {
 	if len(txs) == 0 {
 		return nil
@@ -487,6 +768,13 @@ func (r *Repository) CreateRuneTransactions(ctx context.Context, txs []*entity.R
 	if err != nil {
 		return errors.Wrap(err, "failed to map rune transactions to params")
 	}
+	// transaction hashes are unique across partitions, so a transaction that is indexed twice is rejected here
+	if err := r.queries.BatchCreateRuneTransactionHeights(ctx, gen.BatchCreateRuneTransactionHeightsParams{
+		HashArr:        txParams.HashArr,
+		BlockHeightArr: txParams.BlockHeightArr,
+	}); err != nil {
+		return errors.Wrap(err, "error during exec BatchCreateRuneTransactionHeights")
+	}
 	if err := r.queries.BatchCreateRuneTransactions(ctx, txParams); err != nil {
 		return errors.Wrap(err, "error during exec BatchCreateRuneTransactions")
 	}
@@ -499,6 +787,14 @@ func (r *Repository) CreateRuneTransactions(ctx context.Context, txs []*entity.R
 		return errors.Wrap(err, "error during exec BatchCreateRunestones")
 	}
 
+	txIOParams, err := mapRuneTransactionIOTypeToParamsBatch(txs)
+	if err != nil {
+		return errors.Wrap(err, "failed to map rune transaction inputs/outputs to params")
+	}
+	if err := r.queries.BatchCreateRuneTransactionIOPatched(ctx, txIOParams); err != nil {
+		return errors.Wrap(err, "error during exec BatchCreateRuneTransactionIO")
+	}
+
 	return nil
 }
 
@@ -553,23 +849,52 @@ func (r *Repository) CreateOutPointBalances(ctx context.Context, outPointBalance
 	return nil
 }
 
-func (r *Repository) SpendOutPointBalancesBatch(ctx context.Context, outPoints []wire.OutPoint, blockHeight uint64) error {
-	if len(outPoints) == 0 {
+func (r *Repository) SpendOutPointBalancesBatch(ctx context.Context, spentOutPoints []*entity.SpentOutPoint, blockHeight uint64) error {
+	if len(spentOutPoints) == 0 {
 		return nil
 	}
 
 	params := gen.BatchSpendOutpointBalancesParams{
-		TxHashArr:   make([]string, 0, len(outPoints)),
-		TxIdxArr:    make([]int32, 0, len(outPoints)),
-		SpentHeight: int32(blockHeight),
-	}
-	for _, outPoint := range outPoints {
-		params.TxHashArr = append(params.TxHashArr, outPoint.Hash.String())
-		params.TxIdxArr = append(params.TxIdxArr, int32(outPoint.Index))
+		TxHashArr:          make([]string, 0, len(spentOutPoints)),
+		TxIdxArr:           make([]int32, 0, len(spentOutPoints)),
+		BlockHeightArr:     make([]int32, 0, len(spentOutPoints)),
+		SpentTxHashArr:     make([]string, 0, len(spentOutPoints)),
+		SpentTxInputIdxArr: make([]int32, 0, len(spentOutPoints)),
+		SpentHeight:        int32(blockHeight),
+		MinBlockHeight:     math.MaxInt32,
+	}
+	for _, spentOutPoint := range spentOutPoints {
+		params.TxHashArr = append(params.TxHashArr, spentOutPoint.OutPoint.Hash.String())
+		params.TxIdxArr = append(params.TxIdxArr, int32(spentOutPoint.OutPoint.Index))
+		params.BlockHeightArr = append(params.BlockHeightArr, int32(spentOutPoint.BlockHeight))
+		params.SpentTxHashArr = append(params.SpentTxHashArr, spentOutPoint.TxHash.String())
+		params.SpentTxInputIdxArr = append(params.SpentTxInputIdxArr, int32(spentOutPoint.InputIndex))
+		params.MinBlockHeight = min(params.MinBlockHeight, int32(spentOutPoint.BlockHeight))
+		params.MaxBlockHeight = max(params.MaxBlockHeight, int32(spentOutPoint.BlockHeight))
 	}
 
 	if err := r.queries.BatchSpendOutpointBalances(ctx, params); err != nil {
-		return errors.Wrap(err, "error during exec")
+		return errors.Wrap(err, "error during exec BatchSpendOutpointBalances")
+	}
+
+	// record each outpoint once, as an outpoint with multiple runes is spent once per rune. Outpoints that are spent twice are rejected.
+	uniqueSpentOutPoints := lo.UniqBy(spentOutPoints, func(spentOutPoint *entity.SpentOutPoint) wire.OutPoint {
+		return spentOutPoint.OutPoint
+	})
+	spendParams := gen.BatchCreateOutPointSpendsParams{
+		TxHashArr:      make([]string, 0, len(uniqueSpentOutPoints)),
+		TxIdxArr:       make([]int32, 0, len(uniqueSpentOutPoints)),
+		BlockHeightArr: make([]int32, 0, len(uniqueSpentOutPoints)),
+		SpentHeightArr: make([]int32, 0, len(uniqueSpentOutPoints)),
+	}
+	for _, spentOutPoint := range uniqueSpentOutPoints {
+		spendParams.TxHashArr = append(spendParams.TxHashArr, spentOutPoint.OutPoint.Hash.String())
+		spendParams.TxIdxArr = append(spendParams.TxIdxArr, int32(spentOutPoint.OutPoint.Index))
+		spendParams.BlockHeightArr = append(spendParams.BlockHeightArr, int32(spentOutPoint.BlockHeight))
+		spendParams.SpentHeightArr = append(spendParams.SpentHeightArr, int32(blockHeight))
+	}
+	if err := r.queries.BatchCreateOutPointSpends(ctx, spendParams); err != nil {
+		return errors.Wrap(err, "error during exec BatchCreateOutPointSpends")
 	}
 
 	return nil
@@ -591,6 +916,29 @@ func (r *Repository) CreateRuneBalances(ctx context.Context, balances []*entity.
 	return nil
 }
 
+func (r *Repository) UpdateCurrentBalances(ctx context.Context, balances []*entity.Balance) error {
+	if len(balances) == 0 {
+		return nil
+	}
+
+	upsertParams, deleteParams, err := mapCurrentBalanceTypeToParamsBatch(balances)
+	if err != nil {
+		return errors.Wrap(err, "failed to map current balances to params")
+	}
+	if len(upsertParams.PkscriptArr) > 0 {
+		if err := r.queries.BatchUpsertCurrentBalances(ctx, upsertParams); err != nil {
+			return errors.Wrap(err, "error during exec BatchUpsertCurrentBalances")
+		}
+	}
+	if len(deleteParams.PkscriptArr) > 0 {
+		if err := r.queries.BatchDeleteCurrentBalances(ctx, deleteParams); err != nil {
+			return errors.Wrap(err, "error during exec BatchDeleteCurrentBalances")
+		}
+	}
+
+	return nil
+}
+
 func (r *Repository) CreateIndexedBlock(ctx context.Context, block *entity.IndexedBlock) error {
 	if block == nil {
 		return nil
@@ -630,6 +978,12 @@ func (r *Repository) DeleteRuneTransactionsSinceHeight(ctx context.Context, heig
 	if err := r.queries.DeleteRuneTransactionsSinceHeight(ctx, int32(height)); err != nil {
 		return errors.Wrap(err, "error during exec")
 	}
+	if err := r.queries.DeleteRuneTransactionHeightsSinceHeight(ctx, int32(height)); err != nil {
+		return errors.Wrap(err, "error during exec")
+	}
+	if err := r.queries.DeleteRuneTransactionIOSinceHeight(ctx, int32(height)); err != nil {
+		return errors.Wrap(err, "error during exec")
+	}
 	return nil
 }
 
@@ -648,15 +1002,66 @@ func (r *Repository) DeleteOutPointBalancesSinceHeight(ctx context.Context, heig
 }
 
 func (r *Repository) UnspendOutPointBalancesSinceHeight(ctx context.Context, height uint64) error {
-	if err := r.queries.UnspendOutPointBalancesSinceHeight(ctx, pgtype.Int4{Int32: int32(height), Valid: true}); err != nil {
+	if err := r.queries.UnspendOutPointBalancesSinceHeight(ctx, int32(height)); err != nil {
+		return errors.Wrap(err, "error during exec")
+	}
+	// the spends are deleted after the outpoints are unspent, as they are used to find the spent outpoints
+	if err := r.queries.DeleteOutPointSpendsSinceHeight(ctx, int32(height)); err != nil {
 		return errors.Wrap(err, "error during exec")
 	}
 	return nil
 }
 
+func (r *Repository) RevertCurrentBalancesSinceHeight(ctx context.Context, height uint64) error {
+	if err := r.queries.DeleteCurrentBalancesSinceHeight(ctx, int32(height)); err != nil {
+		return errors.Wrap(err, "error during exec DeleteCurrentBalancesSinceHeight")
+	}
+	if err := r.queries.RestoreCurrentBalancesBeforeHeight(ctx, int32(height)); err != nil {
+		return errors.Wrap(err, "error during exec RestoreCurrentBalancesBeforeHeight")
+	}
+	return nil
+}
+
 func (r *Repository) DeleteRuneBalancesSinceHeight(ctx context.Context, height uint64) error {
 	if err := r.queries.DeleteRuneBalancesSinceHeight(ctx, int32(height)); err != nil {
 		return errors.Wrap(err, "error during exec")
 	}
 	return nil
 }
+
+func (r *Repository) PruneSpentOutPointBalances(ctx context.Context, prunedHeight uint64) (int64, error) {
+	count, err := r.queries.PruneSpentOutPointBalances(ctx, pgtype.Int4{Int32: int32(prunedHeight), Valid: true})
+	if err != nil {
+		return 0, errors.Wrap(err, "error during exec")
+	}
+	if err := r.queries.PruneOutPointSpends(ctx, int32(prunedHeight)); err != nil {
+		return 0, errors.Wrap(err, "error during exec PruneOutPointSpends")
+	}
+	return count, nil
+}
+
+func (r *Repository) PruneRuneBalances(ctx context.Context, prunedHeight uint64) (int64, error) {
+	count, err := r.queries.PruneRuneBalances(ctx, int32(prunedHeight))
+	if err != nil {
+		return 0, errors.Wrap(err, "error during exec")
+	}
+	return count, nil
+}
+
+func (r *Repository) SetPrunedHeight(ctx context.Context, prunedHeight uint64) error {
+	if err := r.queries.CreatePruneState(ctx, int32(prunedHeight)); err != nil {
+		return errors.Wrap(err, "error during exec")
+	}
+	return nil
+}
+
+func (r *Repository) CreatePartitions(ctx context.Context, fromHeight, toHeight uint64) (uint64, error) {
+	partitionEndHeight, err := r.queries.CreatePartitions(ctx, gen.CreatePartitionsParams{
+		FromHeight: int32(fromHeight),
+		ToHeight:   int32(toHeight),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "error during query")
+	}
+	return uint64(partitionEndHeight), nil
+}
@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/repository/postgres/gen"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedExec struct {
+	name string
+	sql  string
+	args []interface{}
+}
+
+// recordingDB records the statements executed through it. Queries are not supported.
+type recordingDB struct {
+	execs []recordedExec
+}
+
+var queryNameRegex = regexp.MustCompile(`^-- name: (\w+)`)
+
+func (d *recordingDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	var name string
+	if match := queryNameRegex.FindStringSubmatch(sql); match != nil {
+		name = match[1]
+	}
+	d.execs = append(d.execs, recordedExec{
+		name: name,
+		sql:  sql,
+		args: args,
+	})
+	return pgconn.CommandTag{}, nil
+}
+
+func (d *recordingDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	panic("not supported")
+}
+
+func (d *recordingDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	panic("not supported")
+}
+
+func (d *recordingDB) names() []string {
+	names := make([]string, 0, len(d.execs))
+	for _, exec := range d.execs {
+		names = append(names, exec.name)
+	}
+	return names
+}
+
+func newRecordingRepository() (*Repository, *recordingDB) {
+	db := &recordingDB{}
+	return &Repository{queries: gen.New(db)}, db
+}
+
+func TestUpdateCurrentBalances(t *testing.T) {
+	runeId := runes.RuneId{BlockHeight: 840000, TxIndex: 1}
+	nonZero := &entity.Balance{
+		PkScript:    []byte{0x01},
+		RuneId:      runeId,
+		Amount:      uint128.From64(100),
+		BlockHeight: 840001,
+	}
+	zero := &entity.Balance{
+		PkScript:    []byte{0x02},
+		RuneId:      runeId,
+		Amount:      uint128.Zero,
+		BlockHeight: 840001,
+	}
+
+	t.Run("upsert and delete", func(t *testing.T) {
+		repo, db := newRecordingRepository()
+		err := repo.UpdateCurrentBalances(context.Background(), []*entity.Balance{nonZero, zero})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"BatchUpsertCurrentBalances", "BatchDeleteCurrentBalances"}, db.names())
+
+		upsertArgs := db.execs[0].args
+		assert.Equal(t, []string{"01"}, upsertArgs[0])
+		assert.Equal(t, []string{runeId.String()}, upsertArgs[1])
+		assert.Equal(t, []int32{840001}, upsertArgs[3])
+		deleteArgs := db.execs[1].args
+		assert.Equal(t, []string{"02"}, deleteArgs[0])
+		assert.Equal(t, []string{runeId.String()}, deleteArgs[1])
+	})
+	t.Run("upsert only", func(t *testing.T) {
+		repo, db := newRecordingRepository()
+		err := repo.UpdateCurrentBalances(context.Background(), []*entity.Balance{nonZero})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"BatchUpsertCurrentBalances"}, db.names())
+	})
+	t.Run("delete only", func(t *testing.T) {
+		repo, db := newRecordingRepository()
+		err := repo.UpdateCurrentBalances(context.Background(), []*entity.Balance{zero})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"BatchDeleteCurrentBalances"}, db.names())
+	})
+	t.Run("empty", func(t *testing.T) {
+		repo, db := newRecordingRepository()
+		err := repo.UpdateCurrentBalances(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, db.execs)
+	})
+}
+
+func TestRevertCurrentBalancesSinceHeight(t *testing.T) {
+	repo, db := newRecordingRepository()
+	err := repo.RevertCurrentBalancesSinceHeight(context.Background(), 840001)
+	assert.NoError(t, err)
+
+	// changed balances are deleted first, then restored from the balance history before the height
+	assert.Equal(t, []string{"DeleteCurrentBalancesSinceHeight", "RestoreCurrentBalancesBeforeHeight"}, db.names())
+	for _, exec := range db.execs {
+		assert.Equal(t, []interface{}{int32(840001)}, exec.args)
+	}
+}
+
+func TestRevertOnlyModifiesRecentPartitions(t *testing.T) {
+	// partitionBounds is the predicate that bounds the partitions modified by each revert statement on a partitioned table.
+	// A statement that is missing here would scan every partition of the table.
+	partitionBounds := map[string]string{
+		"DeleteRuneTransactionsSinceHeight":  "block_height >= $1",
+		"DeleteRunestonesSinceHeight":        "block_height >= $1",
+		"DeleteOutPointBalancesSinceHeight":  "block_height >= $1",
+		"UnspendOutPointBalancesSinceHeight": "runes_outpoint_balances.block_height >= (SELECT COALESCE(MIN(block_height), $1) FROM runes_outpoint_spends",
+		"DeleteRuneBalancesSinceHeight":      "block_height >= $1",
+	}
+	partitionedTables := []string{"runes_transactions", "runes_runestones", "runes_outpoint_balances", "runes_balances"}
+
+	repo, db := newRecordingRepository()
+	ctx := context.Background()
+	const height = 840001
+	assert.NoError(t, repo.DeleteIndexedBlockSinceHeight(ctx, height))
+	assert.NoError(t, repo.DeleteRuneEntriesSinceHeight(ctx, height))
+	assert.NoError(t, repo.DeleteRuneEntryStatesSinceHeight(ctx, height))
+	assert.NoError(t, repo.DeleteRuneTransactionsSinceHeight(ctx, height))
+	assert.NoError(t, repo.DeleteRunestonesSinceHeight(ctx, height))
+	assert.NoError(t, repo.DeleteOutPointBalancesSinceHeight(ctx, height))
+	assert.NoError(t, repo.UnspendOutPointBalancesSinceHeight(ctx, height))
+	assert.NoError(t, repo.RevertCurrentBalancesSinceHeight(ctx, height))
+	assert.NoError(t, repo.DeleteRuneBalancesSinceHeight(ctx, height))
+
+	targetRegex := regexp.MustCompile(`(?m)^(?:DELETE FROM|UPDATE) (\w+)`)
+	modifiedTables := make(map[string]bool)
+	for _, exec := range db.execs {
+		match := targetRegex.FindStringSubmatch(exec.sql)
+		if match == nil {
+			continue
+		}
+		target := match[1]
+		if !slices.Contains(partitionedTables, target) {
+			continue
+		}
+		modifiedTables[target] = true
+		bound, ok := partitionBounds[exec.name]
+		if assert.True(t, ok, "%s modifies partitioned table %s without a partition bound", exec.name, target) {
+			assert.Contains(t, exec.sql, bound, "%s does not bound the partitions of %s", exec.name, target)
+		}
+	}
+	for _, table := range partitionedTables {
+		assert.True(t, modifiedTables[table], "revert does not modify %s", table)
+	}
+}
+
+func TestSpendOutPointBalancesBatch(t *testing.T) {
+	outPoint1 := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}
+	outPoint2 := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}
+	spendingTxHash := chainhash.Hash{3}
+
+	repo, db := newRecordingRepository()
+	err := repo.SpendOutPointBalancesBatch(context.Background(), []*entity.SpentOutPoint{
+		// an outpoint with two runes is spent once per rune
+		{OutPoint: outPoint1, BlockHeight: 840000, TxHash: spendingTxHash, InputIndex: 0},
+		{OutPoint: outPoint1, BlockHeight: 840000, TxHash: spendingTxHash, InputIndex: 0},
+		{OutPoint: outPoint2, BlockHeight: 850000, TxHash: spendingTxHash, InputIndex: 1},
+	}, 860000)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BatchSpendOutpointBalances", "BatchCreateOutPointSpends"}, db.names())
+
+	// the partitions are bounded by the block heights of the outpoints
+	spendArgs := db.execs[0].args
+	assert.Equal(t, []int32{840000, 840000, 850000}, spendArgs[3])
+	assert.Equal(t, int32(840000), spendArgs[6])
+	assert.Equal(t, int32(850000), spendArgs[7])
+
+	// each outpoint is recorded once
+	spendsArgs := db.execs[1].args
+	assert.Equal(t, []string{outPoint1.Hash.String(), outPoint2.Hash.String()}, spendsArgs[0])
+	assert.Equal(t, []int32{0, 1}, spendsArgs[1])
+	assert.Equal(t, []int32{840000, 850000}, spendsArgs[2])
+	assert.Equal(t, []int32{860000, 860000}, spendsArgs[3])
+}
+
+func TestSetOutPointValues(t *testing.T) {
+	repo, db := newRecordingRepository()
+	err := repo.SetOutPointValues(context.Background(), []*entity.OutPointValue{
+		{OutPoint: wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}, BlockHeight: 840000, Value: 546},
+		{OutPoint: wire.OutPoint{Hash: chainhash.Hash{2}, Index: 3}, BlockHeight: 850000, Value: 10000},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BatchSetOutPointValues"}, db.names())
+
+	args := db.execs[0].args
+	assert.Equal(t, []string{chainhash.Hash{1}.String(), chainhash.Hash{2}.String()}, args[0])
+	assert.Equal(t, []int32{0, 3}, args[1])
+	assert.Equal(t, []int32{840000, 850000}, args[2])
+	assert.Equal(t, []int64{546, 10000}, args[3])
+	// the partitions are bounded by the block heights of the outpoints
+	assert.Equal(t, int32(840000), args[4])
+	assert.Equal(t, int32(850000), args[5])
+	// recorded values are never overwritten
+	assert.Contains(t, db.execs[0].sql, `"runes_outpoint_balances"."value" IS NULL`)
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/repository/postgres/gen"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+)
+
+var _ datagateway.SnapshotDataGateway = (*Repository)(nil)
+
+func (r *Repository) GetSnapshotRuneEntries(ctx context.Context, blockHeight uint64, afterNumber int64, limit int32) ([]*runes.RuneEntry, error) {
+	rows, err := r.queries.GetSnapshotRuneEntries(ctx, gen.GetSnapshotRuneEntriesParams{
+		Height:      int32(blockHeight),
+		AfterNumber: afterNumber,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	runeEntries := make([]*runes.RuneEntry, 0, len(rows))
+	for _, row := range rows {
+		runeEntry, err := mapRuneEntryModelToType(gen.GetRuneEntriesRow(row))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse rune entry model")
+		}
+		runeEntries = append(runeEntries, &runeEntry)
+	}
+	return runeEntries, nil
+}
+
+func (r *Repository) GetSnapshotOutPointBalances(ctx context.Context, blockHeight uint64, after *entity.OutPointBalance, limit int32) ([]*entity.OutPointBalance, error) {
+	params := gen.GetSnapshotOutPointBalancesParams{
+		BlockHeight: int32(blockHeight),
+		AfterTxIdx:  -1,
+		Limit:       limit,
+	}
+	if after != nil {
+		params.AfterTxHash = after.OutPoint.Hash.String()
+		params.AfterTxIdx = int32(after.OutPoint.Index)
+		params.AfterRuneID = after.RuneId.String()
+	}
+	rows, err := r.queries.GetSnapshotOutPointBalances(ctx, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	outPointBalances, err := mapOutPointBalanceModelsToTypes(rows)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return outPointBalances, nil
+}
+
+func (r *Repository) GetSnapshotBalances(ctx context.Context, blockHeight uint64, after *entity.Balance, limit int32) ([]*entity.Balance, error) {
+	params := gen.GetSnapshotBalancesParams{
+		BlockHeight: int32(blockHeight),
+		Limit:       limit,
+	}
+	if after != nil {
+		params.AfterPkscript = hex.EncodeToString(after.PkScript)
+		params.AfterRuneID = after.RuneId.String()
+	}
+	rows, err := r.queries.GetSnapshotBalances(ctx, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "error during query")
+	}
+
+	balances := make([]*entity.Balance, 0, len(rows))
+	for _, row := range rows {
+		balance, err := mapBalanceModelToType(row)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse balance model")
+		}
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
@@ -26,6 +26,26 @@ func (r *Repository) begin(ctx context.Context) (*Repository, error) {
 	}, nil
 }
 
+// beginReadSnapshot begins a read-only REPEATABLE READ transaction, so that all reads in it see the same snapshot of the database.
+// The transaction must be rolled back after use.
+func (r *Repository) beginReadSnapshot(ctx context.Context) (*Repository, error) {
+	if r.tx != nil {
+		return nil, errors.WithStack(ErrTxAlreadyExists)
+	}
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	return &Repository{
+		db:      r.db,
+		queries: r.queries.WithTx(tx),
+		tx:      tx,
+	}, nil
+}
+
 func (r *Repository) BeginRunesTx(ctx context.Context) (datagateway.RunesDataGatewayWithTx, error) {
 	repo, err := r.begin(ctx)
 	if err != nil {
@@ -34,6 +54,14 @@ func (r *Repository) BeginRunesTx(ctx context.Context) (datagateway.RunesDataGat
 	return repo, nil
 }
 
+func (r *Repository) BeginSnapshotReaderTx(ctx context.Context) (datagateway.SnapshotReaderDataGatewayWithTx, error) {
+	repo, err := r.beginReadSnapshot(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return repo, nil
+}
+
 func (r *Repository) Commit(ctx context.Context) error {
 	if r.tx == nil {
 		return nil
@@ -13,11 +13,14 @@ import (
 	"github.com/gaze-network/indexer-network/internal/config"
 	"github.com/gaze-network/indexer-network/internal/postgres"
 	runesapi "github.com/gaze-network/indexer-network/modules/runes/api"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	runesmigrations "github.com/gaze-network/indexer-network/modules/runes/database/postgresql/migrations"
 	runesdatagateway "github.com/gaze-network/indexer-network/modules/runes/datagateway"
 	runespostgres "github.com/gaze-network/indexer-network/modules/runes/repository/postgres"
 	runesusecase "github.com/gaze-network/indexer-network/modules/runes/usecase"
 	"github.com/gaze-network/indexer-network/pkg/btcclient"
 	"github.com/gaze-network/indexer-network/pkg/logger"
+	"github.com/gaze-network/indexer-network/pkg/logger/slogx"
 	"github.com/gaze-network/indexer-network/pkg/reportingclient"
 	"github.com/gofiber/fiber/v2"
 	"github.com/samber/do/v2"
@@ -31,9 +34,16 @@ func New(injector do.Injector) (indexer.IndexerWorker, error) {
 
 	var (
 		runesDg       runesdatagateway.RunesDataGateway
+		runesReaderDg runesdatagateway.RunesReaderDataGateway
 		indexerInfoDg runesdatagateway.IndexerInfoDataGateway
 	)
 	var cleanupFuncs []func(context.Context) error
+	// cleanup releases the resources acquired so far if the indexer can't be created
+	cleanup := func() {
+		for _, cleanupFunc := range cleanupFuncs {
+			_ = cleanupFunc(ctx)
+		}
+	}
 	switch strings.ToLower(conf.Modules.Runes.Database) {
 	case "postgresql", "postgres", "pg":
 		pg, err := postgres.NewPool(ctx, conf.Modules.Runes.Postgres)
@@ -43,13 +53,63 @@ func New(injector do.Injector) (indexer.IndexerWorker, error) {
 			}
 			return nil, errors.Wrap(err, "can't create Postgres connection pool")
 		}
+		if !conf.APIOnly {
+			releaseLock, ok, err := postgres.TryAdvisoryLock(ctx, pg, postgres.SchemaLockKey(constants.IndexerLockKey, conf.Modules.Runes.Postgres.Schema))
+			if err != nil {
+				pg.Close()
+				return nil, errors.Wrap(err, "can't acquire runes indexer lock")
+			}
+			if !ok {
+				pg.Close()
+				return nil, errors.Wrap(errs.InvalidState, "another runes indexer or maintenance command is running on the database")
+			}
+			// the lock must be released before closing the pool
+			cleanupFuncs = append(cleanupFuncs, releaseLock)
+
+			if conf.Modules.Runes.AutoMigrate {
+				if schema := conf.Modules.Runes.Postgres.Schema; schema != "" {
+					if err := postgres.CreateSchema(ctx, pg, schema); err != nil {
+						_ = releaseLock(ctx)
+						pg.Close()
+						return nil, errors.Wrap(err, "can't create runes database schema")
+					}
+				}
+				if err := postgres.MigrateUpTo(ctx, pg, runesmigrations.FS, runesmigrations.MigrationsTable, constants.DBVersion); err != nil {
+					_ = releaseLock(ctx)
+					pg.Close()
+					return nil, errors.Wrap(err, "can't migrate runes database")
+				}
+			}
+		}
 		cleanupFuncs = append(cleanupFuncs, func(ctx context.Context) error {
 			pg.Close()
 			return nil
 		})
 		runesRepo := runespostgres.NewRepository(pg)
 		runesDg = runesRepo
+		runesReaderDg = runesRepo
 		indexerInfoDg = runesRepo
+
+		if replicaConfs := conf.Modules.Runes.Postgres.ReadReplicas; len(replicaConfs) > 0 {
+			replicaRepos := make([]*runespostgres.Repository, 0, len(replicaConfs))
+			for i, replicaConf := range replicaConfs {
+				if replicaConf.Schema == "" {
+					replicaConf.Schema = conf.Modules.Runes.Postgres.Schema
+				}
+				replicaPg, err := postgres.NewPool(ctx, replicaConf)
+				if err != nil {
+					cleanup()
+					return nil, errors.Wrapf(err, "can't create Postgres connection pool for read replica #%d", i)
+				}
+				cleanupFuncs = append(cleanupFuncs, func(ctx context.Context) error {
+					replicaPg.Close()
+					return nil
+				})
+				replicaRepos = append(replicaRepos, runespostgres.NewRepository(replicaPg))
+			}
+			runesReaderDg = runespostgres.NewReplicaRouter(runesRepo, replicaRepos)
+			logger.InfoContext(ctx, "Routing API queries to read replicas", slogx.Int("replicas", len(replicaRepos)))
+		}
 	default:
 		return nil, errors.Wrapf(errs.Unsupported, "%q database for indexer is not supported", conf.Modules.Runes.Database)
 	}
@@ -63,12 +123,14 @@ func New(injector do.Injector) (indexer.IndexerWorker, error) {
 		bitcoinDatasource = bitcoinNodeDatasource
 		bitcoinClient = bitcoinNodeDatasource
 	default:
+		cleanup()
 		return nil, errors.Wrapf(errs.Unsupported, "%q datasource is not supported", conf.Modules.Runes.Datasource)
 	}
 
-	processor := NewProcessor(runesDg, indexerInfoDg, bitcoinClient, conf.Network, reportingClient, cleanupFuncs)
+	processor := NewProcessor(runesDg, indexerInfoDg, bitcoinClient, conf.Network, reportingClient, conf.Modules.Runes.Pruning, cleanupFuncs)
 	if !conf.APIOnly {
 		if err := processor.VerifyStates(ctx); err != nil {
+			cleanup()
 			return nil, errors.WithStack(err)
 		}
 	}
@@ -78,13 +140,15 @@ func New(injector do.Injector) (indexer.IndexerWorker, error) {
 		switch handler { // TODO: support more handlers (e.g. gRPC)
 		case "http":
 			httpServer := do.MustInvoke[*fiber.App](injector)
-			runesUsecase := runesusecase.New(runesDg, bitcoinClient)
+			runesUsecase := runesusecase.New(runesReaderDg, bitcoinClient)
 			runesHTTPHandler := runesapi.NewHTTPHandler(conf.Network, runesUsecase)
 			if err := runesHTTPHandler.Mount(httpServer); err != nil {
+				cleanup()
 				return nil, errors.Wrap(err, "can't mount Runes API")
 			}
 			logger.InfoContext(ctx, "Mounted HTTP handler")
 		default:
+			cleanup()
 			return nil, errors.Wrapf(errs.Unsupported, "%q API handler is not supported", handler)
 		}
 	}
@@ -3,6 +3,7 @@ package runes
 import (
 	"fmt"
 	"slices"
+	"sort"
 
 	"github.com/Cleverse/go-utilities/utils"
 	"github.com/cockroachdb/errors"
@@ -159,6 +160,30 @@ func MinimumRuneAtHeight(network common.Network, height uint64) Rune {
 	return Rune(result)
 }
 
+// UnlockHeight returns the first block height that the rune can be etched at on the network, as limited by MinimumRuneAtHeight.
+func UnlockHeight(network common.Network, rune Rune) uint64 {
+	start := FirstRuneHeight(network)
+	end := start + network.HalvingInterval()
+
+	// the minimum rune never increases with height, and every rune is unlocked at the end
+	offset := sort.Search(int(end-start), func(i int) bool {
+		return MinimumRuneAtHeight(network, start+uint64(i)).Cmp(rune) <= 0
+	})
+	return start + uint64(offset)
+}
+
+// UnlockHeightsOfLength returns the first block heights that the first name and every name of the given length can be etched at on the network.
+// Names of 13 or more characters can all be etched from FirstRuneHeight.
+func UnlockHeightsOfLength(network common.Network, length int) (first uint64, all uint64) {
+	if length < 1 || length > 12 {
+		start := FirstRuneHeight(network)
+		return start, start
+	}
+	// the largest name of the length unlocks first, e.g. ZZ before AA
+	last := Rune(unlockSteps[length].Sub64(1))
+	return UnlockHeight(network, last), UnlockHeight(network, Rune(unlockSteps[length-1]))
+}
+
 func GetReservedRune(blockHeight uint64, txIndex uint32) Rune {
 	// firstReservedRune + ((blockHeight << 32) | txIndex)
 	delta := uint128.From64(blockHeight).Lsh(32).Or64(uint64(txIndex))
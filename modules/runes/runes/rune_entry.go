@@ -70,6 +70,23 @@ func (e *RuneEntry) IsMintStarted(height uint64) bool {
 	if e.Terms == nil {
 		return false
 	}
+	return height >= e.MintStartHeight()
+}
+
+func (e *RuneEntry) IsMintEnded(height uint64) bool {
+	if e.Terms == nil {
+		return false
+	}
+	endHeight, ok := e.MintEndHeight()
+	return ok && height >= endHeight
+}
+
+// MintStartHeight returns the first block height that the rune can be minted at, from the height and offset terms.
+// Returns 0 if the rune has no terms.
+func (e *RuneEntry) MintStartHeight() uint64 {
+	if e.Terms == nil {
+		return 0
+	}
 
 	var relative, absolute uint64
 	if e.Terms.OffsetStart != nil {
@@ -79,12 +96,14 @@ func (e *RuneEntry) IsMintStarted(height uint64) bool {
 		absolute = *e.Terms.HeightStart
 	}
 
-	return height >= max(relative, absolute)
+	return max(relative, absolute)
 }
 
-func (e *RuneEntry) IsMintEnded(height uint64) bool {
+// MintEndHeight returns the first block height that the rune can no longer be minted at, from the height and offset terms.
+// Returns false if minting never ends or the rune has no terms.
+func (e *RuneEntry) MintEndHeight() (uint64, bool) {
 	if e.Terms == nil {
-		return false
+		return 0, false
 	}
 
 	var relative, absolute uint64 = math.MaxUint64, math.MaxUint64
@@ -95,7 +114,8 @@ func (e *RuneEntry) IsMintEnded(height uint64) bool {
 		absolute = *e.Terms.HeightEnd
 	}
 
-	return height >= min(relative, absolute)
+	endHeight := min(relative, absolute)
+	return endHeight, endHeight != math.MaxUint64
 }
 
 func (e RuneEntry) Supply() (uint128.Uint128, error) {
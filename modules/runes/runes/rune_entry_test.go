@@ -0,0 +1,77 @@
+package runes
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuneEntryMintHeights(t *testing.T) {
+	type testcase struct {
+		name              string
+		terms             *Terms
+		expectedStart     uint64
+		expectedEnd       uint64
+		expectedEndExists bool
+	}
+	runeId := RuneId{BlockHeight: 840000, TxIndex: 1}
+	testcases := []testcase{
+		{
+			name:  "no terms",
+			terms: nil,
+		},
+		{
+			name:          "no height terms",
+			terms:         &Terms{},
+			expectedStart: 0,
+		},
+		{
+			name: "height terms",
+			terms: &Terms{
+				HeightStart: lo.ToPtr(uint64(840100)),
+				HeightEnd:   lo.ToPtr(uint64(840200)),
+			},
+			expectedStart:     840100,
+			expectedEnd:       840200,
+			expectedEndExists: true,
+		},
+		{
+			name: "offset terms",
+			terms: &Terms{
+				OffsetStart: lo.ToPtr(uint64(10)),
+				OffsetEnd:   lo.ToPtr(uint64(20)),
+			},
+			expectedStart:     840010,
+			expectedEnd:       840020,
+			expectedEndExists: true,
+		},
+		{
+			name: "start is the later and end is the earlier of height and offset",
+			terms: &Terms{
+				HeightStart: lo.ToPtr(uint64(840100)),
+				HeightEnd:   lo.ToPtr(uint64(840200)),
+				OffsetStart: lo.ToPtr(uint64(10)),
+				OffsetEnd:   lo.ToPtr(uint64(20)),
+			},
+			expectedStart:     840100,
+			expectedEnd:       840020,
+			expectedEndExists: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := &RuneEntry{
+				RuneId: runeId,
+				Terms:  tc.terms,
+			}
+			assert.Equal(t, tc.expectedStart, entry.MintStartHeight())
+			end, ok := entry.MintEndHeight()
+			assert.Equal(t, tc.expectedEndExists, ok)
+			if ok {
+				assert.Equal(t, tc.expectedEnd, end)
+			}
+		})
+	}
+}
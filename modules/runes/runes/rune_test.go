@@ -233,6 +233,43 @@ func TestUnlockSteps(t *testing.T) {
 	}
 }
 
+func TestUnlockHeight(t *testing.T) {
+	test := func(network common.Network, runeStr string) {
+		t.Run(fmt.Sprintf("%s_%s", network, runeStr), func(t *testing.T) {
+			t.Parallel()
+			rune, err := NewRuneFromString(runeStr)
+			assert.NoError(t, err)
+			height := UnlockHeight(network, rune)
+			assert.LessOrEqual(t, MinimumRuneAtHeight(network, height).Cmp(rune), 0)
+			if height > FirstRuneHeight(network) {
+				assert.Greater(t, MinimumRuneAtHeight(network, height-1).Cmp(rune), 0)
+			}
+		})
+	}
+
+	for _, network := range []common.Network{common.NetworkMainnet, common.NetworkTestnet} {
+		test(network, "A")
+		test(network, "ZZ")
+		test(network, "AAAA")
+		test(network, "UNCOMMONGOODS")
+		test(network, "ZZYZXBRKWXVA")
+		test(network, "AAAAAAAAAAAA")
+		test(network, "AAAAAAAAAAAAA")
+	}
+
+	start := FirstRuneHeight(common.NetworkMainnet)
+	end := start + common.NetworkMainnet.HalvingInterval()
+	assert.Equal(t, start, UnlockHeight(common.NetworkMainnet, utils.Must(NewRuneFromString("AAAAAAAAAAAAA"))))
+	assert.Equal(t, end-1, UnlockHeight(common.NetworkMainnet, utils.Must(NewRuneFromString("A"))))
+
+	first, all := UnlockHeightsOfLength(common.NetworkMainnet, 1)
+	assert.Equal(t, UnlockHeight(common.NetworkMainnet, utils.Must(NewRuneFromString("Z"))), first)
+	assert.Equal(t, end-1, all)
+	first, all = UnlockHeightsOfLength(common.NetworkMainnet, 13)
+	assert.Equal(t, start, first)
+	assert.Equal(t, start, all)
+}
+
 func TestCommitment(t *testing.T) {
 	test := func(rune Rune, expected []byte) {
 		t.Run(rune.String(), func(t *testing.T) {
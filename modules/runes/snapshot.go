@@ -0,0 +1,739 @@
+package runes
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/indexer-network/pkg/logger"
+	"github.com/gaze-network/indexer-network/pkg/logger/slogx"
+	"github.com/gaze-network/uint128"
+)
+
+// SnapshotVersion is the version of the snapshot file format. It must be bumped whenever the format changes.
+const SnapshotVersion = 1
+
+// snapshotPageSize is the number of rows to load or insert at a time when exporting or importing a snapshot.
+const snapshotPageSize = 10000
+
+// A snapshot is a gzip-compressed stream of newline-delimited JSON records. The first record is the header,
+// and the last record is the sha256 checksum of all previous (uncompressed) lines.
+type snapshotRecordType string
+
+const (
+	snapshotRecordHeader          snapshotRecordType = "header"
+	snapshotRecordIndexedBlock    snapshotRecordType = "indexed_block"
+	snapshotRecordRuneEntry       snapshotRecordType = "rune_entry"
+	snapshotRecordOutPointBalance snapshotRecordType = "outpoint_balance"
+	snapshotRecordBalance         snapshotRecordType = "balance"
+	snapshotRecordChecksum        snapshotRecordType = "checksum"
+)
+
+type snapshotRecord struct {
+	Type snapshotRecordType `json:"type"`
+	Data json.RawMessage    `json:"data"`
+}
+
+// SnapshotHeader describes the runes state contained in a snapshot.
+type SnapshotHeader struct {
+	Version             int            `json:"version"`
+	Network             common.Network `json:"network"`
+	EventHashVersion    int            `json:"eventHashVersion"`
+	Height              int64          `json:"height"`
+	Hash                chainhash.Hash `json:"hash"`
+	CumulativeEventHash chainhash.Hash `json:"cumulativeEventHash"`
+	CreatedAt           time.Time      `json:"createdAt"`
+}
+
+type snapshotIndexedBlock struct {
+	Height              int64          `json:"height"`
+	Hash                chainhash.Hash `json:"hash"`
+	PrevHash            chainhash.Hash `json:"prevHash"`
+	EventHash           chainhash.Hash `json:"eventHash"`
+	CumulativeEventHash chainhash.Hash `json:"cumulativeEventHash"`
+}
+
+type snapshotTerms struct {
+	Amount      *uint128.Uint128 `json:"amount,omitempty"`
+	Cap         *uint128.Uint128 `json:"cap,omitempty"`
+	HeightStart *uint64          `json:"heightStart,omitempty"`
+	HeightEnd   *uint64          `json:"heightEnd,omitempty"`
+	OffsetStart *uint64          `json:"offsetStart,omitempty"`
+	OffsetEnd   *uint64          `json:"offsetEnd,omitempty"`
+}
+
+type snapshotRuneEntry struct {
+	RuneId            string          `json:"runeId"`
+	Number            uint64          `json:"number"`
+	Divisibility      uint8           `json:"divisibility"`
+	Premine           uint128.Uint128 `json:"premine"`
+	Rune              string          `json:"rune"`
+	Spacers           uint32          `json:"spacers"`
+	Symbol            int32           `json:"symbol"`
+	Terms             *snapshotTerms  `json:"terms,omitempty"`
+	Turbo             bool            `json:"turbo"`
+	Mints             uint128.Uint128 `json:"mints"`
+	BurnedAmount      uint128.Uint128 `json:"burnedAmount"`
+	CompletedAt       *time.Time      `json:"completedAt,omitempty"`
+	CompletedAtHeight *uint64         `json:"completedAtHeight,omitempty"`
+	EtchingBlock      uint64          `json:"etchingBlock"`
+	EtchingTxHash     chainhash.Hash  `json:"etchingTxHash"`
+	EtchedAt          time.Time       `json:"etchedAt"`
+}
+
+type snapshotOutPointBalance struct {
+	TxHash      chainhash.Hash  `json:"txHash"`
+	TxOutIndex  uint32          `json:"txOutIndex"`
+	PkScript    string          `json:"pkScript"`
+	RuneId      string          `json:"runeId"`
+	Amount      uint128.Uint128 `json:"amount"`
+	BlockHeight uint64          `json:"blockHeight"`
+	Value       *int64          `json:"value,omitempty"`
+}
+
+type snapshotBalance struct {
+	PkScript    string          `json:"pkScript"`
+	RuneId      string          `json:"runeId"`
+	Amount      uint128.Uint128 `json:"amount"`
+	BlockHeight uint64          `json:"blockHeight"`
+}
+
+type snapshotChecksum struct {
+	SHA256 string `json:"sha256"`
+}
+
+// Snapshotter exports the runes state at a block height to a snapshot, and imports a snapshot into an empty database.
+// A snapshot contains everything needed to continue indexing from its height, but not the rune transactions history.
+type Snapshotter struct {
+	runesDg     datagateway.RunesDataGateway
+	snapshotDg  datagateway.SnapshotDataGateway
+	integrityDg datagateway.IntegrityDataGateway
+	network     common.Network
+}
+
+func NewSnapshotter(runesDg datagateway.RunesDataGateway, snapshotDg datagateway.SnapshotDataGateway, integrityDg datagateway.IntegrityDataGateway, network common.Network) *Snapshotter {
+	return &Snapshotter{
+		runesDg:     runesDg,
+		snapshotDg:  snapshotDg,
+		integrityDg: integrityDg,
+		network:     network,
+	}
+}
+
+// Export writes a snapshot of the runes state at the given block height to w.
+// Use blockHeight = -1 to export at the latest indexed block height.
+// All data is read in a single read-only transaction, so that blocks indexed or reverted during the export don't change it.
+func (s *Snapshotter) Export(ctx context.Context, w io.Writer, blockHeight int64) (*SnapshotHeader, error) {
+	snapshotDgTx, err := s.snapshotDg.BeginSnapshotReaderTx(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin snapshot transaction")
+	}
+	defer func() {
+		if err := snapshotDgTx.Rollback(ctx); err != nil {
+			logger.WarnContext(ctx, "failed to rollback transaction", slogx.Error(err))
+		}
+	}()
+
+	latestBlock, err := snapshotDgTx.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest block")
+	}
+	if blockHeight == -1 {
+		blockHeight = latestBlock.Height
+	}
+	if blockHeight > latestBlock.Height {
+		return nil, errors.Wrapf(errs.InvalidArgument, "block height %d is higher than the latest indexed block height %d", blockHeight, latestBlock.Height)
+	}
+	prunedHeight, err := snapshotDgTx.GetPrunedHeight(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pruned height")
+	}
+	if blockHeight < int64(prunedHeight) {
+		return nil, errors.Wrapf(errs.InvalidArgument, "block height %d is pruned, earliest available block height is %d", blockHeight, prunedHeight)
+	}
+	indexedBlock, err := snapshotDgTx.GetIndexedBlockByHeight(ctx, blockHeight)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get indexed block at height %d", blockHeight)
+	}
+
+	header := &SnapshotHeader{
+		Version:             SnapshotVersion,
+		Network:             s.network,
+		EventHashVersion:    constants.EventHashVersion,
+		Height:              blockHeight,
+		Hash:                indexedBlock.Hash,
+		CumulativeEventHash: indexedBlock.CumulativeEventHash,
+		CreatedAt:           time.Now().UTC(),
+	}
+
+	gzipWriter := gzip.NewWriter(w)
+	sw := newSnapshotWriter(gzipWriter)
+	if err := sw.write(snapshotRecordHeader, header); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// indexed blocks
+	var count int
+	for fromHeight := constants.StartingBlockHeader[s.network].Height + 1; fromHeight <= blockHeight; fromHeight += snapshotPageSize {
+		toHeight := min(fromHeight+snapshotPageSize-1, blockHeight)
+		indexedBlocks, err := snapshotDgTx.GetIndexedBlocksInRange(ctx, fromHeight, toHeight)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get indexed blocks")
+		}
+		for _, indexedBlock := range indexedBlocks {
+			if err := sw.write(snapshotRecordIndexedBlock, snapshotIndexedBlock(*indexedBlock)); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		count += len(indexedBlocks)
+	}
+	logger.InfoContext(ctx, "Exported indexed blocks", slog.Int("count", count))
+
+	// rune entries
+	count = 0
+	for afterNumber := int64(-1); ; {
+		runeEntries, err := snapshotDgTx.GetSnapshotRuneEntries(ctx, uint64(blockHeight), afterNumber, snapshotPageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get rune entries")
+		}
+		for _, runeEntry := range runeEntries {
+			if err := sw.write(snapshotRecordRuneEntry, mapRuneEntryToSnapshot(runeEntry)); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		count += len(runeEntries)
+		if len(runeEntries) < snapshotPageSize {
+			break
+		}
+		afterNumber = int64(runeEntries[len(runeEntries)-1].Number)
+	}
+	logger.InfoContext(ctx, "Exported rune entries", slog.Int("count", count))
+
+	// unspent outpoint balances
+	count = 0
+	var afterOutPointBalance *entity.OutPointBalance
+	for {
+		outPointBalances, err := snapshotDgTx.GetSnapshotOutPointBalances(ctx, uint64(blockHeight), afterOutPointBalance, snapshotPageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get outpoint balances")
+		}
+		for _, outPointBalance := range outPointBalances {
+			if err := sw.write(snapshotRecordOutPointBalance, snapshotOutPointBalance{
+				TxHash:      outPointBalance.OutPoint.Hash,
+				TxOutIndex:  outPointBalance.OutPoint.Index,
+				PkScript:    hex.EncodeToString(outPointBalance.PkScript),
+				RuneId:      outPointBalance.RuneId.String(),
+				Amount:      outPointBalance.Amount,
+				BlockHeight: outPointBalance.BlockHeight,
+				Value:       outPointBalance.Value,
+			}); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		count += len(outPointBalances)
+		if len(outPointBalances) < snapshotPageSize {
+			break
+		}
+		afterOutPointBalance = outPointBalances[len(outPointBalances)-1]
+	}
+	logger.InfoContext(ctx, "Exported outpoint balances", slog.Int("count", count))
+
+	// latest balances
+	count = 0
+	var afterBalance *entity.Balance
+	for {
+		balances, err := snapshotDgTx.GetSnapshotBalances(ctx, uint64(blockHeight), afterBalance, snapshotPageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get balances")
+		}
+		for _, balance := range balances {
+			if err := sw.write(snapshotRecordBalance, snapshotBalance{
+				PkScript:    hex.EncodeToString(balance.PkScript),
+				RuneId:      balance.RuneId.String(),
+				Amount:      balance.Amount,
+				BlockHeight: balance.BlockHeight,
+			}); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		count += len(balances)
+		if len(balances) < snapshotPageSize {
+			break
+		}
+		afterBalance = balances[len(balances)-1]
+	}
+	logger.InfoContext(ctx, "Exported balances", slog.Int("count", count))
+
+	if err := sw.writeChecksum(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close gzip writer")
+	}
+	return header, nil
+}
+
+// Import loads a snapshot from r into an empty database, then verifies the stored indexed blocks and cumulative event hashes.
+// The snapshot height is recorded as the pruned height, since the history before it isn't part of the snapshot.
+func (s *Snapshotter) Import(ctx context.Context, r io.Reader) (*SnapshotHeader, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gzip reader")
+	}
+	defer gzipReader.Close()
+	sr := newSnapshotReader(gzipReader)
+
+	record, err := sr.read()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if record.Type != snapshotRecordHeader {
+		return nil, errors.Errorf("invalid snapshot: first record must be %q, got %q", snapshotRecordHeader, record.Type)
+	}
+	var header SnapshotHeader
+	if err := json.Unmarshal(record.Data, &header); err != nil {
+		return nil, errors.Wrap(err, "failed to parse snapshot header")
+	}
+	if header.Version != SnapshotVersion {
+		return nil, errors.Wrapf(errs.Unsupported, "snapshot version %d is not supported, expected %d", header.Version, SnapshotVersion)
+	}
+	if header.Network != s.network {
+		return nil, errors.Wrapf(errs.InvalidArgument, "snapshot network %q doesn't match configured network %q", header.Network, s.network)
+	}
+	if header.EventHashVersion != constants.EventHashVersion {
+		return nil, errors.Wrapf(errs.Unsupported, "snapshot event hash version %d is not supported, expected %d", header.EventHashVersion, constants.EventHashVersion)
+	}
+
+	if err := s.ensureEmpty(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// partitions are created outside of the import transaction, since creating a partition locks the parent table
+	if _, err := s.runesDg.CreatePartitions(ctx, uint64(constants.StartingBlockHeader[s.network].Height+1), uint64(header.Height)); err != nil {
+		return nil, errors.Wrap(err, "failed to create partitions")
+	}
+
+	runesDgTx, err := s.runesDg.BeginRunesTx(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin runes tx")
+	}
+	defer func() {
+		if err := runesDgTx.Rollback(ctx); err != nil {
+			logger.WarnContext(ctx, "failed to rollback transaction",
+				slogx.Error(err),
+				slogx.String("event", "rollback_runes_snapshot_import"),
+			)
+		}
+	}()
+
+	si := &snapshotImporter{
+		runesDgTx:   runesDgTx,
+		blockHeight: uint64(header.Height),
+	}
+	if err := sr.readRecords(func(record *snapshotRecord) error {
+		return si.add(ctx, record)
+	}); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := si.flush(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := runesDgTx.SetPrunedHeight(ctx, uint64(header.Height)); err != nil {
+		return nil, errors.Wrap(err, "failed to set pruned height")
+	}
+	if err := runesDgTx.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to commit runes tx")
+	}
+	logger.InfoContext(ctx, "Imported snapshot",
+		slog.Int64("height", header.Height),
+		slog.Int("indexed_blocks", si.indexedBlocksCount),
+		slog.Int("rune_entries", si.runeEntriesCount),
+		slog.Int("outpoint_balances", si.outPointBalancesCount),
+		slog.Int("balances", si.balancesCount),
+	)
+
+	if err := s.verifyImport(ctx, &header); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &header, nil
+}
+
+// ensureEmpty returns an error if the database already contains runes data.
+func (s *Snapshotter) ensureEmpty(ctx context.Context) error {
+	_, err := s.runesDg.GetLatestBlock(ctx)
+	if err == nil {
+		return errors.Wrap(errs.InvalidArgument, "database is not empty: indexed blocks found")
+	}
+	if !errors.Is(err, errs.NotFound) {
+		return errors.Wrap(err, "failed to get latest block")
+	}
+	runeEntriesCount, err := s.runesDg.CountRuneEntries(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to count rune entries")
+	}
+	if runeEntriesCount > 0 {
+		return errors.Wrap(errs.InvalidArgument, "database is not empty: rune entries found")
+	}
+	return nil
+}
+
+// verifyImport verifies that the imported indexed blocks chain correctly up to the cumulative event hash of the snapshot header.
+func (s *Snapshotter) verifyImport(ctx context.Context, header *SnapshotHeader) error {
+	indexedBlock, err := s.runesDg.GetIndexedBlockByHeight(ctx, header.Height)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get indexed block at height %d", header.Height)
+	}
+	if indexedBlock.CumulativeEventHash != header.CumulativeEventHash {
+		return errors.Errorf("stored cumulative event hash %s doesn't match snapshot cumulative event hash %s", indexedBlock.CumulativeEventHash, header.CumulativeEventHash)
+	}
+	checker := NewIntegrityChecker(s.runesDg, s.integrityDg, s.network)
+	violations, err := checker.checkIndexedBlocks(ctx, header.Height)
+	if err != nil {
+		return errors.Wrap(err, "failed to check indexed blocks")
+	}
+	if len(violations) > 0 {
+		details := make([]string, 0, len(violations))
+		for _, violation := range violations {
+			details = append(details, violation.String())
+		}
+		return errors.Errorf("imported indexed blocks are invalid:\n%s", strings.Join(details, "\n"))
+	}
+	return nil
+}
+
+type snapshotWriter struct {
+	w    io.Writer
+	hash hash.Hash
+}
+
+func newSnapshotWriter(w io.Writer) *snapshotWriter {
+	return &snapshotWriter{
+		w:    w,
+		hash: sha256.New(),
+	}
+}
+
+// write writes a record and adds it to the checksum.
+func (w *snapshotWriter) write(recordType snapshotRecordType, data any) error {
+	line, err := marshalSnapshotRecord(recordType, data)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	w.hash.Write(line)
+	if _, err := w.w.Write(line); err != nil {
+		return errors.Wrap(err, "failed to write snapshot")
+	}
+	return nil
+}
+
+// writeChecksum writes the checksum of all previously written records. The checksum line itself is not part of the checksum.
+func (w *snapshotWriter) writeChecksum() error {
+	line, err := marshalSnapshotRecord(snapshotRecordChecksum, snapshotChecksum{SHA256: hex.EncodeToString(w.hash.Sum(nil))})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := w.w.Write(line); err != nil {
+		return errors.Wrap(err, "failed to write snapshot")
+	}
+	return nil
+}
+
+// marshalSnapshotRecord returns the newline-terminated line of a record.
+func marshalSnapshotRecord(recordType snapshotRecordType, data any) ([]byte, error) {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal %s record", recordType)
+	}
+	line, err := json.Marshal(snapshotRecord{
+		Type: recordType,
+		Data: dataBytes,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal %s record", recordType)
+	}
+	return append(line, '\n'), nil
+}
+
+type snapshotReader struct {
+	r    *bufio.Reader
+	hash hash.Hash
+	// sum is the checksum of all lines before the last read line
+	sum []byte
+}
+
+func newSnapshotReader(r io.Reader) *snapshotReader {
+	return &snapshotReader{
+		r:    bufio.NewReader(r),
+		hash: sha256.New(),
+	}
+}
+
+// read reads the next record. Returns io.EOF if there are no more records.
+func (r *snapshotReader) read() (*snapshotRecord, error) {
+	r.sum = r.hash.Sum(nil)
+	line, err := r.r.ReadBytes('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			if len(line) == 0 {
+				return nil, errors.WithStack(io.EOF)
+			}
+			return nil, errors.New("invalid snapshot: truncated record")
+		}
+		return nil, errors.Wrap(err, "failed to read snapshot")
+	}
+	r.hash.Write(line)
+	var record snapshotRecord
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, errors.Wrap(err, "invalid snapshot: failed to parse record")
+	}
+	return &record, nil
+}
+
+// readRecords calls fn with every record up to the checksum record, then verifies the checksum and that no data follows it.
+func (r *snapshotReader) readRecords(fn func(record *snapshotRecord) error) error {
+	for {
+		record, err := r.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return errors.New("invalid snapshot: missing checksum")
+			}
+			return errors.WithStack(err)
+		}
+		if record.Type == snapshotRecordChecksum {
+			if err := r.verifyChecksum(record); err != nil {
+				return errors.WithStack(err)
+			}
+			break
+		}
+		if err := fn(record); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if _, err := r.read(); !errors.Is(err, io.EOF) {
+		return errors.New("invalid snapshot: unexpected data after checksum")
+	}
+	return nil
+}
+
+// verifyChecksum verifies the checksum record against the checksum of all records read before it.
+func (r *snapshotReader) verifyChecksum(record *snapshotRecord) error {
+	var checksum snapshotChecksum
+	if err := json.Unmarshal(record.Data, &checksum); err != nil {
+		return errors.Wrap(err, "failed to parse snapshot checksum")
+	}
+	if expected := hex.EncodeToString(r.sum); checksum.SHA256 != expected {
+		return errors.Errorf("invalid snapshot: checksum is %s, but content checksum is %s", checksum.SHA256, expected)
+	}
+	return nil
+}
+
+// snapshotImporter buffers the snapshot records and inserts them in batches.
+type snapshotImporter struct {
+	runesDgTx   datagateway.RunesDataGatewayWithTx
+	blockHeight uint64
+
+	runeEntries      []*runes.RuneEntry
+	outPointBalances []*entity.OutPointBalance
+	balances         []*entity.Balance
+
+	indexedBlocksCount    int
+	runeEntriesCount      int
+	outPointBalancesCount int
+	balancesCount         int
+}
+
+func (i *snapshotImporter) add(ctx context.Context, record *snapshotRecord) error {
+	switch record.Type {
+	case snapshotRecordIndexedBlock:
+		var indexedBlock snapshotIndexedBlock
+		if err := json.Unmarshal(record.Data, &indexedBlock); err != nil {
+			return errors.Wrap(err, "failed to parse indexed block record")
+		}
+		if err := i.runesDgTx.CreateIndexedBlock(ctx, (*entity.IndexedBlock)(&indexedBlock)); err != nil {
+			return errors.Wrap(err, "failed to create indexed block")
+		}
+		i.indexedBlocksCount++
+	case snapshotRecordRuneEntry:
+		var snapshotEntry snapshotRuneEntry
+		if err := json.Unmarshal(record.Data, &snapshotEntry); err != nil {
+			return errors.Wrap(err, "failed to parse rune entry record")
+		}
+		runeEntry, err := mapSnapshotToRuneEntry(snapshotEntry)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		i.runeEntries = append(i.runeEntries, runeEntry)
+	case snapshotRecordOutPointBalance:
+		var snapshotBalance snapshotOutPointBalance
+		if err := json.Unmarshal(record.Data, &snapshotBalance); err != nil {
+			return errors.Wrap(err, "failed to parse outpoint balance record")
+		}
+		pkScript, err := hex.DecodeString(snapshotBalance.PkScript)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode pkScript")
+		}
+		runeId, err := runes.NewRuneIdFromString(snapshotBalance.RuneId)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse rune id")
+		}
+		i.outPointBalances = append(i.outPointBalances, &entity.OutPointBalance{
+			RuneId:   runeId,
+			PkScript: pkScript,
+			OutPoint: wire.OutPoint{
+				Hash:  snapshotBalance.TxHash,
+				Index: snapshotBalance.TxOutIndex,
+			},
+			Amount:      snapshotBalance.Amount,
+			BlockHeight: snapshotBalance.BlockHeight,
+			Value:       snapshotBalance.Value,
+		})
+	case snapshotRecordBalance:
+		var snapshotBalance snapshotBalance
+		if err := json.Unmarshal(record.Data, &snapshotBalance); err != nil {
+			return errors.Wrap(err, "failed to parse balance record")
+		}
+		pkScript, err := hex.DecodeString(snapshotBalance.PkScript)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode pkScript")
+		}
+		runeId, err := runes.NewRuneIdFromString(snapshotBalance.RuneId)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse rune id")
+		}
+		i.balances = append(i.balances, &entity.Balance{
+			PkScript:    pkScript,
+			Amount:      snapshotBalance.Amount,
+			RuneId:      runeId,
+			BlockHeight: snapshotBalance.BlockHeight,
+		})
+	default:
+		return errors.Errorf("invalid snapshot: unexpected record type %q", record.Type)
+	}
+
+	if len(i.runeEntries) >= snapshotPageSize || len(i.outPointBalances) >= snapshotPageSize || len(i.balances) >= snapshotPageSize {
+		return errors.WithStack(i.flush(ctx))
+	}
+	return nil
+}
+
+// flush inserts the buffered records.
+func (i *snapshotImporter) flush(ctx context.Context) error {
+	if len(i.runeEntries) > 0 {
+		if err := i.runesDgTx.CreateRuneEntries(ctx, i.runeEntries); err != nil {
+			return errors.Wrap(err, "failed to create rune entries")
+		}
+		// the state history before the snapshot height isn't part of the snapshot
+		if err := i.runesDgTx.CreateRuneEntryStates(ctx, i.runeEntries, i.blockHeight); err != nil {
+			return errors.Wrap(err, "failed to create rune entry states")
+		}
+		i.runeEntriesCount += len(i.runeEntries)
+		i.runeEntries = nil
+	}
+	if len(i.outPointBalances) > 0 {
+		if err := i.runesDgTx.CreateOutPointBalances(ctx, i.outPointBalances); err != nil {
+			return errors.Wrap(err, "failed to create outpoint balances")
+		}
+		i.outPointBalancesCount += len(i.outPointBalances)
+		i.outPointBalances = nil
+	}
+	if len(i.balances) > 0 {
+		if err := i.runesDgTx.CreateRuneBalances(ctx, i.balances); err != nil {
+			return errors.Wrap(err, "failed to create balances")
+		}
+		if err := i.runesDgTx.UpdateCurrentBalances(ctx, i.balances); err != nil {
+			return errors.Wrap(err, "failed to update current balances")
+		}
+		i.balancesCount += len(i.balances)
+		i.balances = nil
+	}
+	return nil
+}
+
+func mapRuneEntryToSnapshot(src *runes.RuneEntry) snapshotRuneEntry {
+	dst := snapshotRuneEntry{
+		RuneId:            src.RuneId.String(),
+		Number:            src.Number,
+		Divisibility:      src.Divisibility,
+		Premine:           src.Premine,
+		Rune:              src.SpacedRune.Rune.String(),
+		Spacers:           src.SpacedRune.Spacers,
+		Symbol:            src.Symbol,
+		Turbo:             src.Turbo,
+		Mints:             src.Mints,
+		BurnedAmount:      src.BurnedAmount,
+		CompletedAtHeight: src.CompletedAtHeight,
+		EtchingBlock:      src.EtchingBlock,
+		EtchingTxHash:     src.EtchingTxHash,
+		EtchedAt:          src.EtchedAt,
+	}
+	if !src.CompletedAt.IsZero() {
+		dst.CompletedAt = &src.CompletedAt
+	}
+	if src.Terms != nil {
+		dst.Terms = &snapshotTerms{
+			Amount:      src.Terms.Amount,
+			Cap:         src.Terms.Cap,
+			HeightStart: src.Terms.HeightStart,
+			HeightEnd:   src.Terms.HeightEnd,
+			OffsetStart: src.Terms.OffsetStart,
+			OffsetEnd:   src.Terms.OffsetEnd,
+		}
+	}
+	return dst
+}
+
+func mapSnapshotToRuneEntry(src snapshotRuneEntry) (*runes.RuneEntry, error) {
+	runeId, err := runes.NewRuneIdFromString(src.RuneId)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse rune id")
+	}
+	rune, err := runes.NewRuneFromString(src.Rune)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse rune")
+	}
+	dst := &runes.RuneEntry{
+		RuneId:            runeId,
+		Number:            src.Number,
+		Divisibility:      src.Divisibility,
+		Premine:           src.Premine,
+		SpacedRune:        runes.NewSpacedRune(rune, src.Spacers),
+		Symbol:            src.Symbol,
+		Turbo:             src.Turbo,
+		Mints:             src.Mints,
+		BurnedAmount:      src.BurnedAmount,
+		CompletedAtHeight: src.CompletedAtHeight,
+		EtchingBlock:      src.EtchingBlock,
+		EtchingTxHash:     src.EtchingTxHash,
+		EtchedAt:          src.EtchedAt,
+	}
+	if src.CompletedAt != nil {
+		dst.CompletedAt = *src.CompletedAt
+	}
+	if src.Terms != nil {
+		dst.Terms = &runes.Terms{
+			Amount:      src.Terms.Amount,
+			Cap:         src.Terms.Cap,
+			HeightStart: src.Terms.HeightStart,
+			HeightEnd:   src.Terms.HeightEnd,
+			OffsetStart: src.Terms.OffsetStart,
+			OffsetEnd:   src.Terms.OffsetEnd,
+		}
+	}
+	return dst, nil
+}
@@ -0,0 +1,138 @@
+package runes
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	"github.com/gaze-network/uint128"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestSnapshot writes a snapshot with a header, an indexed block and a balance, followed by the checksum.
+func writeTestSnapshot(t *testing.T) []byte {
+	var buf bytes.Buffer
+	sw := newSnapshotWriter(&buf)
+	assert.NoError(t, sw.write(snapshotRecordHeader, SnapshotHeader{
+		Version:          SnapshotVersion,
+		Network:          common.NetworkMainnet,
+		EventHashVersion: constants.EventHashVersion,
+		Height:           840001,
+	}))
+	assert.NoError(t, sw.write(snapshotRecordIndexedBlock, snapshotIndexedBlock{
+		Height: 840001,
+		Hash:   chainhash.Hash{1},
+	}))
+	assert.NoError(t, sw.write(snapshotRecordBalance, snapshotBalance{
+		PkScript:    "5120",
+		RuneId:      "840000:1",
+		Amount:      uint128.From64(100),
+		BlockHeight: 840001,
+	}))
+	assert.NoError(t, sw.writeChecksum())
+	return buf.Bytes()
+}
+
+// readTestSnapshot reads all records of a snapshot and verifies its checksum.
+func readTestSnapshot(snapshot []byte) ([]*snapshotRecord, error) {
+	records := make([]*snapshotRecord, 0)
+	err := newSnapshotReader(bytes.NewReader(snapshot)).readRecords(func(record *snapshotRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	return records, errors.WithStack(err)
+}
+
+func TestSnapshotRecordsRoundTrip(t *testing.T) {
+	records, err := readTestSnapshot(writeTestSnapshot(t))
+	assert.NoError(t, err)
+	if !assert.Len(t, records, 3) {
+		return
+	}
+	assert.Equal(t, []snapshotRecordType{snapshotRecordHeader, snapshotRecordIndexedBlock, snapshotRecordBalance}, []snapshotRecordType{records[0].Type, records[1].Type, records[2].Type})
+
+	var header SnapshotHeader
+	assert.NoError(t, json.Unmarshal(records[0].Data, &header))
+	assert.Equal(t, SnapshotVersion, header.Version)
+	assert.Equal(t, common.NetworkMainnet, header.Network)
+	assert.Equal(t, int64(840001), header.Height)
+
+	var indexedBlock snapshotIndexedBlock
+	assert.NoError(t, json.Unmarshal(records[1].Data, &indexedBlock))
+	assert.Equal(t, chainhash.Hash{1}, indexedBlock.Hash)
+
+	var balance snapshotBalance
+	assert.NoError(t, json.Unmarshal(records[2].Data, &balance))
+	assert.Equal(t, snapshotBalance{PkScript: "5120", RuneId: "840000:1", Amount: uint128.From64(100), BlockHeight: 840001}, balance)
+}
+
+func TestSnapshotRecordsCorrupted(t *testing.T) {
+	type testcase struct {
+		name        string
+		corrupt     func(snapshot []byte) []byte
+		expectedErr string
+	}
+
+	testcases := []testcase{
+		{
+			name: "modified record",
+			corrupt: func(snapshot []byte) []byte {
+				return bytes.Replace(snapshot, []byte(`"amount":"100"`), []byte(`"amount":"101"`), 1)
+			},
+			expectedErr: "checksum is",
+		},
+		{
+			name: "removed record",
+			corrupt: func(snapshot []byte) []byte {
+				lines := bytes.SplitAfter(snapshot, []byte("\n"))
+				return bytes.Join(append(lines[:1:1], lines[2:]...), nil)
+			},
+			expectedErr: "checksum is",
+		},
+		{
+			name: "modified checksum",
+			corrupt: func(snapshot []byte) []byte {
+				lines := bytes.SplitAfter(snapshot, []byte("\n"))
+				checksumLine := lines[len(lines)-2]
+				lines[len(lines)-2] = bytes.Replace(checksumLine, []byte(`"sha256":"`), []byte(`"sha256":"00`), 1)
+				return bytes.Join(lines, nil)
+			},
+			expectedErr: "checksum is",
+		},
+		{
+			name: "missing checksum",
+			corrupt: func(snapshot []byte) []byte {
+				lines := bytes.SplitAfter(snapshot, []byte("\n"))
+				return bytes.Join(lines[:len(lines)-2], nil)
+			},
+			expectedErr: "missing checksum",
+		},
+		{
+			name: "truncated record",
+			corrupt: func(snapshot []byte) []byte {
+				return snapshot[:len(snapshot)-1]
+			},
+			expectedErr: "truncated record",
+		},
+		{
+			name: "data after checksum",
+			corrupt: func(snapshot []byte) []byte {
+				return append(snapshot, snapshot...)
+			},
+			expectedErr: "unexpected data after checksum",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := readTestSnapshot(tc.corrupt(writeTestSnapshot(t)))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/core/types"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+)
+
+// DecodeRawTransaction deserializes a raw transaction. Returns errs.InvalidArgument if the transaction is malformed.
+func (u *Usecase) DecodeRawTransaction(rawTx []byte) (*wire.MsgTx, error) {
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+		return nil, errors.Wrapf(errs.InvalidArgument, "malformed transaction: %v", err)
+	}
+	return &msgTx, nil
+}
+
+// GetRawTransaction returns the transaction from the bitcoin node. Returns errs.NotFound if the node does not know the transaction.
+func (u *Usecase) GetRawTransaction(ctx context.Context, txHash chainhash.Hash) (*wire.MsgTx, error) {
+	if u.bitcoinClient == nil {
+		return nil, errors.Wrap(errs.Unsupported, "bitcoin client is not configured")
+	}
+	tx, err := u.bitcoinClient.GetRawTransactionByTxHash(ctx, txHash)
+	if err != nil {
+		if strings.Contains(err.Error(), "No such mempool or blockchain transaction.") {
+			return nil, errors.WithStack(errs.NotFound)
+		}
+		return nil, errors.Wrap(err, "error during GetRawTransactionByTxHash")
+	}
+	return tx, nil
+}
+
+// DecipherRunestone returns the runestone of the transaction, as the indexer would decipher it. Returns nil if the transaction has no runestone.
+func (u *Usecase) DecipherRunestone(msgTx *wire.MsgTx) (*runes.Runestone, error) {
+	// the block of the transaction does not affect deciphering
+	tx := types.ParseMsgTx(msgTx, 0, chainhash.Hash{}, 0)
+	runestone, err := runes.DecipherRunestone(tx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decipher runestone")
+	}
+	return runestone, nil
+}
@@ -2,4 +2,7 @@ package usecase
 
 import "github.com/cockroachdb/errors"
 
-var ErrUTXONotFound = errors.New("utxo not found")
+var (
+	ErrUTXONotFound      = errors.New("utxo not found")
+	ErrBlockHeightPruned = errors.New("block height is pruned")
+)
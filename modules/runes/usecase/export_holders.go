@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/indexer-network/pkg/btcutils"
+	"github.com/gaze-network/uint128"
+)
+
+type HoldersExportFormat string
+
+const (
+	HoldersExportFormatCSV    HoldersExportFormat = "csv"
+	HoldersExportFormatNDJSON HoldersExportFormat = "ndjson"
+)
+
+func (f HoldersExportFormat) IsValid() bool {
+	return f == HoldersExportFormatCSV || f == HoldersExportFormatNDJSON
+}
+
+// holdersExportBatchSize is the number of balances fetched per query during a holders export.
+const holdersExportBatchSize = 5000
+
+// HoldersExportTrailer is the last row of a holders export.
+type HoldersExportTrailer struct {
+	RuneId      runes.RuneId    `json:"runeId"`
+	BlockHeight uint64          `json:"blockHeight"`
+	Holders     uint64          `json:"holders"`
+	TotalAmount uint128.Uint128 `json:"totalAmount"` // sum of all exported balances
+	// CirculatingSupply is the minted amount, including premine, minus the burned amount of the rune at the block height.
+	CirculatingSupply uint128.Uint128 `json:"circulatingSupply"`
+	// Reconciled is true if TotalAmount equals CirculatingSupply.
+	Reconciled bool `json:"reconciled"`
+	// SHA256 is the checksum of all bytes written before the trailer row.
+	SHA256 string `json:"sha256"`
+}
+
+type holderRow struct {
+	Address        string          `json:"address"`
+	PkScript       string          `json:"pkScript"`
+	Amount         uint128.Uint128 `json:"amount"`
+	DecimalsAmount string          `json:"decimalsAmount"`
+}
+
+// ExportHolders writes every holder of the rune at blockHeight to w, ordered by amount descending, then pkscript.
+// The last row is a HoldersExportTrailer with the checksum of all previous rows and the reconciliation against the circulating supply.
+// Returns errs.NotFound if the rune is not etched at blockHeight.
+func (u *Usecase) ExportHolders(ctx context.Context, w io.Writer, runeId runes.RuneId, blockHeight uint64, format HoldersExportFormat, network common.Network) (*HoldersExportTrailer, error) {
+	if !format.IsValid() {
+		return nil, errors.Wrapf(errs.InvalidArgument, "unsupported holders export format %q", format)
+	}
+	if err := u.ensureBlockHeightNotPruned(ctx, blockHeight); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	runeEntry, err := u.runesDg.GetRuneEntryByRuneIdAndHeight(ctx, runeId, blockHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "error during GetRuneEntryByRuneIdAndHeight")
+	}
+	mintedAmount, err := runeEntry.MintedAmount()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get minted amount of rune")
+	}
+
+	hw := &holdersExportWriter{
+		format: format,
+		hash:   sha256.New(),
+	}
+	hw.w = io.MultiWriter(w, hw.hash)
+	if err := hw.writeHeader(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	trailer := &HoldersExportTrailer{
+		RuneId:            runeId,
+		BlockHeight:       blockHeight,
+		CirculatingSupply: mintedAmount.Sub(runeEntry.BurnedAmount),
+	}
+	var cursor *entity.BalanceCursor
+	for {
+		balances, err := u.runesDg.GetBalancesByRuneId(ctx, runeId, blockHeight, cursor, holdersExportBatchSize, 0)
+		if err != nil {
+			return nil, errors.Wrap(err, "error during GetBalancesByRuneId")
+		}
+		for _, balance := range balances {
+			if err := hw.writeRow(holderRow{
+				Address:        btcutils.EncodeAddressFromPkScript(balance.PkScript, network.ChainParams()),
+				PkScript:       hex.EncodeToString(balance.PkScript),
+				Amount:         balance.Amount,
+				DecimalsAmount: formatDecimalsAmount(balance.Amount, runeEntry.Divisibility),
+			}); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			trailer.Holders++
+			var overflow bool
+			trailer.TotalAmount, overflow = trailer.TotalAmount.AddOverflow(balance.Amount)
+			if overflow {
+				return nil, errors.WithStack(errs.OverflowUint128)
+			}
+		}
+		if len(balances) < holdersExportBatchSize {
+			break
+		}
+		last := balances[len(balances)-1]
+		cursor = &entity.BalanceCursor{
+			Amount:   last.Amount,
+			PkScript: last.PkScript,
+		}
+	}
+
+	trailer.Reconciled = trailer.TotalAmount == trailer.CirculatingSupply
+	trailer.SHA256 = hex.EncodeToString(hw.hash.Sum(nil))
+	// the trailer itself is not part of the checksum
+	hw.w = w
+	if err := hw.writeTrailer(trailer); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return trailer, nil
+}
+
+type holdersExportWriter struct {
+	format HoldersExportFormat
+	w      io.Writer
+	hash   hash.Hash
+}
+
+func (hw *holdersExportWriter) writeCSV(record []string) error {
+	cw := csv.NewWriter(hw.w)
+	if err := cw.Write(record); err != nil {
+		return errors.Wrap(err, "failed to write csv record")
+	}
+	cw.Flush()
+	return errors.Wrap(cw.Error(), "failed to write csv record")
+}
+
+func (hw *holdersExportWriter) writeJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal json record")
+	}
+	if _, err := hw.w.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write json record")
+	}
+	return nil
+}
+
+func (hw *holdersExportWriter) writeHeader() error {
+	if hw.format == HoldersExportFormatCSV {
+		return hw.writeCSV([]string{"address", "pkscript", "amount", "decimals_amount"})
+	}
+	return nil
+}
+
+func (hw *holdersExportWriter) writeRow(row holderRow) error {
+	if hw.format == HoldersExportFormatCSV {
+		return hw.writeCSV([]string{row.Address, row.PkScript, row.Amount.String(), row.DecimalsAmount})
+	}
+	return hw.writeJSON(row)
+}
+
+// writeTrailer writes the trailer row. In CSV, it is a row starting with "#trailer" followed by key=value fields.
+func (hw *holdersExportWriter) writeTrailer(trailer *HoldersExportTrailer) error {
+	if hw.format == HoldersExportFormatCSV {
+		return hw.writeCSV([]string{
+			"#trailer",
+			"rune_id=" + trailer.RuneId.String(),
+			"block_height=" + strconv.FormatUint(trailer.BlockHeight, 10),
+			"holders=" + strconv.FormatUint(trailer.Holders, 10),
+			"total_amount=" + trailer.TotalAmount.String(),
+			"circulating_supply=" + trailer.CirculatingSupply.String(),
+			"reconciled=" + strconv.FormatBool(trailer.Reconciled),
+			"sha256=" + trailer.SHA256,
+		})
+	}
+	return hw.writeJSON(map[string]*HoldersExportTrailer{"trailer": trailer})
+}
+
+// formatDecimalsAmount formats the amount with the divisibility of the rune, e.g. 12345 with divisibility 2 is "123.45".
+func formatDecimalsAmount(amount uint128.Uint128, divisibility uint8) string {
+	s := amount.String()
+	if divisibility == 0 {
+		return s
+	}
+	if len(s) <= int(divisibility) {
+		s = strings.Repeat("0", int(divisibility)-len(s)+1) + s
+	}
+	return fmt.Sprintf("%s.%s", s[:len(s)-int(divisibility)], s[len(s)-int(divisibility):])
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gaze-network/indexer-network/common"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDecimalsAmount(t *testing.T) {
+	type testcase struct {
+		amount       uint64
+		divisibility uint8
+		expected     string
+	}
+
+	testcases := []testcase{
+		{amount: 0, divisibility: 0, expected: "0"},
+		{amount: 12345, divisibility: 0, expected: "12345"},
+		{amount: 12345, divisibility: 2, expected: "123.45"},
+		{amount: 100, divisibility: 2, expected: "1.00"},
+		// len(amount) <= divisibility
+		{amount: 45, divisibility: 2, expected: "0.45"},
+		{amount: 5, divisibility: 2, expected: "0.05"},
+		{amount: 0, divisibility: 2, expected: "0.00"},
+		{amount: 123, divisibility: 18, expected: "0.000000000000000123"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatDecimalsAmount(uint128.From64(tc.amount), tc.divisibility))
+		})
+	}
+}
+
+func TestExportHolders(t *testing.T) {
+	runeId := runes.RuneId{BlockHeight: 840000, TxIndex: 1}
+	taprootPkScript, _ := hex.DecodeString("51204b5e272a4ef8675362c11c5a60afc67a1da7657c4accc1e2ad5a808997a29739")
+	nonStandardPkScript := []byte{0x6a, 0x01, 0xff}
+	newDg := func(burnedAmount uint64) *fakeRunesDg {
+		return &fakeRunesDg{
+			runeEntry: &runes.RuneEntry{
+				RuneId:       runeId,
+				Divisibility: 2,
+				Premine:      uint128.From64(1000),
+				BurnedAmount: uint128.From64(burnedAmount),
+			},
+			holders: []*entity.Balance{
+				{PkScript: taprootPkScript, RuneId: runeId, Amount: uint128.From64(700)},
+				{PkScript: nonStandardPkScript, RuneId: runeId, Amount: uint128.From64(300)},
+			},
+		}
+	}
+
+	// splitTrailer splits the export into the rows before the trailer and the trailer row
+	splitTrailer := func(t *testing.T, output string) (string, string) {
+		t.Helper()
+		trimmed := strings.TrimSuffix(output, "\n")
+		i := strings.LastIndex(trimmed, "\n")
+		if !assert.NotEqual(t, -1, i) {
+			t.FailNow()
+		}
+		return output[:i+1], trimmed[i+1:]
+	}
+	checksum := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		trailer, err := New(newDg(0), nil).ExportHolders(context.Background(), &buf, runeId, 840001, HoldersExportFormatCSV, common.NetworkMainnet)
+		assert.NoError(t, err)
+
+		rows, trailerRow := splitTrailer(t, buf.String())
+		assert.Equal(t, "address,pkscript,amount,decimals_amount\n"+
+			"bc1pfd0zw2jwlpn4xckpr3dxpt7x0gw6wetuftxvrc4dt2qgn9azjuus65fug6,51204b5e272a4ef8675362c11c5a60afc67a1da7657c4accc1e2ad5a808997a29739,700,7.00\n"+
+			",6a01ff,300,3.00\n", rows)
+		// the checksum covers every row before the trailer, but not the trailer itself
+		assert.Equal(t, checksum(rows), trailer.SHA256)
+		assert.Equal(t, "#trailer,rune_id=840000:1,block_height=840001,holders=2,total_amount=1000,circulating_supply=1000,reconciled=true,sha256="+trailer.SHA256, trailerRow)
+	})
+	t.Run("ndjson", func(t *testing.T) {
+		var buf bytes.Buffer
+		trailer, err := New(newDg(0), nil).ExportHolders(context.Background(), &buf, runeId, 840001, HoldersExportFormatNDJSON, common.NetworkMainnet)
+		assert.NoError(t, err)
+
+		rows, trailerRow := splitTrailer(t, buf.String())
+		assert.Equal(t, `{"address":"bc1pfd0zw2jwlpn4xckpr3dxpt7x0gw6wetuftxvrc4dt2qgn9azjuus65fug6","pkScript":"51204b5e272a4ef8675362c11c5a60afc67a1da7657c4accc1e2ad5a808997a29739","amount":"700","decimalsAmount":"7.00"}`+"\n"+
+			`{"address":"","pkScript":"6a01ff","amount":"300","decimalsAmount":"3.00"}`+"\n", rows)
+		assert.Equal(t, checksum(rows), trailer.SHA256)
+
+		var decoded map[string]*HoldersExportTrailer
+		assert.NoError(t, json.Unmarshal([]byte(trailerRow), &decoded))
+		assert.Equal(t, trailer, decoded["trailer"])
+		assert.Equal(t, uint64(2), trailer.Holders)
+		assert.True(t, trailer.Reconciled)
+	})
+	t.Run("not reconciled", func(t *testing.T) {
+		var buf bytes.Buffer
+		trailer, err := New(newDg(100), nil).ExportHolders(context.Background(), &buf, runeId, 840001, HoldersExportFormatCSV, common.NetworkMainnet)
+		assert.NoError(t, err)
+		assert.Equal(t, uint128.From64(1000), trailer.TotalAmount)
+		assert.Equal(t, uint128.From64(900), trailer.CirculatingSupply)
+		assert.False(t, trailer.Reconciled)
+		assert.True(t, strings.HasSuffix(buf.String(), "reconciled=false,sha256="+trailer.SHA256+"\n"))
+	})
+}
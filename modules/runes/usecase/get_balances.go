@@ -4,12 +4,29 @@ import (
 	"context"
 
 	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
 	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
 	"github.com/gaze-network/indexer-network/modules/runes/runes"
 )
 
+// GetBalancesByPkScript returns the balances of the pkScript at blockHeight. If latest is true, blockHeight is expected to be the latest indexed block height,
+// and the balances are read from the current balances, which is much cheaper than looking up the balance history.
 // Use limit = -1 as no limit.
-func (u *Usecase) GetBalancesByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error) {
+func (u *Usecase) GetBalancesByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, latest bool, limit int32, offset int32) ([]*entity.Balance, error) {
+	if err := u.ensureBlockHeightNotPruned(ctx, blockHeight); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if latest {
+		balances, err := u.runesDg.GetCurrentBalancesByPkScript(ctx, pkScript, blockHeight, limit, offset)
+		if err == nil {
+			return balances, nil
+		}
+		if !errors.Is(err, errs.InvalidState) {
+			return nil, errors.Wrap(err, "error during GetCurrentBalancesByPkScript")
+		}
+		// a new block was indexed after blockHeight was resolved, fallback to the balance history
+	}
+
 	balances, err := u.runesDg.GetBalancesByPkScript(ctx, pkScript, blockHeight, limit, offset)
 	if err != nil {
 		return nil, errors.Wrap(err, "error during GetBalancesByPkScript")
@@ -18,8 +35,11 @@ func (u *Usecase) GetBalancesByPkScript(ctx context.Context, pkScript []byte, bl
 }
 
 // Use limit = -1 as no limit.
-func (u *Usecase) GetBalancesByRuneId(ctx context.Context, runeId runes.RuneId, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error) {
-	balances, err := u.runesDg.GetBalancesByRuneId(ctx, runeId, blockHeight, limit, offset)
+func (u *Usecase) GetBalancesByRuneId(ctx context.Context, runeId runes.RuneId, blockHeight uint64, cursor *entity.BalanceCursor, limit int32, offset int32) ([]*entity.Balance, error) {
+	if err := u.ensureBlockHeightNotPruned(ctx, blockHeight); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	balances, err := u.runesDg.GetBalancesByRuneId(ctx, runeId, blockHeight, cursor, limit, offset)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get rune holders by rune id")
 	}
@@ -27,6 +47,9 @@ func (u *Usecase) GetBalancesByRuneId(ctx context.Context, runeId runes.RuneId,
 }
 
 func (u *Usecase) GetTotalHoldersByRuneIds(ctx context.Context, runeIds []runes.RuneId, blockHeight uint64) (map[runes.RuneId]int64, error) {
+	if err := u.ensureBlockHeightNotPruned(ctx, blockHeight); err != nil {
+		return nil, errors.WithStack(err)
+	}
 	holders, err := u.runesDg.GetTotalHoldersByRuneIds(ctx, runeIds, blockHeight)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get total holders by rune ids")
@@ -35,6 +58,9 @@ func (u *Usecase) GetTotalHoldersByRuneIds(ctx context.Context, runeIds []runes.
 }
 
 func (u *Usecase) GetTotalHoldersByRuneId(ctx context.Context, runeId runes.RuneId, blockHeight uint64) (int64, error) {
+	if err := u.ensureBlockHeightNotPruned(ctx, blockHeight); err != nil {
+		return 0, errors.WithStack(err)
+	}
 	holders, err := u.runesDg.GetTotalHoldersByRuneIds(ctx, []runes.RuneId{runeId}, blockHeight)
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to get total holders by rune ids")
@@ -43,3 +69,36 @@ func (u *Usecase) GetTotalHoldersByRuneId(ctx context.Context, runeId runes.Rune
 	// defaults to zero holders if not found
 	return holders[runeId], nil
 }
+
+// GetBalanceHistoryByPkScript returns the balance changes of the pkScript from fromBlock to toBlock. fromBlock = 0 starts from the earliest available block height.
+// Use limit = -1 as no limit.
+func (u *Usecase) GetBalanceHistoryByPkScript(ctx context.Context, pkScript []byte, runeId runes.RuneId, fromBlock, toBlock uint64, interval uint64, limit int32, offset int32) ([]*entity.BalanceChange, error) {
+	if fromBlock == 0 {
+		prunedHeight, err := u.getPrunedHeight(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get pruned height")
+		}
+		fromBlock = prunedHeight
+	}
+	if err := u.ensureBlockHeightNotPruned(ctx, fromBlock); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	changes, err := u.runesDg.GetBalanceHistoryByPkScript(ctx, pkScript, runeId, fromBlock, toBlock, interval, limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "error during GetBalanceHistoryByPkScript")
+	}
+	return changes, nil
+}
+
+// GetHolderChangesByRuneId returns the holders of the rune whose balance changed between fromBlock and toBlock.
+// Use limit = -1 as no limit.
+func (u *Usecase) GetHolderChangesByRuneId(ctx context.Context, runeId runes.RuneId, fromBlock, toBlock uint64, changeType entity.HolderChangeType, limit int32, offset int32) ([]*entity.HolderChange, error) {
+	if err := u.ensureBlockHeightNotPruned(ctx, fromBlock); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	changes, err := u.runesDg.GetHolderChangesByRuneId(ctx, runeId, fromBlock, toBlock, changeType, limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "error during GetHolderChangesByRuneId")
+	}
+	return changes, nil
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+)
+
+// BlockActivity is the summary of all runes activity in a block.
+type BlockActivity struct {
+	Block     *entity.IndexedBlock
+	Timestamp time.Time // timestamp of the rune transactions in the block, zero if there is none
+	// EtchedRuneIds are the runes etched in the block, in transaction order.
+	EtchedRuneIds []runes.RuneId
+	Mints         map[runes.RuneId]*MintActivity
+	Burns         map[runes.RuneId]uint128.Uint128
+	// Cenotaphs are the transactions in the block with a cenotaph runestone, in transaction order.
+	Cenotaphs []*entity.RuneTransaction
+	// TransactionCount is the number of rune transactions in the block.
+	TransactionCount uint64
+	// TransferCount is the number of rune transactions in the block that spend rune balances.
+	TransferCount uint64
+}
+
+type MintActivity struct {
+	Count  uint64
+	Amount uint128.Uint128
+}
+
+// blockActivityBatchSize is the number of rune transactions fetched per query when summarizing a block.
+const blockActivityBatchSize = 5000
+
+// GetBlockActivity returns the summary of all runes activity in the block at the given height.
+// Returns errs.NotFound if the block is not indexed.
+func (u *Usecase) GetBlockActivity(ctx context.Context, blockHeight uint64) (*BlockActivity, error) {
+	block, err := u.runesDg.GetIndexedBlockByHeight(ctx, int64(blockHeight))
+	if err != nil {
+		return nil, errors.Wrap(err, "error during GetIndexedBlockByHeight")
+	}
+	// page through the transactions, as a single query is capped
+	txs := make([]*entity.RuneTransaction, 0)
+	var cursor *entity.RuneTransactionCursor
+	for {
+		page, err := u.runesDg.GetRuneTransactions(ctx, nil, runes.RuneId{}, blockHeight, blockHeight, cursor, blockActivityBatchSize, 0)
+		if err != nil {
+			return nil, errors.Wrap(err, "error during GetRuneTransactions")
+		}
+		txs = append(txs, page...)
+		if len(page) < blockActivityBatchSize {
+			break
+		}
+		last := page[len(page)-1]
+		cursor = &entity.RuneTransactionCursor{
+			BlockHeight: last.BlockHeight,
+			Index:       last.Index,
+		}
+	}
+	// transactions are returned newest first
+	slices.SortFunc(txs, func(tx1, tx2 *entity.RuneTransaction) int {
+		return cmp.Compare(tx1.Index, tx2.Index)
+	})
+
+	activity := &BlockActivity{
+		Block:            block,
+		EtchedRuneIds:    make([]runes.RuneId, 0),
+		Mints:            make(map[runes.RuneId]*MintActivity),
+		Burns:            make(map[runes.RuneId]uint128.Uint128),
+		Cenotaphs:        make([]*entity.RuneTransaction, 0),
+		TransactionCount: uint64(len(txs)),
+	}
+	for _, tx := range txs {
+		activity.Timestamp = tx.Timestamp
+		if tx.RuneEtched {
+			activity.EtchedRuneIds = append(activity.EtchedRuneIds, runes.RuneId{
+				BlockHeight: tx.BlockHeight,
+				TxIndex:     tx.Index,
+			})
+		}
+		for runeId, amount := range tx.Mints {
+			mint, ok := activity.Mints[runeId]
+			if !ok {
+				mint = &MintActivity{}
+				activity.Mints[runeId] = mint
+			}
+			var overflow bool
+			mint.Count++
+			mint.Amount, overflow = mint.Amount.AddOverflow(amount)
+			if overflow {
+				return nil, errors.WithStack(errs.OverflowUint128)
+			}
+		}
+		for runeId, amount := range tx.Burns {
+			burned, overflow := activity.Burns[runeId].AddOverflow(amount)
+			if overflow {
+				return nil, errors.WithStack(errs.OverflowUint128)
+			}
+			activity.Burns[runeId] = burned
+		}
+		if tx.Runestone != nil && tx.Runestone.Cenotaph {
+			activity.Cenotaphs = append(activity.Cenotaphs, tx)
+		}
+		if len(tx.Inputs) > 0 {
+			activity.TransferCount++
+		}
+	}
+	return activity, nil
+}
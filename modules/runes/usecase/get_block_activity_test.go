@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBlockActivity(t *testing.T) {
+	const blockHeight = 840000
+	runeId := runes.RuneId{BlockHeight: 839000, TxIndex: 1}
+
+	// more transactions than fit in one page
+	txCount := blockActivityBatchSize*2 + 1
+	txs := make(map[chainhash.Hash]*entity.RuneTransaction)
+	for i := range txCount {
+		hash := chainhash.Hash{byte(i), byte(i >> 8), byte(i >> 16)}
+		txs[hash] = &entity.RuneTransaction{
+			Hash:        hash,
+			BlockHeight: blockHeight,
+			Index:       uint32(i),
+			Mints:       map[runes.RuneId]uint128.Uint128{runeId: uint128.From64(10)},
+		}
+	}
+	// transactions of other blocks are not counted
+	txs[chainhash.Hash{0xff, 0xff, 0xff, 0xff}] = &entity.RuneTransaction{
+		Hash:        chainhash.Hash{0xff, 0xff, 0xff, 0xff},
+		BlockHeight: blockHeight + 1,
+		Mints:       map[runes.RuneId]uint128.Uint128{runeId: uint128.From64(10)},
+	}
+
+	dg := &fakeRunesDg{
+		indexedBlock: &entity.IndexedBlock{Height: blockHeight},
+		txs:          txs,
+	}
+	activity, err := New(dg, nil).GetBlockActivity(context.Background(), blockHeight)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, dg.getRuneTxsCalls)
+	assert.Equal(t, uint64(txCount), activity.TransactionCount)
+	assert.Equal(t, map[runes.RuneId]*MintActivity{
+		runeId: {
+			Count:  uint64(txCount),
+			Amount: uint128.From64(uint64(txCount) * 10),
+		},
+	}, activity.Mints)
+	assert.Empty(t, activity.Cenotaphs)
+}
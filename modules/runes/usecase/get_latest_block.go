@@ -2,9 +2,12 @@ package usecase
 
 import (
 	"context"
+	"time"
 
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/cockroachdb/errors"
 	"github.com/gaze-network/indexer-network/core/types"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
 )
 
 func (u *Usecase) GetLatestBlock(ctx context.Context) (types.BlockHeader, error) {
@@ -14,3 +17,27 @@ func (u *Usecase) GetLatestBlock(ctx context.Context) (types.BlockHeader, error)
 	}
 	return blockHeader, nil
 }
+
+func (u *Usecase) GetIndexedBlockByHeight(ctx context.Context, height int64) (*entity.IndexedBlock, error) {
+	indexedBlock, err := u.runesDg.GetIndexedBlockByHeight(ctx, height)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get indexed block by height")
+	}
+	return indexedBlock, nil
+}
+
+func (u *Usecase) GetIndexedBlockByHash(ctx context.Context, hash chainhash.Hash) (*entity.IndexedBlock, error) {
+	indexedBlock, err := u.runesDg.GetIndexedBlockByHash(ctx, hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get indexed block by hash")
+	}
+	return indexedBlock, nil
+}
+
+func (u *Usecase) GetBlockHeightAtTimestamp(ctx context.Context, timestamp time.Time) (uint64, error) {
+	height, err := u.runesDg.GetBlockHeightAtTimestamp(ctx, timestamp)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get block height at timestamp")
+	}
+	return height, nil
+}
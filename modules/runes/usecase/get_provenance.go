@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/samber/lo"
+)
+
+// maxProvenanceNodes is the maximum number of transactions visited in a single provenance walk.
+const maxProvenanceNodes = 1000
+
+// GetRuneProvenance walks the lineage of the rune outpoint backwards through transfers to the mints and premines that created its runes.
+// If runeId is not zero, only the lineage of that rune is walked. Inputs with an amount less than minAmount are not followed.
+// The walk stops at maxDepth transfers from the outpoint. Returns ErrUTXONotFound if the outpoint has no runes at blockHeight.
+func (u *Usecase) GetRuneProvenance(ctx context.Context, outPoint wire.OutPoint, blockHeight uint64, runeId runes.RuneId, maxDepth int, minAmount uint128.Uint128) (*entity.RuneProvenance, error) {
+	if err := u.ensureBlockHeightNotPruned(ctx, blockHeight); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	tx, err := u.runesDg.GetRuneTransaction(ctx, outPoint.Hash)
+	if err != nil {
+		if errors.Is(err, errs.NotFound) {
+			return nil, errors.WithStack(ErrUTXONotFound)
+		}
+		return nil, errors.Wrap(err, "error during GetRuneTransaction")
+	}
+	if tx.BlockHeight > blockHeight {
+		return nil, errors.WithStack(ErrUTXONotFound)
+	}
+	txs := map[chainhash.Hash]*entity.RuneTransaction{
+		tx.Hash: tx,
+	}
+
+	// tracked rune ids of each transaction to visit
+	frontier := make(map[chainhash.Hash]map[runes.RuneId]struct{})
+	for _, output := range tx.Outputs {
+		if output.Index != outPoint.Index || (runeId != runes.RuneId{} && output.RuneId != runeId) {
+			continue
+		}
+		if _, ok := frontier[outPoint.Hash]; !ok {
+			frontier[outPoint.Hash] = make(map[runes.RuneId]struct{})
+		}
+		frontier[outPoint.Hash][output.RuneId] = struct{}{}
+	}
+	if len(frontier) == 0 {
+		return nil, errors.WithStack(ErrUTXONotFound)
+	}
+
+	result := &entity.RuneProvenance{
+		OutPoint: outPoint,
+		Nodes:    make([]*entity.ProvenanceNode, 0),
+	}
+	nodes := make(map[chainhash.Hash]*entity.ProvenanceNode)
+	// a transaction can be reached again through another rune, so visits are tracked per rune to walk the lineage of every rune
+	visited := make(map[provenanceVisit]struct{})
+	for depth := 0; len(frontier) > 0; depth++ {
+		newTxHashes := lo.Filter(lo.Keys(frontier), func(txHash chainhash.Hash, _ int) bool {
+			_, ok := nodes[txHash]
+			return !ok
+		})
+		if len(nodes)+len(newTxHashes) > maxProvenanceNodes {
+			result.Truncated = true
+			break
+		}
+		// mark the whole frontier as visited first, so that a transaction in the frontier is not added to the next frontier again
+		for txHash, runeIds := range frontier {
+			for id := range runeIds {
+				visited[provenanceVisit{txHash: txHash, runeId: id}] = struct{}{}
+			}
+		}
+
+		missingTxHashes := lo.Filter(newTxHashes, func(txHash chainhash.Hash, _ int) bool {
+			_, ok := txs[txHash]
+			return !ok
+		})
+		fetchedTxs, err := u.runesDg.GetRuneTransactionsByHashes(ctx, missingTxHashes)
+		if err != nil {
+			return nil, errors.Wrap(err, "error during GetRuneTransactionsByHashes")
+		}
+		for txHash, tx := range fetchedTxs {
+			txs[txHash] = tx
+		}
+
+		newNodes := make([]*entity.ProvenanceNode, 0, len(newTxHashes))
+		nextFrontier := make(map[chainhash.Hash]map[runes.RuneId]struct{})
+		for txHash, runeIds := range frontier {
+			tx, ok := txs[txHash]
+			if !ok {
+				return nil, errs.WithPublicMessage(errors.Wrapf(errs.NotFound, "rune transaction %s in the lineage is not indexed", txHash), "")
+			}
+			// a transaction reached again for other runes is merged into its existing node
+			node, ok := nodes[txHash]
+			if !ok {
+				node = &entity.ProvenanceNode{
+					TxHash:      tx.Hash,
+					BlockHeight: tx.BlockHeight,
+					Index:       tx.Index,
+					Depth:       depth,
+					Inputs:      make([]*entity.TxInputOutput, 0),
+					Origins:     make([]*entity.ProvenanceOrigin, 0),
+				}
+				nodes[txHash] = node
+				newNodes = append(newNodes, node)
+			}
+			for id := range runeIds {
+				if amount, ok := tx.Mints[id]; ok {
+					node.Origins = append(node.Origins, &entity.ProvenanceOrigin{
+						Type:   entity.ProvenanceOriginTypeMint,
+						RuneId: id,
+						Amount: amount,
+					})
+				}
+			}
+			if premine := getAllocatedPremine(tx); !premine.IsZero() {
+				etchedRuneId := runes.RuneId{BlockHeight: tx.BlockHeight, TxIndex: tx.Index}
+				if _, ok := runeIds[etchedRuneId]; ok {
+					node.Origins = append(node.Origins, &entity.ProvenanceOrigin{
+						Type:   entity.ProvenanceOriginTypePremine,
+						RuneId: etchedRuneId,
+						Amount: premine,
+					})
+				}
+			}
+			for _, input := range tx.Inputs {
+				if _, ok := runeIds[input.RuneId]; !ok || input.Amount.Cmp(minAmount) < 0 {
+					continue
+				}
+				if depth >= maxDepth {
+					result.Truncated = true
+					continue
+				}
+				node.Inputs = append(node.Inputs, input)
+				if _, ok := visited[provenanceVisit{txHash: input.TxHash, runeId: input.RuneId}]; ok {
+					continue
+				}
+				if _, ok := nextFrontier[input.TxHash]; !ok {
+					nextFrontier[input.TxHash] = make(map[runes.RuneId]struct{})
+				}
+				nextFrontier[input.TxHash][input.RuneId] = struct{}{}
+			}
+			slices.SortFunc(node.Inputs, func(i1, i2 *entity.TxInputOutput) int {
+				if i1.Index != i2.Index {
+					return cmp.Compare(i1.Index, i2.Index)
+				}
+				return i1.RuneId.Cmp(i2.RuneId)
+			})
+			slices.SortFunc(node.Origins, func(o1, o2 *entity.ProvenanceOrigin) int {
+				return o1.RuneId.Cmp(o2.RuneId)
+			})
+		}
+		// sort by block height DESC, then index DESC
+		slices.SortFunc(newNodes, func(n1, n2 *entity.ProvenanceNode) int {
+			if n1.BlockHeight != n2.BlockHeight {
+				return cmp.Compare(n2.BlockHeight, n1.BlockHeight)
+			}
+			return cmp.Compare(n2.Index, n1.Index)
+		})
+		result.Nodes = append(result.Nodes, newNodes...)
+		frontier = nextFrontier
+	}
+	return result, nil
+}
+
+// provenanceVisit is a rune of a transaction whose lineage is walked.
+type provenanceVisit struct {
+	txHash chainhash.Hash
+	runeId runes.RuneId
+}
+
+// getAllocatedPremine returns the premine allocated by the etching in the transaction, or zero if the transaction has no premine.
+func getAllocatedPremine(tx *entity.RuneTransaction) uint128.Uint128 {
+	if !tx.RuneEtched || tx.Runestone == nil || tx.Runestone.Etching == nil || tx.Runestone.Cenotaph {
+		// premine of cenotaph etchings is burned
+		return uint128.Zero
+	}
+	return lo.FromPtr(tx.Runestone.Etching.Premine)
+}
@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	provenanceRune1 = runes.RuneId{BlockHeight: 840000, TxIndex: 1}
+	provenanceRune2 = runes.RuneId{BlockHeight: 840000, TxIndex: 2}
+)
+
+// provenanceTx is a rune transaction in a provenance test graph. Hashes are derived from names.
+type provenanceTx struct {
+	name        string
+	blockHeight uint64
+	inputs      []provenanceInput
+	outputs     []provenanceOutput
+	mints       map[runes.RuneId]uint64
+}
+
+type provenanceInput struct {
+	txName string
+	index  uint32
+	runeId runes.RuneId
+	amount uint64
+}
+
+type provenanceOutput struct {
+	index  uint32
+	runeId runes.RuneId
+	amount uint64
+}
+
+func provenanceTxHash(name string) chainhash.Hash {
+	return chainhash.DoubleHashH([]byte(name))
+}
+
+func newProvenanceDg(graph []provenanceTx) *fakeRunesDg {
+	txs := make(map[chainhash.Hash]*entity.RuneTransaction)
+	for _, tx := range graph {
+		runeTx := &entity.RuneTransaction{
+			Hash:        provenanceTxHash(tx.name),
+			BlockHeight: tx.blockHeight,
+			Mints:       make(map[runes.RuneId]uint128.Uint128),
+			Burns:       make(map[runes.RuneId]uint128.Uint128),
+		}
+		for i, input := range tx.inputs {
+			runeTx.Inputs = append(runeTx.Inputs, &entity.TxInputOutput{
+				RuneId:     input.runeId,
+				Amount:     uint128.From64(input.amount),
+				Index:      uint32(i),
+				TxHash:     provenanceTxHash(input.txName),
+				TxOutIndex: input.index,
+			})
+		}
+		for _, output := range tx.outputs {
+			runeTx.Outputs = append(runeTx.Outputs, &entity.TxInputOutput{
+				RuneId: output.runeId,
+				Amount: uint128.From64(output.amount),
+				Index:  output.index,
+				TxHash: runeTx.Hash,
+			})
+		}
+		for runeId, amount := range tx.mints {
+			runeTx.Mints[runeId] = uint128.From64(amount)
+		}
+		txs[runeTx.Hash] = runeTx
+	}
+	return &fakeRunesDg{txs: txs}
+}
+
+func TestGetRuneProvenance(t *testing.T) {
+	type expectedNode struct {
+		name    string
+		depth   int
+		inputs  []string // names of the previous transactions of the followed inputs
+		origins []runes.RuneId
+	}
+
+	type testcase struct {
+		name              string
+		graph             []provenanceTx
+		runeId            runes.RuneId
+		maxDepth          int
+		expectedNodes     []expectedNode
+		expectedTruncated bool
+	}
+
+	testcases := []testcase{
+		{
+			name: "diamond",
+			// a splits to b and c, which are merged in d
+			graph: []provenanceTx{
+				{name: "a", blockHeight: 840001, mints: map[runes.RuneId]uint64{provenanceRune1: 2}, outputs: []provenanceOutput{{0, provenanceRune1, 1}, {1, provenanceRune1, 1}}},
+				{name: "b", blockHeight: 840002, inputs: []provenanceInput{{"a", 0, provenanceRune1, 1}}, outputs: []provenanceOutput{{0, provenanceRune1, 1}}},
+				{name: "c", blockHeight: 840003, inputs: []provenanceInput{{"a", 1, provenanceRune1, 1}}, outputs: []provenanceOutput{{0, provenanceRune1, 1}}},
+				{name: "d", blockHeight: 840004, inputs: []provenanceInput{{"b", 0, provenanceRune1, 1}, {"c", 0, provenanceRune1, 1}}, outputs: []provenanceOutput{{0, provenanceRune1, 2}}},
+			},
+			maxDepth: 10,
+			expectedNodes: []expectedNode{
+				{name: "d", depth: 0, inputs: []string{"b", "c"}},
+				{name: "c", depth: 1, inputs: []string{"a"}},
+				{name: "b", depth: 1, inputs: []string{"a"}},
+				{name: "a", depth: 2, origins: []runes.RuneId{provenanceRune1}},
+			},
+		},
+		{
+			name: "transaction in two frontiers",
+			// a is spent by d directly, and through b
+			graph: []provenanceTx{
+				{name: "a", blockHeight: 840001, mints: map[runes.RuneId]uint64{provenanceRune1: 2}, outputs: []provenanceOutput{{0, provenanceRune1, 1}, {1, provenanceRune1, 1}}},
+				{name: "b", blockHeight: 840002, inputs: []provenanceInput{{"a", 0, provenanceRune1, 1}}, outputs: []provenanceOutput{{0, provenanceRune1, 1}}},
+				{name: "d", blockHeight: 840004, inputs: []provenanceInput{{"b", 0, provenanceRune1, 1}, {"a", 1, provenanceRune1, 1}}, outputs: []provenanceOutput{{0, provenanceRune1, 2}}},
+			},
+			maxDepth: 10,
+			expectedNodes: []expectedNode{
+				{name: "d", depth: 0, inputs: []string{"b", "a"}},
+				{name: "b", depth: 1, inputs: []string{"a"}},
+				{name: "a", depth: 1, origins: []runes.RuneId{provenanceRune1}},
+			},
+		},
+		{
+			name: "transaction reached again for another rune",
+			// a mints rune 1 and transfers rune 2 from x. d gets rune 1 from a directly, and rune 2 from a through b
+			graph: []provenanceTx{
+				{name: "x", blockHeight: 840000, mints: map[runes.RuneId]uint64{provenanceRune2: 2}, outputs: []provenanceOutput{{0, provenanceRune2, 2}}},
+				{name: "a", blockHeight: 840001, mints: map[runes.RuneId]uint64{provenanceRune1: 1}, inputs: []provenanceInput{{"x", 0, provenanceRune2, 2}}, outputs: []provenanceOutput{{0, provenanceRune1, 1}, {1, provenanceRune2, 2}}},
+				{name: "b", blockHeight: 840002, inputs: []provenanceInput{{"a", 1, provenanceRune2, 2}}, outputs: []provenanceOutput{{0, provenanceRune2, 2}}},
+				{name: "d", blockHeight: 840004, inputs: []provenanceInput{{"a", 0, provenanceRune1, 1}, {"b", 0, provenanceRune2, 2}}, outputs: []provenanceOutput{{0, provenanceRune1, 1}, {0, provenanceRune2, 2}}},
+			},
+			maxDepth: 10,
+			expectedNodes: []expectedNode{
+				{name: "d", depth: 0, inputs: []string{"a", "b"}},
+				{name: "b", depth: 1, inputs: []string{"a"}},
+				{name: "a", depth: 1, inputs: []string{"x"}, origins: []runes.RuneId{provenanceRune1}},
+				{name: "x", depth: 3, origins: []runes.RuneId{provenanceRune2}},
+			},
+		},
+		{
+			name: "filter by rune",
+			graph: []provenanceTx{
+				{name: "x", blockHeight: 840000, mints: map[runes.RuneId]uint64{provenanceRune2: 2}, outputs: []provenanceOutput{{0, provenanceRune2, 2}}},
+				{name: "a", blockHeight: 840001, mints: map[runes.RuneId]uint64{provenanceRune1: 1}, outputs: []provenanceOutput{{0, provenanceRune1, 1}}},
+				{name: "d", blockHeight: 840004, inputs: []provenanceInput{{"a", 0, provenanceRune1, 1}, {"x", 0, provenanceRune2, 2}}, outputs: []provenanceOutput{{0, provenanceRune1, 1}, {0, provenanceRune2, 2}}},
+			},
+			runeId:   provenanceRune2,
+			maxDepth: 10,
+			expectedNodes: []expectedNode{
+				{name: "d", depth: 0, inputs: []string{"x"}},
+				{name: "x", depth: 1, origins: []runes.RuneId{provenanceRune2}},
+			},
+		},
+		{
+			name: "depth limit",
+			graph: []provenanceTx{
+				{name: "a", blockHeight: 840001, mints: map[runes.RuneId]uint64{provenanceRune1: 1}, outputs: []provenanceOutput{{0, provenanceRune1, 1}}},
+				{name: "b", blockHeight: 840002, inputs: []provenanceInput{{"a", 0, provenanceRune1, 1}}, outputs: []provenanceOutput{{0, provenanceRune1, 1}}},
+				{name: "c", blockHeight: 840003, inputs: []provenanceInput{{"b", 0, provenanceRune1, 1}}, outputs: []provenanceOutput{{0, provenanceRune1, 1}}},
+			},
+			maxDepth: 1,
+			expectedNodes: []expectedNode{
+				{name: "c", depth: 0, inputs: []string{"b"}},
+				{name: "b", depth: 1},
+			},
+			expectedTruncated: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := New(newProvenanceDg(tc.graph), nil)
+			tracedTx := tc.graph[len(tc.graph)-1]
+			provenance, err := u.GetRuneProvenance(context.Background(), wire.OutPoint{Hash: provenanceTxHash(tracedTx.name), Index: 0}, tracedTx.blockHeight, tc.runeId, tc.maxDepth, uint128.Zero)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTruncated, provenance.Truncated)
+
+			if !assert.Len(t, provenance.Nodes, len(tc.expectedNodes)) {
+				return
+			}
+			for i, expected := range tc.expectedNodes {
+				node := provenance.Nodes[i]
+				assert.Equal(t, provenanceTxHash(expected.name), node.TxHash, "node %d should be %s", i, expected.name)
+				assert.Equal(t, expected.depth, node.Depth, "depth of %s", expected.name)
+
+				inputs := make([]chainhash.Hash, 0, len(node.Inputs))
+				for _, input := range node.Inputs {
+					inputs = append(inputs, input.TxHash)
+				}
+				expectedInputs := make([]chainhash.Hash, 0, len(expected.inputs))
+				for _, name := range expected.inputs {
+					expectedInputs = append(expectedInputs, provenanceTxHash(name))
+				}
+				assert.Equal(t, expectedInputs, inputs, "inputs of %s", expected.name)
+
+				origins := make([]runes.RuneId, 0, len(node.Origins))
+				for _, origin := range node.Origins {
+					origins = append(origins, origin.RuneId)
+				}
+				assert.Equal(t, append([]runes.RuneId{}, expected.origins...), origins, "origins of %s", expected.name)
+			}
+		})
+	}
+
+	t.Run("node limit", func(t *testing.T) {
+		graph := make([]provenanceTx, 0, maxProvenanceNodes+1)
+		traced := provenanceTx{name: "traced", blockHeight: 840002, outputs: []provenanceOutput{{0, provenanceRune1, maxProvenanceNodes}}}
+		for i := 0; i < maxProvenanceNodes; i++ {
+			name := string(rune('a'+i%26)) + string(rune(i))
+			graph = append(graph, provenanceTx{name: name, blockHeight: 840001, mints: map[runes.RuneId]uint64{provenanceRune1: 1}, outputs: []provenanceOutput{{0, provenanceRune1, 1}}})
+			traced.inputs = append(traced.inputs, provenanceInput{name, 0, provenanceRune1, 1})
+		}
+		graph = append(graph, traced)
+
+		u := New(newProvenanceDg(graph), nil)
+		provenance, err := u.GetRuneProvenance(context.Background(), wire.OutPoint{Hash: provenanceTxHash("traced"), Index: 0}, 840002, runes.RuneId{}, 10, uint128.Zero)
+		assert.NoError(t, err)
+		assert.True(t, provenance.Truncated)
+		assert.Len(t, provenance.Nodes, 1)
+	})
+
+	t.Run("missing transaction", func(t *testing.T) {
+		graph := []provenanceTx{
+			{name: "b", blockHeight: 840002, inputs: []provenanceInput{{"a", 0, provenanceRune1, 1}}, outputs: []provenanceOutput{{0, provenanceRune1, 1}}},
+		}
+		u := New(newProvenanceDg(graph), nil)
+		_, err := u.GetRuneProvenance(context.Background(), wire.OutPoint{Hash: provenanceTxHash("b"), Index: 0}, 840002, runes.RuneId{}, 10, uint128.Zero)
+		assert.ErrorIs(t, err, errs.NotFound)
+		assert.ErrorAs(t, err, new(*errs.PublicError))
+	})
+
+	t.Run("outpoint after block height", func(t *testing.T) {
+		graph := []provenanceTx{
+			{name: "a", blockHeight: 840001, mints: map[runes.RuneId]uint64{provenanceRune1: 1}, outputs: []provenanceOutput{{0, provenanceRune1, 1}}},
+		}
+		u := New(newProvenanceDg(graph), nil)
+		_, err := u.GetRuneProvenance(context.Background(), wire.OutPoint{Hash: provenanceTxHash("a"), Index: 0}, 840000, runes.RuneId{}, 10, uint128.Zero)
+		assert.ErrorIs(t, err, ErrUTXONotFound)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
 	"github.com/gaze-network/indexer-network/modules/runes/runes"
 )
 
@@ -47,18 +48,32 @@ func (u *Usecase) GetRuneEntryByRuneIdAndHeightBatch(ctx context.Context, runeId
 	return runeEntries, nil
 }
 
-func (u *Usecase) GetRuneEntries(ctx context.Context, search string, blockHeight uint64, limit, offset int32) ([]*runes.RuneEntry, error) {
-	entries, err := u.runesDg.GetRuneEntries(ctx, search, blockHeight, limit, offset)
+func (u *Usecase) GetRuneEntries(ctx context.Context, search string, blockHeight uint64, cursor *entity.RuneEntryCursor, limit, offset int32) ([]*runes.RuneEntry, error) {
+	entries, err := u.runesDg.GetRuneEntries(ctx, search, blockHeight, cursor, limit, offset)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to listing rune entries")
 	}
 	return entries, nil
 }
 
-func (u *Usecase) GetOngoingRuneEntries(ctx context.Context, search string, blockHeight uint64, limit, offset int32) ([]*runes.RuneEntry, error) {
-	entries, err := u.runesDg.GetOngoingRuneEntries(ctx, search, blockHeight, limit, offset)
+func (u *Usecase) GetOngoingRuneEntries(ctx context.Context, search string, blockHeight uint64, cursor *entity.RuneEntryCursor, limit, offset int32) ([]*runes.RuneEntry, error) {
+	entries, err := u.runesDg.GetOngoingRuneEntries(ctx, search, blockHeight, cursor, limit, offset)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to listing rune entries")
 	}
 	return entries, nil
 }
+
+// GetRuneEntryStateHistory returns the state of the rune entry every interval blocks from fromBlock to toBlock.
+// Use limit = -1 as no limit.
+func (u *Usecase) GetRuneEntryStateHistory(ctx context.Context, runeId runes.RuneId, fromBlock, toBlock uint64, interval uint64, limit, offset int32) ([]*entity.RuneEntryState, error) {
+	// holders count is computed from balances, which are pruned
+	if err := u.ensureBlockHeightNotPruned(ctx, fromBlock); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	states, err := u.runesDg.GetRuneEntryStateHistory(ctx, runeId, fromBlock, toBlock, interval, limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get rune entry state history")
+	}
+	return states, nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -10,67 +11,116 @@ import (
 	"github.com/gaze-network/indexer-network/common/errs"
 	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
 	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/samber/lo"
 )
 
-func (u *Usecase) GetRunesUTXOsByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.RunesUTXOWithSats, error) {
-	balances, err := u.runesDg.GetRunesUTXOsByPkScript(ctx, pkScript, blockHeight, limit, offset)
+func (u *Usecase) GetRunesUTXOsByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, cursor *wire.OutPoint, limit int32, offset int32) ([]*entity.RunesUTXOWithSats, error) {
+	if err := u.ensureBlockHeightNotPruned(ctx, blockHeight); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	utxos, err := u.runesDg.GetRunesUTXOsByPkScript(ctx, pkScript, blockHeight, cursor, limit, offset)
 	if err != nil {
-		return nil, errors.Wrap(err, "error during GetBalancesByPkScript")
+		return nil, errors.Wrap(err, "error during GetRunesUTXOsByPkScript")
 	}
+	result, err := u.withUTXOSats(ctx, utxos)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return result, nil
+}
 
-	result := make([]*entity.RunesUTXOWithSats, 0, len(balances))
-	for _, balance := range balances {
-		tx, err := u.bitcoinClient.GetRawTransactionByTxHash(ctx, balance.OutPoint.Hash)
-		if err != nil {
-			if strings.Contains(err.Error(), "No such mempool or blockchain transaction.") {
-				return nil, errors.WithStack(ErrUTXONotFound)
+func (u *Usecase) GetRunesUTXOsByRuneIdAndPkScript(ctx context.Context, runeId runes.RuneId, pkScript []byte, blockHeight uint64, cursor *wire.OutPoint, limit int32, offset int32) ([]*entity.RunesUTXOWithSats, error) {
+	if err := u.ensureBlockHeightNotPruned(ctx, blockHeight); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	utxos, err := u.runesDg.GetRunesUTXOsByRuneIdAndPkScript(ctx, runeId, pkScript, blockHeight, cursor, limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "error during GetRunesUTXOsByRuneIdAndPkScript")
+	}
+	result, err := u.withUTXOSats(ctx, utxos)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return result, nil
+}
+
+// withUTXOSats returns the UTXOs with their recorded values. Values that were not recorded are fetched from the bitcoin node.
+func (u *Usecase) withUTXOSats(ctx context.Context, utxos []*entity.RunesUTXOWithValue) ([]*entity.RunesUTXOWithSats, error) {
+	// outputs of the same transaction share a single request
+	txOuts := make(map[chainhash.Hash][]*wire.TxOut)
+	result := make([]*entity.RunesUTXOWithSats, 0, len(utxos))
+	for _, utxo := range utxos {
+		if utxo.Value != nil {
+			result = append(result, &entity.RunesUTXOWithSats{
+				RunesUTXO: utxo.RunesUTXO,
+				Sats:      *utxo.Value,
+			})
+			continue
+		}
+		outs, ok := txOuts[utxo.OutPoint.Hash]
+		if !ok {
+			tx, err := u.bitcoinClient.GetRawTransactionByTxHash(ctx, utxo.OutPoint.Hash)
+			if err != nil {
+				if strings.Contains(err.Error(), "No such mempool or blockchain transaction.") {
+					return nil, errors.WithStack(ErrUTXONotFound)
+				}
+				return nil, errors.WithStack(err)
 			}
-			return nil, errors.WithStack(err)
+			outs = tx.TxOut
+			txOuts[utxo.OutPoint.Hash] = outs
+		}
+		if len(outs) <= int(utxo.OutPoint.Index) {
+			return nil, errors.Wrapf(errs.InvalidState, "output index %d is out of range of transaction %s", utxo.OutPoint.Index, utxo.OutPoint.Hash)
 		}
-
 		result = append(result, &entity.RunesUTXOWithSats{
-			RunesUTXO: entity.RunesUTXO{
-				PkScript:     balance.PkScript,
-				OutPoint:     balance.OutPoint,
-				RuneBalances: balance.RuneBalances,
-			},
-			Sats: tx.TxOut[balance.OutPoint.Index].Value,
+			RunesUTXO: utxo.RunesUTXO,
+			Sats:      outs[utxo.OutPoint.Index].Value,
 		})
 	}
-
 	return result, nil
 }
 
-func (u *Usecase) GetRunesUTXOsByRuneIdAndPkScript(ctx context.Context, runeId runes.RuneId, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.RunesUTXOWithSats, error) {
-	balances, err := u.runesDg.GetRunesUTXOsByRuneIdAndPkScript(ctx, runeId, pkScript, blockHeight, limit, offset)
+// GetUTXOsOutputByLocation returns the output and its rune balances as of blockHeight. Returns ErrUTXONotFound if the output was created by a rune transaction after blockHeight.
+func (u *Usecase) GetUTXOsOutputByLocation(ctx context.Context, txHash chainhash.Hash, outputIdx uint32, blockHeight uint64) (*entity.RunesUTXOWithSats, error) {
+	if err := u.ensureBlockHeightNotPruned(ctx, blockHeight); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	outPoint := wire.OutPoint{
+		Hash:  txHash,
+		Index: outputIdx,
+	}
+	balances, err := u.runesDg.GetRunesBalancesAtOutPoint(ctx, outPoint)
 	if err != nil {
-		return nil, errors.Wrap(err, "error during GetBalancesByPkScript")
+		return nil, errors.Wrap(err, "error during GetRunesBalancesAtOutPoint")
 	}
-
-	result := make([]*entity.RunesUTXOWithSats, 0, len(balances))
-	for _, balance := range balances {
-		tx, err := u.bitcoinClient.GetRawTransactionByTxHash(ctx, balance.OutPoint.Hash)
-		if err != nil {
-			if strings.Contains(err.Error(), "No such mempool or blockchain transaction.") {
-				return nil, errors.WithStack(ErrUTXONotFound)
-			}
-			return nil, errors.WithStack(err)
+	// all balances at the same outpoint are created at the same height
+	if len(balances) > 0 && lo.Values(balances)[0].BlockHeight > blockHeight {
+		return nil, errors.WithStack(ErrUTXONotFound)
+	}
+	// use the recorded value if available, otherwise fallback to the bitcoin node
+	if len(balances) > 0 && lo.EveryBy(lo.Values(balances), func(balance *entity.OutPointBalance) bool { return balance.Value != nil }) {
+		runeBalances := make([]entity.RunesUTXOBalance, 0, len(balances))
+		for _, balance := range balances {
+			runeBalances = append(runeBalances, entity.RunesUTXOBalance{
+				RuneId: balance.RuneId,
+				Amount: balance.Amount,
+			})
 		}
-
-		result = append(result, &entity.RunesUTXOWithSats{
+		slices.SortFunc(runeBalances, func(b1, b2 entity.RunesUTXOBalance) int {
+			return b1.RuneId.Cmp(b2.RuneId)
+		})
+		// all balances at the same outpoint have the same pkscript and value
+		anyBalance := lo.Values(balances)[0]
+		return &entity.RunesUTXOWithSats{
 			RunesUTXO: entity.RunesUTXO{
-				PkScript:     balance.PkScript,
-				OutPoint:     balance.OutPoint,
-				RuneBalances: balance.RuneBalances,
+				PkScript:     anyBalance.PkScript,
+				OutPoint:     outPoint,
+				RuneBalances: runeBalances,
 			},
-			Sats: tx.TxOut[balance.OutPoint.Index].Value,
-		})
+			Sats: *anyBalance.Value,
+		}, nil
 	}
 
-	return result, nil
-}
-
-func (u *Usecase) GetUTXOsOutputByLocation(ctx context.Context, txHash chainhash.Hash, outputIdx uint32) (*entity.RunesUTXOWithSats, error) {
 	tx, err := u.bitcoinClient.GetRawTransactionByTxHash(ctx, txHash)
 	if err != nil {
 		if strings.Contains(err.Error(), "No such mempool or blockchain transaction.") {
@@ -103,6 +153,9 @@ func (u *Usecase) GetUTXOsOutputByLocation(ctx context.Context, txHash chainhash
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if transaction.BlockHeight > blockHeight {
+		return nil, errors.WithStack(ErrUTXONotFound)
+	}
 
 	runeBalance := make([]entity.RunesUTXOBalance, 0, len(transaction.Outputs))
 	for _, output := range transaction.Outputs {
@@ -117,3 +170,44 @@ func (u *Usecase) GetUTXOsOutputByLocation(ctx context.Context, txHash chainhash
 	rune.RuneBalances = runeBalance
 	return rune, nil
 }
+
+// GetOutPointSpendingTransaction returns the rune balances at the outpoint and the rune transaction that spent it, as of blockHeight.
+// The returned transaction is nil if the outpoint is unspent at blockHeight. Returns ErrUTXONotFound if the outpoint has no rune balances at blockHeight.
+func (u *Usecase) GetOutPointSpendingTransaction(ctx context.Context, outPoint wire.OutPoint, blockHeight uint64) ([]*entity.OutPointBalance, *entity.RuneTransaction, error) {
+	if err := u.ensureBlockHeightNotPruned(ctx, blockHeight); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	balancesMap, err := u.runesDg.GetRunesBalancesAtOutPoint(ctx, outPoint)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error during GetRunesBalancesAtOutPoint")
+	}
+	if len(balancesMap) == 0 {
+		return nil, nil, errors.WithStack(ErrUTXONotFound)
+	}
+	balances := lo.Values(balancesMap)
+	slices.SortFunc(balances, func(b1, b2 *entity.OutPointBalance) int {
+		return b1.RuneId.Cmp(b2.RuneId)
+	})
+	if balances[0].BlockHeight > blockHeight {
+		return nil, nil, errors.WithStack(ErrUTXONotFound)
+	}
+	// the outpoint was still unspent at blockHeight
+	if spentHeight := balances[0].SpentHeight; spentHeight != nil && *spentHeight > blockHeight {
+		for _, balance := range balances {
+			balance.SpentHeight = nil
+			balance.SpentTxHash = nil
+			balance.SpentTxInputIndex = nil
+		}
+	}
+
+	// all balances at the same outpoint are spent by the same input
+	spentTxHash := balances[0].SpentTxHash
+	if spentTxHash == nil {
+		return balances, nil, nil
+	}
+	tx, err := u.runesDg.GetRuneTransaction(ctx, *spentTxHash)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error during GetRuneTransaction")
+	}
+	return balances, tx, nil
+}
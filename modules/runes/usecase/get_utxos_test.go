@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/pkg/btcclient"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBitcoinClient is a btcclient.Contract for tests. Calling a method that is not implemented panics.
+type fakeBitcoinClient struct {
+	btcclient.Contract
+
+	txs      map[chainhash.Hash]*wire.MsgTx
+	getCalls int
+}
+
+func (c *fakeBitcoinClient) GetRawTransactionByTxHash(ctx context.Context, txHash chainhash.Hash) (*wire.MsgTx, error) {
+	c.getCalls++
+	tx, ok := c.txs[txHash]
+	if !ok {
+		return nil, errors.New("-5: No such mempool or blockchain transaction. Use gettransaction for wallet transactions.")
+	}
+	return tx, nil
+}
+
+func TestGetRunesUTXOsByPkScript(t *testing.T) {
+	recordedTxHash := chainhash.Hash{1}
+	unrecordedTxHash := chainhash.Hash{2}
+	newUTXO := func(txHash chainhash.Hash, index uint32, value *int64) *entity.RunesUTXOWithValue {
+		return &entity.RunesUTXOWithValue{
+			RunesUTXO: entity.RunesUTXO{OutPoint: wire.OutPoint{Hash: txHash, Index: index}},
+			Value:     value,
+		}
+	}
+	unrecordedTx := wire.NewMsgTx(wire.TxVersion)
+	unrecordedTx.AddTxOut(wire.NewTxOut(330, nil))
+	unrecordedTx.AddTxOut(wire.NewTxOut(546, nil))
+
+	t.Run("values fetched from the node when not recorded", func(t *testing.T) {
+		client := &fakeBitcoinClient{txs: map[chainhash.Hash]*wire.MsgTx{unrecordedTxHash: unrecordedTx}}
+		u := New(&fakeRunesDg{utxos: []*entity.RunesUTXOWithValue{
+			newUTXO(recordedTxHash, 0, lo.ToPtr[int64](10000)),
+			newUTXO(unrecordedTxHash, 0, nil),
+			newUTXO(unrecordedTxHash, 1, nil),
+		}}, client)
+		utxos, err := u.GetRunesUTXOsByPkScript(context.Background(), []byte{0x01}, 100, nil, -1, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{10000, 330, 546}, lo.Map(utxos, func(utxo *entity.RunesUTXOWithSats, _ int) int64 { return utxo.Sats }))
+		assert.Equal(t, wire.OutPoint{Hash: unrecordedTxHash, Index: 1}, utxos[2].OutPoint)
+		// outputs of the same transaction are fetched once
+		assert.Equal(t, 1, client.getCalls)
+	})
+	t.Run("recorded values", func(t *testing.T) {
+		client := &fakeBitcoinClient{}
+		u := New(&fakeRunesDg{utxos: []*entity.RunesUTXOWithValue{
+			newUTXO(recordedTxHash, 0, lo.ToPtr[int64](10000)),
+		}}, client)
+		utxos, err := u.GetRunesUTXOsByPkScript(context.Background(), []byte{0x01}, 100, nil, -1, 0)
+		assert.NoError(t, err)
+		assert.Len(t, utxos, 1)
+		assert.Equal(t, int64(10000), utxos[0].Sats)
+		assert.Zero(t, client.getCalls)
+	})
+	t.Run("transaction not found", func(t *testing.T) {
+		u := New(&fakeRunesDg{utxos: []*entity.RunesUTXOWithValue{
+			newUTXO(unrecordedTxHash, 0, nil),
+		}}, &fakeBitcoinClient{})
+		_, err := u.GetRunesUTXOsByPkScript(context.Background(), []byte{0x01}, 100, nil, -1, 0)
+		assert.ErrorIs(t, err, ErrUTXONotFound)
+	})
+}
@@ -1,18 +1,81 @@
 package usecase
 
 import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
 	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
 	"github.com/gaze-network/indexer-network/pkg/btcclient"
 )
 
+// prunedHeightTTL is how long the pruned height is cached before it is checked again.
+// The pruner may run while the pruned height is cached, so the cached pruned height is only used with a margin of constants.PruneInterval blocks.
+const prunedHeightTTL = time.Minute
+
 type Usecase struct {
-	runesDg       datagateway.RunesDataGateway
+	runesDg       datagateway.RunesReaderDataGateway
 	bitcoinClient btcclient.Contract
+
+	prunedHeightMu        sync.Mutex
+	prunedHeight          uint64
+	prunedHeightCheckedAt time.Time
 }
 
-func New(runesDg datagateway.RunesDataGateway, bitcoinClient btcclient.Contract) *Usecase {
+func New(runesDg datagateway.RunesReaderDataGateway, bitcoinClient btcclient.Contract) *Usecase {
 	return &Usecase{
 		runesDg:       runesDg,
 		bitcoinClient: bitcoinClient,
 	}
 }
+
+// getPrunedHeight returns the lowest block height that is safe to query, and refreshes the cached pruned height if it is older than prunedHeightTTL.
+// The pruner advances the pruned height by constants.PruneInterval blocks at a time, so heights within one pruning run of the cached pruned height are treated as pruned.
+// The pruned height isn't cached until the first pruning run, because the first run advances it from 0 to the pruned height of the keep_blocks setting at once.
+func (u *Usecase) getPrunedHeight(ctx context.Context) (uint64, error) {
+	u.prunedHeightMu.Lock()
+	prunedHeight, checkedAt := u.prunedHeight, u.prunedHeightCheckedAt
+	u.prunedHeightMu.Unlock()
+	if prunedHeight > 0 && time.Since(checkedAt) < prunedHeightTTL {
+		return prunedHeight + constants.PruneInterval, nil
+	}
+
+	// don't hold the lock across the query, concurrent refreshes are harmless
+	prunedHeight, err := u.runesDg.GetPrunedHeight(ctx)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if prunedHeight == 0 {
+		return 0, nil
+	}
+	u.prunedHeightMu.Lock()
+	defer u.prunedHeightMu.Unlock()
+	// the pruned height never decreases
+	u.prunedHeight = max(u.prunedHeight, prunedHeight)
+	u.prunedHeightCheckedAt = time.Now()
+	return u.prunedHeight + constants.PruneInterval, nil
+}
+
+// GetPrunedHeight returns the lowest block height that can be queried, or 0 if history has never been pruned.
+func (u *Usecase) GetPrunedHeight(ctx context.Context) (uint64, error) {
+	prunedHeight, err := u.getPrunedHeight(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get pruned height")
+	}
+	return prunedHeight, nil
+}
+
+// ensureBlockHeightNotPruned returns ErrBlockHeightPruned if history at the given block height has been pruned.
+func (u *Usecase) ensureBlockHeightNotPruned(ctx context.Context, blockHeight uint64) error {
+	prunedHeight, err := u.getPrunedHeight(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pruned height")
+	}
+	if blockHeight < prunedHeight {
+		return errs.WithPublicMessage(errors.Wrapf(ErrBlockHeightPruned, "earliest available block height is %d, got %d", prunedHeight, blockHeight), "")
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/cockroachdb/errors"
+	"github.com/gaze-network/indexer-network/common/errs"
+	"github.com/gaze-network/indexer-network/modules/runes/constants"
+	"github.com/gaze-network/indexer-network/modules/runes/datagateway"
+	"github.com/gaze-network/indexer-network/modules/runes/internal/entity"
+	"github.com/gaze-network/indexer-network/modules/runes/runes"
+	"github.com/gaze-network/uint128"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRunesDg is a RunesReaderDataGateway for tests. Calling a method that is not implemented panics.
+type fakeRunesDg struct {
+	datagateway.RunesReaderDataGateway
+
+	prunedHeight      uint64
+	prunedHeightCalls int
+	latestHeight      uint64
+	currentBalances   []*entity.Balance
+	historyBalances   []*entity.Balance
+	txs               map[chainhash.Hash]*entity.RuneTransaction
+	runeEntry         *runes.RuneEntry
+	holders           []*entity.Balance
+	indexedBlock      *entity.IndexedBlock
+	getRuneTxsCalls   int
+	utxos             []*entity.RunesUTXOWithValue
+}
+
+func (d *fakeRunesDg) GetPrunedHeight(ctx context.Context) (uint64, error) {
+	d.prunedHeightCalls++
+	return d.prunedHeight, nil
+}
+
+func (d *fakeRunesDg) GetCurrentBalancesByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error) {
+	if blockHeight != d.latestHeight {
+		return nil, errors.WithStack(errs.InvalidState)
+	}
+	return d.currentBalances, nil
+}
+
+func (d *fakeRunesDg) GetBalancesByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, limit int32, offset int32) ([]*entity.Balance, error) {
+	return d.historyBalances, nil
+}
+
+func (d *fakeRunesDg) GetRuneTransaction(ctx context.Context, txHash chainhash.Hash) (*entity.RuneTransaction, error) {
+	tx, ok := d.txs[txHash]
+	if !ok {
+		return nil, errors.WithStack(errs.NotFound)
+	}
+	return tx, nil
+}
+
+func (d *fakeRunesDg) GetRuneTransactionsByHashes(ctx context.Context, txHashes []chainhash.Hash) (map[chainhash.Hash]*entity.RuneTransaction, error) {
+	result := make(map[chainhash.Hash]*entity.RuneTransaction)
+	for _, txHash := range txHashes {
+		if tx, ok := d.txs[txHash]; ok {
+			result[txHash] = tx
+		}
+	}
+	return result, nil
+}
+
+func (d *fakeRunesDg) GetIndexedBlockByHeight(ctx context.Context, height int64) (*entity.IndexedBlock, error) {
+	if d.indexedBlock == nil || d.indexedBlock.Height != height {
+		return nil, errors.WithStack(errs.NotFound)
+	}
+	return d.indexedBlock, nil
+}
+
+// GetRuneTransactions returns the transactions in the block range, newest first. Filtering by pkScript and runeId is not supported.
+func (d *fakeRunesDg) GetRuneTransactions(ctx context.Context, pkScript []byte, runeId runes.RuneId, fromBlock, toBlock uint64, cursor *entity.RuneTransactionCursor, limit int32, offset int32) ([]*entity.RuneTransaction, error) {
+	d.getRuneTxsCalls++
+	txs := make([]*entity.RuneTransaction, 0)
+	for _, tx := range d.txs {
+		if tx.BlockHeight < fromBlock || tx.BlockHeight > toBlock {
+			continue
+		}
+		if cursor != nil && (tx.BlockHeight > cursor.BlockHeight || (tx.BlockHeight == cursor.BlockHeight && tx.Index >= cursor.Index)) {
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	slices.SortFunc(txs, func(tx1, tx2 *entity.RuneTransaction) int {
+		return cmp.Or(cmp.Compare(tx2.BlockHeight, tx1.BlockHeight), cmp.Compare(tx2.Index, tx1.Index))
+	})
+	txs = txs[min(int(offset), len(txs)):]
+	return txs[:min(int(limit), len(txs))], nil
+}
+
+func (d *fakeRunesDg) GetRuneEntryByRuneIdAndHeight(ctx context.Context, runeId runes.RuneId, blockHeight uint64) (*runes.RuneEntry, error) {
+	if d.runeEntry == nil {
+		return nil, errors.WithStack(errs.NotFound)
+	}
+	return d.runeEntry, nil
+}
+
+func (d *fakeRunesDg) GetBalancesByRuneId(ctx context.Context, runeId runes.RuneId, blockHeight uint64, cursor *entity.BalanceCursor, limit int32, offset int32) ([]*entity.Balance, error) {
+	if cursor != nil {
+		return nil, nil
+	}
+	return d.holders, nil
+}
+
+func (d *fakeRunesDg) GetRunesUTXOsByPkScript(ctx context.Context, pkScript []byte, blockHeight uint64, cursor *wire.OutPoint, limit int32, offset int32) ([]*entity.RunesUTXOWithValue, error) {
+	return d.utxos, nil
+}
+
+func TestEnsureBlockHeightNotPruned(t *testing.T) {
+	t.Run("pruned", func(t *testing.T) {
+		dg := &fakeRunesDg{prunedHeight: 1000}
+		u := New(dg, nil)
+		ctx := context.Background()
+
+		// the pruner may have run since the pruned height was cached
+		assert.NoError(t, u.ensureBlockHeightNotPruned(ctx, 1000+constants.PruneInterval))
+		assert.ErrorIs(t, u.ensureBlockHeightNotPruned(ctx, 1000+constants.PruneInterval-1), ErrBlockHeightPruned)
+		assert.ErrorIs(t, u.ensureBlockHeightNotPruned(ctx, 999), ErrBlockHeightPruned)
+		assert.Equal(t, 1, dg.prunedHeightCalls, "pruned height should be cached")
+
+		// a stale cache is refreshed
+		dg.prunedHeight = 2000
+		assert.NoError(t, u.ensureBlockHeightNotPruned(ctx, 1500))
+		u.prunedHeightCheckedAt = time.Now().Add(-prunedHeightTTL)
+		assert.ErrorIs(t, u.ensureBlockHeightNotPruned(ctx, 1500), ErrBlockHeightPruned)
+		assert.Equal(t, 2, dg.prunedHeightCalls)
+	})
+	t.Run("never pruned", func(t *testing.T) {
+		dg := &fakeRunesDg{}
+		u := New(dg, nil)
+		ctx := context.Background()
+
+		assert.NoError(t, u.ensureBlockHeightNotPruned(ctx, 0))
+		// the first pruning run advances the pruned height at once, so it isn't cached
+		dg.prunedHeight = 1000
+		assert.ErrorIs(t, u.ensureBlockHeightNotPruned(ctx, 999), ErrBlockHeightPruned)
+		assert.Equal(t, 2, dg.prunedHeightCalls)
+	})
+}
+
+func TestGetBalancesByPkScript(t *testing.T) {
+	current := []*entity.Balance{{Amount: uint128.From64(2), BlockHeight: 101}}
+	history := []*entity.Balance{{Amount: uint128.From64(1), BlockHeight: 100}}
+
+	type testcase struct {
+		name         string
+		blockHeight  uint64
+		latest       bool
+		latestHeight uint64
+		expected     []*entity.Balance
+	}
+
+	testcases := []testcase{
+		{
+			name:         "latest",
+			blockHeight:  101,
+			latest:       true,
+			latestHeight: 101,
+			expected:     current,
+		},
+		{
+			name:         "not latest",
+			blockHeight:  101,
+			latest:       false,
+			latestHeight: 101,
+			expected:     history,
+		},
+		{
+			name:         "block indexed after resolving latest",
+			blockHeight:  100,
+			latest:       true,
+			latestHeight: 101,
+			expected:     history,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := New(&fakeRunesDg{
+				latestHeight:    tc.latestHeight,
+				currentBalances: current,
+				historyBalances: history,
+			}, nil)
+			balances, err := u.GetBalancesByPkScript(context.Background(), []byte{0x01}, tc.blockHeight, tc.latest, -1, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, balances)
+		})
+	}
+}
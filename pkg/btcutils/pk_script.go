@@ -34,6 +34,17 @@ func GetAddressTypeFromPkScript(pkScript []byte, defaultNet ...*chaincfg.Params)
 	return scriptClass, nil
 }
 
+// EncodeAddressFromPkScript returns the encoded address of the given pubkey script/script pubkey.
+// It returns an empty string if the script is not standard or doesn't have exactly one address, e.g. OP_RETURN or multi-signature scripts.
+func EncodeAddressFromPkScript(pkScript []byte, defaultNet ...*chaincfg.Params) string {
+	net := utils.DefaultOptional(defaultNet, &chaincfg.MainNetParams)
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, net)
+	if err != nil || len(addrs) != 1 {
+		return ""
+	}
+	return addrs[0].EncodeAddress()
+}
+
 // ExtractAddressFromPkScript extracts address from the given pubkey script/script pubkey.
 // multi-signature script not supported
 func ExtractAddressFromPkScript(pkScript []byte, defaultNet ...*chaincfg.Params) (Address, error) {
@@ -215,3 +215,57 @@ func TestGetAddressTypeFromPkScript(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeAddressFromPkScript(t *testing.T) {
+	type Spec struct {
+		PubkeyScript string
+		DefaultNet   *chaincfg.Params
+
+		ExpectedAddress string
+	}
+
+	specs := []Spec{
+		{
+			PubkeyScript:    "001469bca5dfd9888e2c51cd1b4cecc1c9c4f25f5432",
+			DefaultNet:      &chaincfg.MainNetParams,
+			ExpectedAddress: "bc1qdx72th7e3z8zc5wdrdxweswfcne974pjneyjln",
+		},
+		{
+			PubkeyScript:    "51204b5e272a4ef8675362c11c5a60afc67a1da7657c4accc1e2ad5a808997a29739",
+			DefaultNet:      &chaincfg.MainNetParams,
+			ExpectedAddress: "bc1pfd0zw2jwlpn4xckpr3dxpt7x0gw6wetuftxvrc4dt2qgn9azjuus65fug6",
+		},
+		{
+			PubkeyScript:    "76a914cecb25b53809991c7beef2d27bc2be49e78c684388ac",
+			DefaultNet:      &chaincfg.MainNetParams,
+			ExpectedAddress: "1KrRZSShVkdc8J71CtY4wdw46Rx3BRLKyH",
+		},
+		{
+			PubkeyScript:    "00144850d32c3ff585403790507793125d174a5c28e0",
+			DefaultNet:      &chaincfg.TestNet3Params,
+			ExpectedAddress: "tb1qfpgdxtpl7kz5qdus2pmexyjaza99c28qd6ltey",
+		},
+		{
+			// OP_RETURN
+			PubkeyScript:    "6a5d0614c0a2331441",
+			DefaultNet:      &chaincfg.MainNetParams,
+			ExpectedAddress: "",
+		},
+		{
+			// empty script
+			PubkeyScript:    "",
+			DefaultNet:      &chaincfg.MainNetParams,
+			ExpectedAddress: "",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(fmt.Sprintf("pkscript:%s", spec.PubkeyScript), func(t *testing.T) {
+			pkScript, err := hex.DecodeString(spec.PubkeyScript)
+			if err != nil {
+				t.Fatalf("can't decode pkscript %s, Reason: %s", spec.PubkeyScript, err)
+			}
+			assert.Equal(t, spec.ExpectedAddress, btcutils.EncodeAddressFromPkScript(pkScript, spec.DefaultNet))
+		})
+	}
+}